@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"benchy/internal/accounts"
+	"benchy/internal/output"
+	"benchy/internal/scenarios"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/spf13/cobra"
+)
+
+var (
+	accountsCount    int
+	accountsMnemonic string
+)
+
+var accountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Derive benchmark accounts from a mnemonic and inspect them",
+}
+
+var accountsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Derive new accounts from the mnemonic",
+	Long: `Derive --count more accounts at m/44'/60'/0'/0/<index> from the mnemonic
+and record them in ` + accounts.File + `, next to the mnemonic.
+
+The first call uses --mnemonic, or the well-known development mnemonic
+"` + accounts.DefaultMnemonic + `";
+later calls continue its sequence. Fund the accounts with ` + "`benchy faucet`" + `.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := output.FormatText
+		if accountsCount <= 0 {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, fmt.Errorf("--count must be positive")))
+		}
+		store, err := accounts.Load(accounts.File)
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+		if store == nil {
+			mnemonic := accountsMnemonic
+			if mnemonic == "" {
+				mnemonic = accounts.DefaultMnemonic
+			}
+			if store, err = accounts.New(mnemonic); err != nil {
+				exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+			}
+		} else if accountsMnemonic != "" && accountsMnemonic != store.Mnemonic {
+			exitWithError(format, output.NewError(output.CodeConflict,
+				fmt.Errorf("%s holds accounts of another mnemonic, remove it to change mnemonic", accounts.File)))
+		}
+
+		added, err := store.Derive(accountsCount)
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+		if err := store.Save(accounts.File); err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+
+		fmt.Printf("👛 Derived %d accounts, %d in %s\n", len(added), len(store.Accounts), accounts.File)
+		for _, account := range added {
+			fmt.Printf("   %-5d %s\n", account.Index, account.Address.Hex())
+		}
+	},
+}
+
+var accountsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the balance and nonce of each account on every node",
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedFormat()
+		store := requireAccounts(format)
+		tm, err := getTransactionManager()
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		nodes := cfg.NodeNames()
+		states := tm.AccountStates(ctx, nodes, store.Addresses())
+
+		if format != output.FormatText {
+			writeRecord(format, accountsRecord(store, states))
+			return
+		}
+		fmt.Printf("%-5s %-42s", "Index", "Address")
+		for _, node := range nodes {
+			fmt.Printf(" %-24s", node)
+		}
+		fmt.Println()
+		fmt.Println("-" + strings.Repeat("-", 47+25*len(nodes)))
+		for _, account := range store.Accounts {
+			fmt.Printf("%-5d %-42s", account.Index, account.Address.Hex())
+			for _, state := range states[account.Address] {
+				cell := "offline"
+				if state.Err == nil {
+					cell = fmt.Sprintf("%s ETH, nonce %d", formatEther(state.Balance), state.Nonce)
+				}
+				fmt.Printf(" %-24s", cell)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// requireAccounts loads the derived accounts or exits when there are none.
+func requireAccounts(format output.Format) *accounts.Store {
+	store, err := accounts.Load(accounts.File)
+	if err != nil {
+		exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+	}
+	if store == nil || len(store.Accounts) == 0 {
+		exitWithError(format, output.NewError(output.CodeNotFound,
+			fmt.Errorf("no accounts in %s, create some with `benchy accounts create`", accounts.File)))
+	}
+	return store
+}
+
+func accountsRecord(store *accounts.Store, states map[common.Address][]scenarios.AccountState) output.AccountsRecord {
+	record := output.AccountsRecord{Path: store.Path, Accounts: []output.AccountRecord{}}
+	for _, account := range store.Accounts {
+		accountRecord := output.AccountRecord{Index: account.Index, Address: account.Address.Hex()}
+		for _, state := range states[account.Address] {
+			nodeRecord := output.AccountNodeRecord{Node: state.Node, Nonce: state.Nonce}
+			if state.Err != nil {
+				nodeRecord.Error = state.Err.Error()
+			} else {
+				nodeRecord.BalanceWei = state.Balance.String()
+			}
+			accountRecord.Nodes = append(accountRecord.Nodes, nodeRecord)
+		}
+		record.Accounts = append(record.Accounts, accountRecord)
+	}
+	return record
+}
+
+// formatEther prints wei as ETH with four decimals.
+func formatEther(wei *big.Int) string {
+	ether := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.Ether))
+	return ether.Text('f', 4)
+}
+
+func init() {
+	accountsCreateCmd.Flags().IntVar(&accountsCount, "count", 10, "Number of accounts to derive")
+	accountsCreateCmd.Flags().StringVar(&accountsMnemonic, "mnemonic", "", "BIP-39 mnemonic of the first call (default the development mnemonic)")
+	addOutputFlag(accountsListCmd)
+	accountsCmd.AddCommand(accountsCreateCmd, accountsListCmd)
+	rootCmd.AddCommand(accountsCmd)
+}
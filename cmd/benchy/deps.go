@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"benchy/internal/config"
+	"benchy/internal/docker"
+	"benchy/internal/logging"
+	"benchy/internal/monitor"
+	"benchy/internal/scenarios"
+
+	"github.com/spf13/cobra"
+)
+
+var configPath string
+var nodeFilter []string
+var logLevel string
+var logFormat string
+
+// runDir holds the logs of this invocation, under cfg.RunsDir.
+var runDir string
+
+// closeLog closes the run log opened by loadConfig.
+var closeLog = func() error { return nil }
+
+// cfg is loaded before any command runs; dependencies below are built from
+// it on first use so commands only pay for what they need.
+var cfg = config.Default()
+
+var (
+	dockerOnce    sync.Once
+	dockerManager *docker.DockerManager
+	dockerErr     error
+
+	monitorOnce    sync.Once
+	networkMonitor *monitor.NetworkMonitor
+
+	transactionsOnce   sync.Once
+	transactionManager *scenarios.TransactionManager
+	transactionsErr    error
+)
+
+// loadConfig applies --config and --nodes. It runs before every command but
+// not for --help.
+func loadConfig(cmd *cobra.Command, args []string) error {
+	// Config errors are not usage errors; main prints them once
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	loaded, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if err := loaded.Select(nodeFilter); err != nil {
+		return err
+	}
+	cfg = loaded
+	monitor.Configure(cfg)
+
+	runDir, err = logging.NewRunDir(cfg.RunsDir, strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "))
+	if err != nil {
+		return err
+	}
+	closeLog, err = logging.Setup(logging.Options{Level: logLevel, Format: logFormat, RunDir: runDir})
+	if err != nil {
+		return err
+	}
+	slog.Debug("Run started", "command", cmd.CommandPath(), "args", args, "run_dir", runDir)
+	return nil
+}
+
+func getDockerManager() (*docker.DockerManager, error) {
+	dockerOnce.Do(func() {
+		if cfg.Backend == config.BackendInproc {
+			dockerErr = fmt.Errorf("the %s backend has no containers", cfg.Backend)
+			return
+		}
+		dockerManager, dockerErr = docker.NewDockerManager(cfg.ComposeDir)
+	})
+	return dockerManager, dockerErr
+}
+
+func getNetworkMonitor() *monitor.NetworkMonitor {
+	monitorOnce.Do(func() {
+		if cfg.Backend == config.BackendInproc {
+			networkMonitor = monitor.NewNetworkMonitorWithRuntime(monitor.InprocRuntime{})
+			return
+		}
+		networkMonitor = monitor.NewNetworkMonitor()
+	})
+	return networkMonitor
+}
+
+func getTransactionManager() (*scenarios.TransactionManager, error) {
+	transactionsOnce.Do(func() {
+		transactionManager, transactionsErr = scenarios.NewTransactionManager(cfg.Endpoints())
+		if transactionsErr == nil {
+			transactionManager.SetGenesis(cfg.Genesis)
+		}
+	})
+	return transactionManager, transactionsErr
+}
+
+// mustDockerManager is getDockerManager for commands that cannot continue
+// without Docker.
+func mustDockerManager() *docker.DockerManager {
+	manager, err := getDockerManager()
+	if err != nil {
+		fatal("Failed to initialize Docker manager", err)
+	}
+	return manager
+}
+
+// fatal logs msg with err and exits. The run log is unbuffered, so nothing
+// is lost by skipping deferred calls.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "YAML config with compose_dir, genesis and nodes")
+	rootCmd.PersistentFlags().StringSliceVar(&nodeFilter, "nodes", nil, "Only use these nodes (comma separated)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Minimum console log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "pretty", "Console log format: pretty, text or json")
+	rootCmd.PersistentPreRunE = loadConfig
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"benchy/internal/output"
+	"benchy/internal/scenarios"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/spf13/cobra"
+)
+
+var (
+	faucetTo     []string
+	faucetAmount string
+	faucetNode   string
+	faucetBatch  int
+)
+
+var faucetCmd = &cobra.Command{
+	Use:   "faucet",
+	Short: "Fund accounts from a genesis-funded coinbase",
+	Long: `Send --amount ETH to each address of --to, or to every account of
+` + "`benchy accounts create`" + ` with --to all, from the unlocked coinbase of --node.
+Transfers go out in JSON-RPC batches of --batch, each mined before the next.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedFormat()
+		requireNode(faucetNode)
+		if faucetBatch <= 0 {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, fmt.Errorf("--batch must be positive")))
+		}
+		amount, err := parseEther(faucetAmount)
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+		var recipients []common.Address
+		for _, to := range faucetTo {
+			switch {
+			case to == "all":
+				recipients = append(recipients, requireAccounts(format).Addresses()...)
+			case common.IsHexAddress(to):
+				recipients = append(recipients, common.HexToAddress(to))
+			default:
+				exitWithError(format, output.NewError(output.CodeInvalidArgument, fmt.Errorf("invalid address %q", to)))
+			}
+		}
+		if len(recipients) == 0 {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, fmt.Errorf("--to is required")))
+		}
+
+		tm, err := getTransactionManager()
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		restore := output.HumanOutput(format)
+		fmt.Printf("💧 Sending %s ETH to %d accounts through %s\n", formatEther(amount), len(recipients), faucetNode)
+		result, err := tm.Faucet(ctx, faucetNode, recipients, amount, faucetBatch)
+		restore()
+		if err != nil {
+			code := output.CodeFundingFailed
+			switch {
+			case errors.Is(err, scenarios.ErrInsufficientFunds):
+				code = output.CodeInsufficientFunds
+			case errors.Is(err, scenarios.ErrNodeOffline):
+				code = output.CodeNodeOffline
+			}
+			exitWithError(format, output.NewError(code, err))
+		}
+
+		if format != output.FormatText {
+			writeRecord(format, faucetRecord(result))
+			return
+		}
+		fmt.Printf("✅ Funded %d accounts from %s\n", len(result.Transfers), result.From.Hex())
+	},
+}
+
+// parseEther reads a positive decimal amount of ETH as wei.
+func parseEther(value string) (*big.Int, error) {
+	ether, ok := new(big.Float).SetPrec(256).SetString(value)
+	if !ok || ether.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %q, expected a positive number of ETH", value)
+	}
+	wei, _ := ether.Mul(ether, new(big.Float).SetInt(big.NewInt(params.Ether))).Int(nil)
+	return wei, nil
+}
+
+func faucetRecord(result *scenarios.FaucetResult) output.FaucetRecord {
+	record := output.FaucetRecord{
+		Node:      result.Node,
+		From:      result.From.Hex(),
+		AmountWei: result.Amount.String(),
+		Transfers: []output.FaucetTransferRecord{},
+	}
+	for _, transfer := range result.Transfers {
+		record.Transfers = append(record.Transfers, output.FaucetTransferRecord{
+			To:     transfer.To.Hex(),
+			TxHash: transfer.Hash.Hex(),
+			Block:  transfer.Block,
+		})
+	}
+	return record
+}
+
+func init() {
+	faucetCmd.Flags().StringSliceVar(&faucetTo, "to", nil, "Addresses to fund, or all for every derived account")
+	faucetCmd.Flags().StringVar(&faucetAmount, "amount", "10", "ETH sent to each account")
+	faucetCmd.Flags().StringVar(&faucetNode, "node", "alice", "Node whose coinbase funds the accounts")
+	faucetCmd.Flags().IntVar(&faucetBatch, "batch", 100, "Transfers per JSON-RPC batch")
+	addOutputFlag(faucetCmd)
+	rootCmd.AddCommand(faucetCmd)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"benchy/internal/monitor"
+	"benchy/internal/output"
+	"benchy/internal/scenarios"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/spf13/cobra"
+)
+
+var (
+	loadNode        string
+	loadRate        float64
+	loadDuration    time.Duration
+	loadSenders     int
+	loadStrategies  []string
+	loadMix         []string
+	loadSlots       int
+	loadRounds      int
+	loadTipGwei     float64
+	loadMaxFeeGwei  float64
+	loadBumpPercent int
+	loadBumpAfter   uint64
+	loadMaxBumps    int
+	loadTimeout     time.Duration
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Generate transaction load and analyze the fee market",
+	Long: `Send transactions through one node at a steady rate. --mix sets the
+workloads and their weights, e.g. --mix transfer=2,erc20=1,swap=1:
+
+  transfer  plain ETH transfer
+  erc20     ERC20 transfer between the senders
+  erc721    ERC721 mint
+  storage   writes --storage-slots fresh storage slots
+  keccak    chains --keccak-rounds keccak256 hashes
+  swap      Uniswap V2 style constant product swap
+
+The contracts are deployed, and the ERC20 tokens minted to the senders,
+before the run. Transactions are priced with the fee strategies of
+--fee-strategy in turn:
+
+  fixed   pays --tip-gwei up to --max-fee-gwei
+  oracle  pays the node's suggested tip, up to twice the base fee plus the tip
+  bump    starts like oracle and replaces transactions still pending after
+          --bump-after blocks with fees raised by --bump-percent
+
+The inclusion latency and gas used are reported per strategy and per
+workload, followed by the base fee,
+priority fee percentiles, gas used and fullness of every block of the run,
+read with eth_feeHistory.
+
+The senders are funded by the unlocked coinbase of --node.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedFormat()
+		requireNode(loadNode)
+		if loadRate <= 0 {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, fmt.Errorf("--rate must be positive")))
+		}
+
+		var strategies []scenarios.FeeStrategy
+		for _, value := range loadStrategies {
+			strategy, err := scenarios.ParseFeeStrategy(value)
+			if err != nil {
+				exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+			}
+			strategies = append(strategies, strategy)
+		}
+		mix, err := scenarios.ParseWorkloadMix(loadMix)
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+		settings := scenarios.DefaultFeeSettings()
+		settings.Tip = gweiToWei(loadTipGwei)
+		settings.MaxFee = gweiToWei(loadMaxFeeGwei)
+		settings.BumpPercent = loadBumpPercent
+		settings.BumpAfter = loadBumpAfter
+		settings.MaxBumps = loadMaxBumps
+
+		tm, err := getTransactionManager()
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		plan := scenarios.LoadPlan{
+			Node:       loadNode,
+			Rate:       loadRate,
+			Duration:   loadDuration,
+			Mix:        mix,
+			Workloads:  scenarios.WorkloadSettings{StorageSlots: loadSlots, KeccakRounds: loadRounds},
+			Senders:    loadSenders,
+			Strategies: strategies,
+			Fees:       settings,
+			Timeout:    loadTimeout,
+		}
+
+		restore := output.HumanOutput(format)
+		fmt.Printf("🚚 Sending %.1f tx/s through %s for %s, mix %s, fee strategies %v\n", plan.Rate, plan.Node, plan.Duration, plan.Mix, loadStrategies)
+		result, err := tm.RunLoad(ctx, plan)
+		restore()
+		if err != nil {
+			code := output.CodeMeasurementFailed
+			if errors.Is(err, scenarios.ErrNodeOffline) {
+				code = output.CodeNodeOffline
+			}
+			exitWithError(format, output.NewError(code, err))
+		}
+
+		if format != output.FormatText {
+			writeRecord(format, loadRecord(result))
+			return
+		}
+		scenarios.DisplayLoad(result)
+	},
+}
+
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+func loadRecord(result *scenarios.LoadResult) output.LoadRecord {
+	record := output.LoadRecord{
+		Node:       result.Plan.Node,
+		StartedAt:  result.StartedAt.UTC(),
+		DurationMs: result.FinishedAt.Sub(result.StartedAt).Milliseconds(),
+		Sent:       len(result.Transactions),
+		Strategies: []output.LoadGroupRecord{},
+		Workloads:  []output.LoadGroupRecord{},
+		Blocks:     []output.BlockFeeRecord{},
+	}
+	for _, err := range result.Rejected {
+		record.Rejected = append(record.Rejected, err.Error())
+	}
+	for _, group := range result.ByStrategy() {
+		record.Strategies = append(record.Strategies, loadGroupRecord(group))
+	}
+	for _, group := range result.ByWorkload() {
+		record.Workloads = append(record.Workloads, loadGroupRecord(group))
+	}
+	if len(result.Contracts) > 0 {
+		record.Contracts = make(map[string]string)
+		for workload, address := range result.Contracts {
+			record.Contracts[string(workload)] = address.Hex()
+		}
+	}
+	for _, block := range result.Blocks {
+		record.Blocks = append(record.Blocks, blockFeeRecord(block))
+	}
+	return record
+}
+
+func loadGroupRecord(group scenarios.LoadGroup) output.LoadGroupRecord {
+	return output.LoadGroupRecord{
+		Name:            group.Name,
+		Sent:            group.Sent,
+		Included:        group.Included,
+		Reverted:        group.Reverted,
+		Bumps:           group.Bumps,
+		Inclusion:       latencyRecord(group.Latency),
+		AvgGasUsed:      group.AvgGasUsed,
+		AvgGasPriceGwei: group.AvgGasPrice,
+	}
+}
+
+func blockFeeRecord(block monitor.BlockFees) output.BlockFeeRecord {
+	tips := make([]float64, len(block.Tips))
+	for i, tip := range block.Tips {
+		tips[i] = monitor.Gwei(tip)
+	}
+	return output.BlockFeeRecord{
+		Number:       block.Number,
+		BaseFeeGwei:  monitor.Gwei(block.BaseFee),
+		TipsGwei:     tips,
+		Transactions: block.Transactions,
+		GasUsed:      block.GasUsed,
+		GasLimit:     block.GasLimit,
+		GasUsedRatio: block.GasUsedRatio(),
+		Fullness:     block.Fullness(),
+	}
+}
+
+func init() {
+	defaults := scenarios.DefaultFeeSettings()
+	workloads := scenarios.DefaultWorkloadSettings()
+	loadCmd.Flags().StringVar(&loadNode, "node", "alice", "Node to send through, whose coinbase funds the senders")
+	loadCmd.Flags().Float64Var(&loadRate, "rate", 5, "Transactions per second")
+	loadCmd.Flags().DurationVar(&loadDuration, "duration", 30*time.Second, "How long to send")
+	loadCmd.Flags().IntVar(&loadSenders, "senders", 4, "Sending accounts per fee strategy")
+	loadCmd.Flags().StringSliceVar(&loadMix, "mix", []string{string(scenarios.WorkloadTransfer)}, "Workloads and their weights: transfer, erc20, erc721, storage, keccak, swap")
+	loadCmd.Flags().IntVar(&loadSlots, "storage-slots", workloads.StorageSlots, "Slots written per storage transaction")
+	loadCmd.Flags().IntVar(&loadRounds, "keccak-rounds", workloads.KeccakRounds, "Hashes per keccak transaction")
+	loadCmd.Flags().StringSliceVar(&loadStrategies, "fee-strategy", []string{string(scenarios.FeeOracle)}, "Fee strategies used in turn: fixed, oracle, bump")
+	loadCmd.Flags().Float64Var(&loadTipGwei, "tip-gwei", monitor.Gwei(defaults.Tip), "Priority fee of the fixed strategy")
+	loadCmd.Flags().Float64Var(&loadMaxFeeGwei, "max-fee-gwei", monitor.Gwei(defaults.MaxFee), "Max fee of the fixed strategy")
+	loadCmd.Flags().IntVar(&loadBumpPercent, "bump-percent", defaults.BumpPercent, "Fee raise of each replacement of the bump strategy")
+	loadCmd.Flags().Uint64Var(&loadBumpAfter, "bump-after", defaults.BumpAfter, "Blocks a transaction may stay pending before it is bumped")
+	loadCmd.Flags().IntVar(&loadMaxBumps, "max-bumps", defaults.MaxBumps, "Replacements of one transaction at most")
+	loadCmd.Flags().DurationVar(&loadTimeout, "timeout", time.Minute, "How long to wait for the last transactions")
+	addOutputFlag(loadCmd)
+	rootCmd.AddCommand(loadCmd)
+}
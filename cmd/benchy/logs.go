@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	"benchy/internal/docker"
+	"benchy/internal/output"
+)
+
+// logScanTail is how many recent lines per node a one-shot `infos` scans.
+const logScanTail = 500
+
+func logSources() []docker.LogSource {
+	sources := make([]docker.LogSource, len(cfg.Nodes))
+	for i, node := range cfg.Nodes {
+		sources[i] = docker.LogSource{Node: node.Name, Client: node.Client}
+	}
+	return sources
+}
+
+// startLogCollector follows the node logs into the run directory. It returns
+// nil when Docker is unavailable, since logs are only a diagnostic.
+func startLogCollector() *docker.LogCollector {
+	manager, err := getDockerManager()
+	if err != nil {
+		slog.Debug("Container logs not collected", "error", err)
+		return nil
+	}
+	collector, err := manager.CollectLogs(filepath.Join(runDir, "logs"), logSources())
+	if err != nil {
+		slog.Warn("Container logs not collected", "error", err)
+		return nil
+	}
+	return collector
+}
+
+// scanRecentLogs scans the tail of each node's logs, or returns nil without
+// Docker.
+func scanRecentLogs() *docker.LogScanner {
+	manager, err := getDockerManager()
+	if err != nil {
+		slog.Debug("Container logs not scanned", "error", err)
+		return nil
+	}
+	return manager.ScanRecentLogs(context.Background(), logSources(), logScanTail)
+}
+
+// addLogErrors attaches the counts of each node to its record.
+func addLogErrors(record *output.NetworkRecord, counts map[string]map[string]int) {
+	for i := range record.Nodes {
+		if kinds := counts[record.Nodes[i].Name]; len(kinds) > 0 {
+			record.Nodes[i].LogErrors = kinds
+		}
+	}
+}
@@ -1,21 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"benchy/internal/docker"
+	"benchy/internal/config"
+	"benchy/internal/devnet"
 	"benchy/internal/monitor"
+	"benchy/internal/output"
 	"benchy/internal/scenarios"
+	"benchy/internal/snapshot"
+	"benchy/internal/tui"
 
 	"github.com/spf13/cobra"
 )
 
-var dockerManager *docker.DockerManager
-var networkMonitor *monitor.NetworkMonitor
-var transactionManager *scenarios.TransactionManager
-
 var updateInterval int
+var showValidators bool
+var showDashboard bool
+var validatorBlocks uint64
+var alertRulesPath string
+var alertLogPath string
+var alertWebhook string
+var fromSnapshot string
+var launchBackend string
+var inprocPeriod uint64
+
+const hostSampleInterval = 2 * time.Second
 
 var rootCmd = &cobra.Command{
 	Use:   "benchy",
@@ -27,20 +45,78 @@ var launchCmd = &cobra.Command{
 	Use:   "launch-network",
 	Short: "Launch the Ethereum network with 5 nodes",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := dockerManager.LaunchNetwork(); err != nil {
-			fmt.Printf("❌ Failed to launch network: %v\n", err)
-			os.Exit(1)
+		switch launchBackend {
+		case config.BackendDocker:
+		case config.BackendInproc:
+			launchInproc()
+			return
+		default:
+			fatal("Invalid --backend", fmt.Errorf("unknown backend %q (use %s or %s)", launchBackend, config.BackendDocker, config.BackendInproc))
+		}
+
+		if fromSnapshot != "" {
+			manifest, err := snapshot.Restore(context.Background(), mustDockerManager(), cfg, fromSnapshot)
+			if err != nil {
+				slog.Error("Failed to launch network from snapshot", "snapshot", fromSnapshot, "error", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ Network restored from snapshot %s (%s)\n", manifest.Name,
+				manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		} else if err := mustDockerManager().LaunchNetwork(); err != nil {
+			fatal("Failed to launch network", err)
 		}
+
+		printNodeEndpoints(cfg.Nodes)
 	},
 }
 
+func printNodeEndpoints(nodes []config.Node) {
+	fmt.Println("📍 Nodes accessible at:")
+	for _, node := range nodes {
+		fmt.Printf("  - %-22s %s\n", fmt.Sprintf("%s (%s):", node.DisplayName(), node.Client), node.Endpoint)
+	}
+}
+
+// launchInproc runs the selected nodes inside this process until
+// interrupted, publishing their endpoints in devnet.ConfigFile.
+func launchInproc() {
+	if fromSnapshot != "" {
+		fatal("Invalid --from-snapshot", fmt.Errorf("snapshots need the %s backend", config.BackendDocker))
+	}
+
+	slog.Info("🚀 Launching in-process Ethereum network", "nodes", len(cfg.Nodes))
+	network, err := devnet.Start(devnet.Options{Nodes: cfg.Nodes, Genesis: cfg.Genesis, Period: inprocPeriod})
+	if err != nil {
+		fatal("Failed to launch in-process network", err)
+	}
+	defer network.Close()
+
+	if err := network.WriteGenesis(devnet.GenesisFile); err != nil {
+		fatal("Failed to write in-process genesis", err)
+	}
+	defer os.Remove(devnet.GenesisFile)
+	inprocCfg := network.Config(cfg)
+	if err := inprocCfg.Write(devnet.ConfigFile); err != nil {
+		fatal("Failed to write in-process config", err)
+	}
+	defer os.Remove(devnet.ConfigFile)
+
+	slog.Info("✅ Network launched successfully!")
+	printNodeEndpoints(inprocCfg.Nodes)
+	fmt.Printf("💡 Use --config %s with other commands; press Ctrl+C to stop the network\n", devnet.ConfigFile)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	slog.Info("🛑 Stopping in-process network")
+}
+
 var cleanCmd = &cobra.Command{
 	Use:   "clean",
 	Short: "Clean Docker containers and persistent state",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := dockerManager.CleanNetwork(); err != nil {
-			fmt.Printf("❌ Failed to clean: %v\n", err)
-			os.Exit(1)
+		if err := mustDockerManager().CleanNetwork(); err != nil {
+			fatal("Failed to clean", err)
 		}
 	},
 }
@@ -49,48 +125,207 @@ var infosCmd = &cobra.Command{
 	Use:   "infos",
 	Short: "Display information about network nodes",
 	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedFormat()
+		// The producer table has no place in the dashboard, the refreshed
+		// view or the per-node CSV rows
+		if showValidators && (showDashboard || updateInterval > 0 || format == output.FormatCSV) {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument,
+				fmt.Errorf("--validators works with text, json or yaml output, without --tui or -u")))
+		}
+		if showDashboard {
+			live := monitor.NewLiveMonitor(getNetworkMonitor())
+			if err := tui.Run(live, mustDockerManager(), time.Duration(updateInterval)*time.Second); err != nil {
+				fatal("Dashboard failed", err)
+			}
+			return
+		}
+		if format != output.FormatText {
+			restore := output.HumanOutput(format)
+			infos, nodeErrors := getNetworkMonitor().CollectNodeInfo()
+			scanner := scanRecentLogs()
+			var validators *monitor.ValidatorReport
+			var err error
+			if showValidators {
+				validators, err = getNetworkMonitor().AnalyzeValidators(validatorBlocks)
+			}
+			restore()
+			if err != nil {
+				exitWithError(format, output.NewError(output.CodeNetworkInfoFailed, err))
+			}
+			record := networkRecord(infos, nodeErrors)
+			if scanner != nil {
+				addLogErrors(&record, scanner.Counts())
+			}
+			if validators != nil {
+				record.Validators = validatorsRecord(validators)
+			}
+			writeRecord(format, record)
+			return
+		}
 		if updateInterval > 0 {
-			if err := networkMonitor.DisplayNetworkInfoContinuous(updateInterval); err != nil {
-				fmt.Printf("❌ Failed to display continuous info: %v\n", err)
+			rules, err := monitor.LoadAlertRules(alertRulesPath)
+			if err != nil {
+				slog.Error(err.Error())
 				os.Exit(1)
 			}
+			alerts := monitor.NewAlertEngine(rules, alertLogPath, alertWebhook)
+			var logErrors monitor.LogErrorSource
+			if collector := startLogCollector(); collector != nil {
+				defer collector.Stop()
+				logErrors = collector.Scanner()
+			}
+			if err := getNetworkMonitor().DisplayNetworkInfoContinuous(updateInterval, alerts, logErrors); err != nil {
+				fatal("Failed to display continuous info", err)
+			}
 		} else {
 			// Utiliser la version optimisée
-			if err := networkMonitor.DisplayNetworkInfoFast(); err != nil {
-				fmt.Printf("❌ Failed to get network info: %v\n", err)
-				os.Exit(1)
+			if err := getNetworkMonitor().DisplayNetworkInfoFast(); err != nil {
+				fatal("Failed to get network info", err)
+			}
+			if scanner := scanRecentLogs(); scanner != nil {
+				monitor.DisplayLogErrors(scanner.Counts())
+			}
+			if showValidators {
+				if err := getNetworkMonitor().DisplayValidatorSummary(validatorBlocks); err != nil {
+					fatal("Failed to get validator summary", err)
+				}
 			}
 		}
 	},
 }
 
+var (
+	scenarioWithDeps      bool
+	scenarioForce         bool
+	trackBlockPropagation bool
+)
+
 var scenarioCmd = &cobra.Command{
 	Use:   "scenario [number]",
 	Short: "Run predefined scenarios on network",
-	Args:  cobra.ExactArgs(1),
+	Long: `Run a predefined scenario. A scenario whose prerequisites have not run
+fails unless --with-deps, and a scenario whose effects are already on chain is
+skipped unless --force.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		scenario := args[0]
-		fmt.Printf("🎬 Running scenario %s on network...\n", scenario)
-		
-		var err error
-		switch scenario {
-		case "0":
-			err = transactionManager.FullScenario0()
-		case "1":
-			err = transactionManager.FullScenario1()
-		case "2":
-			err = transactionManager.FullScenario2()
-		case "3":
-			err = transactionManager.FullScenario3()
-		default:
-			fmt.Printf("❌ Unknown scenario: %s\n", scenario)
-			return
+		opts := scenarios.RunOptions{WithDeps: scenarioWithDeps, Force: scenarioForce}
+		runScenarios(fmt.Sprintf("🎬 Running scenario %s on network...", scenario), func(tm *scenarios.TransactionManager) ([]*scenarios.ScenarioResult, error) {
+			return tm.Run(scenario, opts)
+		}, func(results []*scenarios.ScenarioResult, report runReport) interface{} {
+			record := scenarioRecordWithPrerequisites(results)
+			record.Host = report.Host
+			record.BlockPropagation = report.BlockPropagation
+			if len(report.LogErrors) > 0 {
+				record.LogErrors = report.LogErrors
+			}
+			return record
+		})
+	},
+}
+
+var scenarioRunAllCmd = &cobra.Command{
+	Use:   "run-all",
+	Short: "Run every scenario in dependency order, skipping those already done",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := scenarios.RunOptions{Force: scenarioForce}
+		runScenarios("🎬 Running all scenarios on network...", func(tm *scenarios.TransactionManager) ([]*scenarios.ScenarioResult, error) {
+			return tm.RunAll(opts)
+		}, func(results []*scenarios.ScenarioResult, report runReport) interface{} {
+			record := output.ScenarioRunRecord{Success: true, Scenarios: []output.ScenarioRecord{}, Host: report.Host}
+			for _, result := range results {
+				record.Scenarios = append(record.Scenarios, scenarioRecord(result))
+				record.Success = record.Success && result.Success()
+			}
+			if len(report.LogErrors) > 0 {
+				record.LogErrors = report.LogErrors
+			}
+			record.BlockPropagation = report.BlockPropagation
+			return record
+		})
+	},
+}
+
+// runReport is what runScenarios measured around the scenarios.
+type runReport struct {
+	Host      *output.HostRecord
+	LogErrors map[string]map[string]int
+	// BlockPropagation is nil without --block-propagation
+	BlockPropagation *output.BlockPropagationRecord
+}
+
+// runScenarios runs scenarios while sampling the host and collecting node
+// logs, then reports them in the selected format, built by record.
+func runScenarios(banner string, run func(*scenarios.TransactionManager) ([]*scenarios.ScenarioResult, error),
+	record func([]*scenarios.ScenarioResult, runReport) interface{}) {
+	format := selectedFormat()
+
+	transactions, err := getTransactionManager()
+	if err != nil {
+		exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+	}
+
+	restore := output.HumanOutput(format)
+	fmt.Println(banner)
+	host, stopHost := startHostSampler()
+	collector := startLogCollector()
+	var blocks *monitor.BlockPropagation
+	if trackBlockPropagation {
+		blocks = getNetworkMonitor().TrackBlockPropagation(context.Background())
+	}
+	results, err := run(transactions)
+	stopHost()
+	var blockReport monitor.BlockPropagationReport
+	if blocks != nil {
+		blocks.Stop()
+		blockReport = blocks.Report()
+	}
+	var logErrors map[string]map[string]int
+	if collector != nil {
+		collector.Stop()
+		logErrors = collector.Scanner().Counts()
+	}
+	restore()
+	hostSummary := host.Summary()
+
+	if format != output.FormatText {
+		report := runReport{Host: hostRecord(hostSummary), LogErrors: logErrors}
+		if blocks != nil {
+			report.BlockPropagation = blockPropagationRecord(blockReport)
 		}
-		
+		writeRecord(format, record(results, report))
 		if err != nil {
-			fmt.Printf("❌ Scenario failed: %v\n", err)
+			os.Exit(1)
 		}
-	},
+		return
+	}
+	if errors.Is(err, scenarios.ErrUnknownScenario) {
+		slog.Error("Unknown scenario", "scenario", results[len(results)-1].Scenario)
+		return
+	}
+	if err != nil {
+		slog.Error("Scenario failed", "error", err)
+	}
+	monitor.DisplayHostSummary(hostSummary)
+	if blocks != nil {
+		monitor.DisplayBlockPropagation(blockReport)
+	}
+	if collector != nil {
+		monitor.DisplayLogErrors(logErrors)
+	}
+}
+
+// startHostSampler samples the host until the returned stop function is
+// called. A last sample is taken on stop so short runs still get a rate.
+func startHostSampler() (*monitor.HostSampler, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	host := monitor.NewHostSampler(monitor.NodeDataPaths(ctx))
+	host.Start(ctx, hostSampleInterval)
+	return host, func() {
+		cancel()
+		host.SampleOnce(context.Background())
+	}
 }
 
 var failureCmd = &cobra.Command{
@@ -99,26 +334,65 @@ var failureCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		node := args[0]
-		if err := dockerManager.StopContainer(node, 40); err != nil {
-			fmt.Printf("❌ Failed to simulate failure: %v\n", err)
-			os.Exit(1)
+		format := selectedFormat()
+
+		if !isConfiguredNode(node) {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument,
+				fmt.Errorf("unknown node %s (known: %s)", node, strings.Join(cfg.NodeNames(), ", "))))
+		}
+		manager, err := getDockerManager()
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeDockerFailed, err))
+		}
+
+		record := output.FailureRecord{Node: node, DurationSeconds: 40, StoppedAt: time.Now().UTC()}
+		restore := output.HumanOutput(format)
+		err = manager.StopContainer(node, 40)
+		restore()
+
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeDockerFailed, fmt.Errorf("failed to simulate failure: %v", err)))
+		}
+		if format != output.FormatText {
+			record.RestartedAt = time.Now().UTC()
+			record.Success = true
+			writeRecord(format, record)
 		}
 	},
 }
 
-func init() {
-	var err error
-	
-	dockerManager, err = docker.NewDockerManager()
-	if err != nil {
-		fmt.Printf("❌ Failed to initialize Docker manager: %v\n", err)
-		os.Exit(1)
+func isConfiguredNode(name string) bool {
+	for _, node := range cfg.NodeNames() {
+		if node == name {
+			return true
+		}
 	}
+	return false
+}
 
-	networkMonitor = monitor.NewNetworkMonitor()
-	transactionManager = scenarios.NewTransactionManager()
-
+func init() {
 	rootCmd.PersistentFlags().IntVarP(&updateInterval, "update", "u", 0, "Update interval in seconds")
+	addOutputFlag(infosCmd)
+	addOutputFlag(scenarioCmd)
+	addOutputFlag(failureCmd)
+	scenarioCmd.PersistentFlags().Float64Var(&monitor.HostSaturationThreshold, "saturation-threshold", monitor.HostSaturationThreshold,
+		"Host CPU, memory or disk usage (%) reported as saturation")
+	scenarioCmd.Flags().BoolVar(&scenarioWithDeps, "with-deps", false, "Run the prerequisites that have not run first")
+	scenarioCmd.PersistentFlags().BoolVar(&scenarioForce, "force", false, "Run scenarios whose effects are already on chain again")
+	scenarioCmd.PersistentFlags().BoolVar(&trackBlockPropagation, "block-propagation", false,
+		"Record when each node first sees each block and report the delays after the sealer")
+	addOutputFlag(scenarioRunAllCmd)
+	scenarioCmd.AddCommand(scenarioRunAllCmd)
+	infosCmd.Flags().BoolVar(&showDashboard, "tui", false, "Open the interactive dashboard")
+	infosCmd.Flags().BoolVar(&showValidators, "validators", false, "Show block production per validator")
+	infosCmd.Flags().Uint64Var(&validatorBlocks, "validator-blocks", 100, "Number of recent blocks analyzed with --validators")
+	infosCmd.Flags().StringVar(&alertRulesPath, "alert-rules", "", "YAML file overriding the default alert rules (with --update)")
+	infosCmd.Flags().StringVar(&alertLogPath, "alert-log", "", "Append alerts as JSON lines to this file")
+	infosCmd.Flags().StringVar(&alertWebhook, "alert-webhook", "", "POST each alert as JSON to this URL")
+
+	launchCmd.Flags().StringVar(&launchBackend, "backend", config.BackendDocker, "Run the nodes with docker or inproc (inside this process, until Ctrl+C)")
+	launchCmd.Flags().Uint64Var(&inprocPeriod, "period", 0, "Clique block period in seconds for --backend inproc (default: the genesis period)")
+	launchCmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "Restore node datadirs from a snapshot made with `snapshot create`")
 
 	rootCmd.AddCommand(launchCmd)
 	rootCmd.AddCommand(cleanCmd)
@@ -128,7 +402,9 @@ func init() {
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	closeLog()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchy/internal/clique"
+	"benchy/internal/config"
+	"benchy/internal/docker"
+	"benchy/internal/monitor"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logTail    int
+	logFollow  bool
+	nodeClient string
+	nodeRole   string
+)
+
+// Images for clients that `node add` can start.
+var clientImages = map[string]string{
+	"geth": "ethereum/client-go:latest",
+}
+
+var nodeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage the lifecycle of individual nodes",
+}
+
+// nodeAction builds a subcommand running one DockerManager call on a node.
+func nodeAction(use, short, done string, action func(*docker.DockerManager, string) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [node]",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			node := requireNode(args[0])
+			if err := action(mustDockerManager(), node); err != nil {
+				slog.Error("Failed to "+use+" node", "node", node, "error", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ %s %s\n", node, done)
+		},
+	}
+}
+
+var nodeLogsCmd = &cobra.Command{
+	Use:   "logs [node]",
+	Short: "Show a node's container logs",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		node := requireNode(args[0])
+		if err := mustDockerManager().NodeLogs(node, logTail, logFollow); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var nodeExecCmd = &cobra.Command{
+	Use:   "exec [node] -- [command...]",
+	Short: "Run a command inside a node's container",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		node := requireNode(args[0])
+		if err := mustDockerManager().ExecNode(node, args[1:]); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var nodeAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Start a new node and join it to the running network",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		if !nodeNamePattern.MatchString(name) {
+			slog.Error(fmt.Sprintf("Invalid node name %q (lowercase letters, digits and dashes)", args[0]))
+			os.Exit(1)
+		}
+		if isConfiguredNode(name) {
+			slog.Error(fmt.Sprintf("Node %s already exists", name))
+			os.Exit(1)
+		}
+		image, supported := clientImages[nodeClient]
+		if !supported {
+			slog.Error(fmt.Sprintf("Unsupported client %q (supported: geth)", nodeClient))
+			os.Exit(1)
+		}
+		if nodeRole != "observer" && nodeRole != "validator" {
+			slog.Error(fmt.Sprintf("Unknown role %q (expected observer or validator)", nodeRole))
+			os.Exit(1)
+		}
+
+		httpPort := nextHTTPPort()
+		spec := docker.NodeSpec{
+			Name:     name,
+			Image:    image,
+			HTTPPort: httpPort,
+			P2PPort:  30303 + (httpPort-8545)/2, // same spacing as docker-compose.yml
+			Mine:     nodeRole == "validator",
+		}
+		node := config.Node{
+			Name:      name,
+			Client:    "Geth",
+			Endpoint:  fmt.Sprintf("http://localhost:%d", httpPort),
+			Validator: nodeRole == "validator",
+		}
+
+		fmt.Printf("🚀 Adding %s %s %s on port %d...\n", nodeRole, nodeClient, name, httpPort)
+		if err := mustDockerManager().AddNode(spec); err != nil {
+			slog.Error("Failed to start node", "node", name, "error", err)
+			os.Exit(1)
+		}
+
+		// Record the node right away so `node remove` works even if it
+		// never becomes healthy
+		if err := recordAddedNode(node); err != nil {
+			slog.Error("Node is running but could not be recorded", "node", name, "error", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		if err := monitor.WaitForNode(ctx, node.Endpoint); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		joinNetwork(ctx, node)
+		if client, err := clique.Dial(node.Endpoint); err == nil {
+			if coinbase, err := client.Coinbase(ctx); err == nil {
+				node.Address = coinbase.Hex()
+				if err := recordAddedNode(node); err != nil {
+					slog.Warn("Failed to record node address", "node", name, "error", err)
+				}
+			}
+			client.Close()
+		}
+
+		if node.Validator {
+			proposeSigner(ctx, node, true)
+		}
+		fmt.Printf("✅ %s joined the network at %s\n", name, node.Endpoint)
+	},
+}
+
+var nodeRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Stop and delete a node added with `node add`",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		added, err := config.ReadAddedNodes(cfg.NodesFile)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+
+		index := -1
+		for i, node := range added {
+			if node.Name == name {
+				index = i
+			}
+		}
+		if index < 0 {
+			slog.Error(fmt.Sprintf("%s was not added with `benchy node add`; base nodes are removed with `clean`", name))
+			os.Exit(1)
+		}
+		node := added[index]
+
+		if node.Validator {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			proposeSigner(ctx, node, false)
+			cancel()
+		}
+
+		if err := mustDockerManager().RemoveNode(name); err != nil {
+			slog.Error("Failed to remove node", "node", name, "error", err)
+			os.Exit(1)
+		}
+		if err := config.WriteAddedNodes(cfg.NodesFile, append(added[:index], added[index+1:]...)); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s removed\n", name)
+	},
+}
+
+// recordAddedNode adds or updates node in the added nodes file.
+func recordAddedNode(node config.Node) error {
+	added, err := config.ReadAddedNodes(cfg.NodesFile)
+	if err != nil {
+		return err
+	}
+	for i := range added {
+		if added[i].Name == node.Name {
+			added[i] = node
+			return config.WriteAddedNodes(cfg.NodesFile, added)
+		}
+	}
+	return config.WriteAddedNodes(cfg.NodesFile, append(added, node))
+}
+
+// requireNode exits unless name is a configured node.
+func requireNode(name string) string {
+	if !isConfiguredNode(name) {
+		slog.Error(fmt.Sprintf("Unknown node %s (known: %s)", name, strings.Join(cfg.NodeNames(), ", ")))
+		os.Exit(1)
+	}
+	return name
+}
+
+// nextHTTPPort is the first port after the highest one used by a node.
+func nextHTTPPort() int {
+	highest := 8545
+	for _, node := range cfg.Nodes {
+		parsed, err := url.Parse(node.Endpoint)
+		if err != nil {
+			continue
+		}
+		if port, err := strconv.Atoi(parsed.Port()); err == nil && port > highest {
+			highest = port
+		}
+	}
+	return highest + 2
+}
+
+// joinNetwork peers the new node with the first reachable existing node.
+func joinNetwork(ctx context.Context, node config.Node) {
+	for _, existing := range cfg.Nodes {
+		enode, err := monitor.ContainerEnode(ctx, existing.Endpoint, "benchy-"+existing.Name)
+		if err != nil {
+			continue
+		}
+		if err := monitor.AddPeer(ctx, node.Endpoint, enode); err != nil {
+			slog.Warn("Failed to peer", "node", existing.Name, "error", err)
+			continue
+		}
+		fmt.Printf("🔗 Peered with %s\n", existing.Name)
+		return
+	}
+	slog.Warn("No existing node could be peered with")
+}
+
+// proposeSigner votes the node's coinbase in or out of the signer set.
+func proposeSigner(ctx context.Context, node config.Node, auth bool) {
+	if node.Address == "" {
+		slog.Warn(fmt.Sprintf("%s has no known address, propose it with `benchy validators propose`", node.Name))
+		return
+	}
+	action := "remove"
+	if auth {
+		action = "add"
+	}
+
+	fmt.Printf("🗳️  Proposing to %s %s as signer\n", action, node.Name)
+	votes, majority, err := castProposal(ctx, common.HexToAddress(node.Address), auth)
+	if err != nil {
+		slog.Warn(err.Error())
+		return
+	}
+	if votes < majority {
+		slog.Warn(fmt.Sprintf("Only %d/%d signers voted, finish with `benchy validators propose %s %s`",
+			votes, majority, action, node.Address))
+	}
+}
+
+func init() {
+	nodeLogsCmd.Flags().IntVar(&logTail, "tail", 100, "Number of lines from the end of the logs")
+	nodeLogsCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Follow log output")
+	nodeAddCmd.Flags().StringVar(&nodeClient, "client", "geth", "Client of the new node (geth)")
+	nodeAddCmd.Flags().StringVar(&nodeRole, "role", "observer", "Role of the new node: observer or validator")
+
+	nodeCmd.AddCommand(
+		nodeAction("start", "Start a stopped node", "started", (*docker.DockerManager).StartNode),
+		nodeAction("stop", "Stop a node until started again", "stopped", (*docker.DockerManager).StopNode),
+		nodeAction("restart", "Restart a node", "restarted", (*docker.DockerManager).RestartNode),
+		nodeAction("pause", "Freeze a node's processes", "paused", (*docker.DockerManager).PauseNode),
+		nodeAction("unpause", "Resume a paused node", "resumed", (*docker.DockerManager).UnpauseNode),
+		nodeAction("kill", "Kill a node without a clean shutdown", "killed", (*docker.DockerManager).KillNode),
+		nodeLogsCmd,
+		nodeExecCmd,
+		nodeAddCmd,
+		nodeRemoveCmd,
+	)
+	rootCmd.AddCommand(nodeCmd)
+}
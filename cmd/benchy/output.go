@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"benchy/internal/monitor"
+	"benchy/internal/output"
+	"benchy/internal/scenarios"
+
+	"github.com/spf13/cobra"
+)
+
+var outputFormat string
+
+// addOutputFlag registers --output on commands with a machine readable form.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, yaml or csv")
+}
+
+// selectedFormat parses --output, exiting on invalid values.
+func selectedFormat() output.Format {
+	format, err := output.ParseFormat(outputFormat)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	return format
+}
+
+func writeRecord(format output.Format, record interface{}) {
+	if err := output.Write(os.Stdout, format, record); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to write %s output: %v\n", format, err)
+		os.Exit(1)
+	}
+}
+
+// exitWithError reports err in the selected format and exits.
+func exitWithError(format output.Format, structured *output.Error) {
+	if format == output.FormatText {
+		slog.Error(structured.Message, "code", structured.Code)
+	} else {
+		writeRecord(format, output.ErrorRecord{Error: structured})
+	}
+	os.Exit(1)
+}
+
+func networkRecord(infos map[string]*monitor.NodeInfo, nodeErrors map[string]error) output.NetworkRecord {
+	record := output.NetworkRecord{Timestamp: time.Now().UTC(), Nodes: []output.NodeRecord{}}
+
+	for _, name := range monitor.NodeOrder {
+		if err, failed := nodeErrors[name]; failed {
+			record.Nodes = append(record.Nodes, output.NodeRecord{
+				Name:  name,
+				Error: output.NewError(output.CodeNetworkInfoFailed, err),
+			})
+			continue
+		}
+		info, exists := infos[name]
+		if !exists {
+			continue
+		}
+
+		balance := "0"
+		if info.Balance != nil {
+			balance = info.Balance.String()
+		}
+		record.Nodes = append(record.Nodes, output.NodeRecord{
+			Name:        info.Name,
+			Client:      info.Client,
+			Endpoint:    info.Endpoint,
+			Running:     info.IsRunning,
+			BlockNumber: info.BlockNumber,
+			PeerCount:   info.PeerCount,
+			CPUPercent:  info.CPUUsage,
+			MemoryUsage: info.MemoryUsage,
+			Address:     info.Address,
+			BalanceWei:  balance,
+			MempoolTxs:  info.MempoolTxs,
+			TxCount:     info.TxCount,
+		})
+	}
+
+	return record
+}
+
+func validatorsRecord(report *monitor.ValidatorReport) *output.ValidatorsRecord {
+	times := report.BlockTimes
+	record := &output.ValidatorsRecord{
+		FromBlock:                    report.FromBlock,
+		ToBlock:                      report.ToBlock,
+		PeriodSeconds:                report.Period,
+		Producers:                    []output.ProducerRecord{},
+		BlockTimeAvgSeconds:          times.Average,
+		BlockTimeMinSeconds:          times.Min,
+		BlockTimeMaxSeconds:          times.Max,
+		BlockTimeMaxDeviationSeconds: times.MaxDeviation,
+		LateBlocks:                   times.Late,
+	}
+	total := report.TotalBlocks()
+	for _, producer := range report.Producers {
+		record.Producers = append(record.Producers, output.ProducerRecord{
+			Node:          report.Names[producer.Signer],
+			Signer:        producer.Signer.Hex(),
+			Blocks:        producer.Blocks,
+			SharePercent:  producer.Share(total) * 100,
+			InTurn:        producer.InTurn,
+			OutOfTurn:     producer.OutOfTurn,
+			ExpectedTurns: producer.ExpectedTurns,
+			MissedTurns:   producer.MissedTurns,
+		})
+	}
+	return record
+}
+
+func scenarioRecord(result *scenarios.ScenarioResult) output.ScenarioRecord {
+	record := output.ScenarioRecord{
+		Scenario:     result.Scenario,
+		Success:      result.Success(),
+		StartedAt:    result.StartedAt.UTC(),
+		FinishedAt:   result.FinishedAt.UTC(),
+		DurationMs:   result.FinishedAt.Sub(result.StartedAt).Milliseconds(),
+		Transactions: []output.TransactionRecord{},
+		Skipped:      result.Skipped,
+	}
+	for _, tx := range result.Transactions {
+		txRecord := output.TransactionRecord{Hash: tx.Hash, From: tx.From, To: tx.To, ValueWei: tx.Value}
+		if tx.Err != nil {
+			txRecord.Error = tx.Err.Error()
+		}
+		record.Transactions = append(record.Transactions, txRecord)
+	}
+	for _, check := range result.Checks {
+		record.Checks = append(record.Checks, output.CheckRecord{Name: check.Name, Passed: check.Passed, Skipped: check.Skipped, Detail: check.Detail})
+	}
+	if result.Err != nil {
+		record.Error = scenarioError(result.Err)
+	}
+	return record
+}
+
+// scenarioRecordWithPrerequisites records the last result, the scenario
+// asked for, with the prerequisites run before it.
+func scenarioRecordWithPrerequisites(results []*scenarios.ScenarioResult) output.ScenarioRecord {
+	record := scenarioRecord(results[len(results)-1])
+	for _, result := range results[:len(results)-1] {
+		record.Prerequisites = append(record.Prerequisites, scenarioRecord(result))
+	}
+	return record
+}
+
+func hostRecord(summary monitor.HostSummary) *output.HostRecord {
+	if summary.Samples == 0 {
+		return nil
+	}
+	return &output.HostRecord{
+		Samples:              summary.Samples,
+		CPUPercentAvg:        summary.CPUPercent.Avg,
+		CPUPercentMax:        summary.CPUPercent.Max,
+		MemoryPercentAvg:     summary.MemoryPercent.Avg,
+		MemoryPercentMax:     summary.MemoryPercent.Max,
+		DiskReadBytesPerSec:  summary.DiskReadRate.Avg,
+		DiskWriteBytesPerSec: summary.DiskWriteRate.Avg,
+		DiskUsedPercent:      summary.DiskUsedPercent,
+		Saturated:            summary.Saturated(),
+		Warnings:             summary.Warnings,
+	}
+}
+
+func blockPropagationRecord(report monitor.BlockPropagationReport) *output.BlockPropagationRecord {
+	record := &output.BlockPropagationRecord{
+		Blocks:       report.Blocks,
+		Unattributed: report.Unattributed,
+		Nodes:        []output.BlockDelayRecord{},
+		Clients:      []output.BlockDelayRecord{},
+	}
+	for _, node := range report.Nodes {
+		record.Nodes = append(record.Nodes, blockDelayRecord(node))
+	}
+	for _, client := range report.Clients {
+		record.Clients = append(record.Clients, blockDelayRecord(client))
+	}
+	return record
+}
+
+func blockDelayRecord(delays monitor.PropagationDelays) output.BlockDelayRecord {
+	return output.BlockDelayRecord{
+		Node:      delays.Name,
+		Client:    delays.Client,
+		Delay:     latencyRecord(delays.Delays),
+		Histogram: output.HistogramRecord{BoundsMs: delays.Histogram.Bounds, Counts: delays.Histogram.Counts},
+	}
+}
+
+func latencyRecord(summary monitor.LatencySummary) output.LatencyRecord {
+	return output.LatencyRecord{
+		Samples: summary.Samples,
+		Missed:  summary.Missed,
+		MinMs:   summary.Min,
+		P50Ms:   summary.P50,
+		AvgMs:   summary.Avg,
+		P95Ms:   summary.P95,
+		MaxMs:   summary.Max,
+	}
+}
+
+func scenarioError(err error) *output.Error {
+	switch {
+	case errors.Is(err, scenarios.ErrUnknownScenario):
+		return output.NewError(output.CodeUnknownScenario, err)
+	case errors.Is(err, scenarios.ErrNodeOffline):
+		return output.NewError(output.CodeNodeOffline, err)
+	case errors.Is(err, scenarios.ErrMissingPrerequisite):
+		return output.NewError(output.CodeMissingPrerequisite, err)
+	case errors.Is(err, scenarios.ErrPreconditionFailed):
+		return output.NewError(output.CodePreconditionFailed, err)
+	case errors.Is(err, scenarios.ErrChecksFailed):
+		return output.NewError(output.CodeChecksFailed, err)
+	}
+	return output.NewError(output.CodeScenarioFailed, err)
+}
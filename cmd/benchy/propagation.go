@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"benchy/internal/output"
+	"benchy/internal/scenarios"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	propagationFrom     []string
+	propagationFunder   string
+	propagationCount    int
+	propagationInterval time.Duration
+	propagationPoll     time.Duration
+	propagationTimeout  time.Duration
+)
+
+var propagationCmd = &cobra.Command{
+	Use:   "propagation",
+	Short: "Measure how fast transactions reach every node's pool and a block",
+	Long: `Submit transfers to one node at a time and poll every other node with
+eth_getTransactionByHash until it holds the transaction pending in its pool,
+then in a block. Latencies are measured from the moment the origin accepted
+the transaction and summarized per node pair and per client pair, such as
+Geth → Nethermind.
+
+The senders are funded by the unlocked coinbase of --funder.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedFormat()
+		requireNode(propagationFunder)
+		origins := propagationFrom
+		if len(origins) == 0 {
+			origins = cfg.NodeNames()
+		}
+		for _, origin := range origins {
+			requireNode(origin)
+		}
+
+		tm, err := getTransactionManager()
+		if err != nil {
+			exitWithError(format, output.NewError(output.CodeInvalidArgument, err))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		clients := make(map[string]string)
+		for _, node := range cfg.Nodes {
+			clients[node.Name] = node.Client
+		}
+		plan := scenarios.PropagationPlan{
+			Funder:   propagationFunder,
+			Origins:  origins,
+			Count:    propagationCount,
+			Interval: propagationInterval,
+			Poll:     propagationPoll,
+			Timeout:  propagationTimeout,
+			Clients:  clients,
+		}
+
+		restore := output.HumanOutput(format)
+		fmt.Printf("📡 Sending %d transactions through %v, polling every %s\n", plan.Count, plan.Origins, plan.Poll)
+		result, err := tm.MeasurePropagation(ctx, plan)
+		restore()
+		if err != nil {
+			code := output.CodeMeasurementFailed
+			if errors.Is(err, scenarios.ErrNodeOffline) {
+				code = output.CodeNodeOffline
+			}
+			exitWithError(format, output.NewError(code, err))
+		}
+
+		if format != output.FormatText {
+			writeRecord(format, propagationRecord(result))
+			return
+		}
+		scenarios.DisplayPropagation(result)
+	},
+}
+
+func propagationRecord(result *scenarios.PropagationResult) output.PropagationRecord {
+	record := output.PropagationRecord{
+		StartedAt:   result.StartedAt.UTC(),
+		DurationMs:  result.FinishedAt.Sub(result.StartedAt).Milliseconds(),
+		Sent:        result.Sent,
+		Pairs:       []output.PropagationPairRecord{},
+		ClientPairs: []output.PropagationPairRecord{},
+	}
+	for _, err := range result.Errors {
+		record.Rejected = append(record.Rejected, err.Error())
+	}
+	for _, pair := range result.Pairs() {
+		record.Pairs = append(record.Pairs, propagationPairRecord(pair))
+	}
+	for _, pair := range result.ClientPairs() {
+		record.ClientPairs = append(record.ClientPairs, propagationPairRecord(pair))
+	}
+	return record
+}
+
+func propagationPairRecord(pair scenarios.PropagationPair) output.PropagationPairRecord {
+	return output.PropagationPairRecord{
+		From:       pair.From,
+		To:         pair.To,
+		FromClient: pair.FromClient,
+		ToClient:   pair.ToClient,
+		Pool:       latencyRecord(pair.Pool),
+		Block:      latencyRecord(pair.Block),
+	}
+}
+
+func init() {
+	propagationCmd.Flags().StringSliceVar(&propagationFrom, "from", nil, "Nodes to submit through in turn (default all selected nodes)")
+	propagationCmd.Flags().StringVar(&propagationFunder, "funder", "alice", "Node whose coinbase funds the senders")
+	propagationCmd.Flags().IntVar(&propagationCount, "count", 20, "Number of transactions to send")
+	propagationCmd.Flags().DurationVar(&propagationInterval, "interval", time.Second, "Delay between transactions")
+	propagationCmd.Flags().DurationVar(&propagationPoll, "poll", 20*time.Millisecond, "Polling interval of each node")
+	propagationCmd.Flags().DurationVar(&propagationTimeout, "timeout", time.Minute, "How long a node is polled for one transaction")
+	addOutputFlag(propagationCmd)
+	rootCmd.AddCommand(propagationCmd)
+}
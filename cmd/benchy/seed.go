@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"benchy/internal/scenarios"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	seedNode      string
+	seedAccounts  int
+	seedContracts int
+	seedSlots     int
+	seedBatch     int
+	seedRestart   bool
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Pre-populate the chain with accounts, contracts and storage",
+	Long: `Send batches of signed transactions from a pool of funded senders to
+create accounts, deploy storage filler contracts and write storage slots.
+
+Progress is saved to ` + scenarios.SeedProgressFile + ` after every mined batch;
+running the same command again after an interruption resumes it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireNode(seedNode)
+		if seedRestart {
+			if err := os.Remove(scenarios.SeedProgressFile); err != nil && !os.IsNotExist(err) {
+				slog.Error(err.Error())
+				os.Exit(1)
+			}
+		}
+
+		tm, err := getTransactionManager()
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		plan := scenarios.SeedPlan{
+			Node:      seedNode,
+			Accounts:  seedAccounts,
+			Contracts: seedContracts,
+			Slots:     seedSlots,
+			BatchSize: seedBatch,
+		}
+		fmt.Printf("🌱 Seeding through %s: %d accounts, %d contracts, %d storage slots\n",
+			seedNode, seedAccounts, seedContracts, seedSlots)
+		if _, err := tm.Seed(ctx, plan, scenarios.SeedProgressFile); err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				fmt.Println("⏸️  Seed interrupted, run the same command to resume")
+			} else {
+				slog.Error("Seed failed", "error", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Println("✅ Seed complete")
+		reportStateSize()
+	},
+}
+
+// reportStateSize prints each node's head block and datadir size.
+func reportStateSize() {
+	dm, err := getDockerManager()
+	if err != nil {
+		slog.Warn("Cannot measure datadirs", "error", err)
+		return
+	}
+
+	fmt.Println("\n📦 State size per node:")
+	for _, node := range cfg.Nodes {
+		block := "offline"
+		if client, err := ethclient.Dial(node.Endpoint); err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if number, err := client.BlockNumber(ctx); err == nil {
+				block = fmt.Sprintf("#%d", number)
+			}
+			cancel()
+			client.Close()
+		}
+		size, err := dm.DataDirSize(node.Name, node.ContainerDataDir())
+		if err != nil {
+			fmt.Printf("   %-10s %-10s ⚠️  %v\n", node.DisplayName(), block, err)
+			continue
+		}
+		fmt.Printf("   %-10s %-10s %s\n", node.DisplayName(), block, formatBytes(size))
+	}
+}
+
+func init() {
+	seedCmd.Flags().StringVar(&seedNode, "node", "alice", "Node the transactions are sent through")
+	seedCmd.Flags().IntVar(&seedAccounts, "accounts", 1000, "Number of accounts to create")
+	seedCmd.Flags().IntVar(&seedContracts, "contracts", 10, "Number of storage filler contracts to deploy")
+	seedCmd.Flags().IntVar(&seedSlots, "slots", 10000, "Number of storage slots to write across the contracts")
+	seedCmd.Flags().IntVar(&seedBatch, "batch", 100, "Transactions per JSON-RPC batch")
+	seedCmd.Flags().BoolVar(&seedRestart, "restart", false, "Discard saved progress and start over")
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"benchy/internal/api"
+	"benchy/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the HTTP control-plane API",
+	Long: `Expose launch, clean, status, scenarios and fault injection over HTTP.
+Long running operations are queued as jobs and run one at a time:
+
+  GET  /v1/status                 network status (same schema as infos -o json)
+  POST /v1/network/launch         launch the network
+  POST /v1/network/clean          clean containers and state
+  POST /v1/scenarios/{number}     run a scenario
+  POST /v1/faults                 {"node": "bob", "duration_seconds": 40}
+  GET  /v1/jobs[?kind=scenario]   list runs
+  GET  /v1/jobs/{id}              job status and result
+  GET  /v1/jobs/{id}/logs?since=N console output of a job
+  POST /v1/jobs/{id}/cancel       cancel a pending or running job`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		slog.Info("🛰️  Benchy API listening", "addr", serveAddr)
+		if err := api.NewServer(serveBackend{}, os.Stdout).ListenAndServe(ctx, serveAddr); err != nil {
+			fatal("API server failed", err)
+		}
+		slog.Info("👋 API server stopped")
+	},
+}
+
+// serveBackend runs API operations with the managers shared by all commands.
+type serveBackend struct{}
+
+func (serveBackend) Launch(ctx context.Context, log io.Writer) error {
+	manager, err := getDockerManager()
+	if err != nil {
+		return output.NewError(output.CodeDockerFailed, err)
+	}
+	if err := manager.WithOutput(log).LaunchNetwork(); err != nil {
+		return output.NewError(output.CodeDockerFailed, err)
+	}
+	return nil
+}
+
+func (serveBackend) Clean(ctx context.Context, log io.Writer) error {
+	manager, err := getDockerManager()
+	if err != nil {
+		return output.NewError(output.CodeDockerFailed, err)
+	}
+	if err := manager.WithOutput(log).CleanNetwork(); err != nil {
+		return output.NewError(output.CodeDockerFailed, err)
+	}
+	return nil
+}
+
+func (serveBackend) Status(ctx context.Context) (interface{}, error) {
+	infos, nodeErrors := getNetworkMonitor().CollectNodeInfo()
+	return networkRecord(infos, nodeErrors), nil
+}
+
+// RunScenario cannot interrupt a scenario midway; a cancelled job is
+// reported once the current scenario returns.
+func (serveBackend) RunScenario(ctx context.Context, log io.Writer, scenario string) (interface{}, error) {
+	transactions, err := getTransactionManager()
+	if err != nil {
+		return nil, output.NewError(output.CodeInvalidArgument, err)
+	}
+
+	host, stopHost := startHostSampler()
+	result, err := transactions.WithOutput(log).RunScenario(scenario)
+	stopHost()
+
+	record := scenarioRecord(result)
+	record.Host = hostRecord(host.Summary())
+	if err != nil {
+		return record, scenarioError(err)
+	}
+	return record, nil
+}
+
+// InjectFault stops a node for duration. Cancelling the job restarts the
+// node immediately.
+func (serveBackend) InjectFault(ctx context.Context, node string, duration time.Duration) (interface{}, error) {
+	record := output.FailureRecord{Node: node, DurationSeconds: int(duration.Seconds()), StoppedAt: time.Now().UTC()}
+
+	if !isConfiguredNode(node) {
+		return record, output.NewError(output.CodeInvalidArgument, fmt.Errorf("unknown node %s", node))
+	}
+	manager, err := getDockerManager()
+	if err != nil {
+		return record, output.NewError(output.CodeDockerFailed, err)
+	}
+
+	slog.Warn("Stopping node", "node", node, "duration", duration)
+	if err := manager.StopNode(node); err != nil {
+		return record, output.NewError(output.CodeDockerFailed, err)
+	}
+
+	select {
+	case <-time.After(duration):
+	case <-ctx.Done():
+		slog.Info("🛑 Fault cancelled, restarting node early", "node", node)
+	}
+
+	slog.Info("🔄 Restarting node", "node", node)
+	if err := manager.StartNode(node); err != nil {
+		return record, output.NewError(output.CodeDockerFailed, err)
+	}
+	record.RestartedAt = time.Now().UTC()
+	record.Success = true
+	slog.Info("✅ Node is back online", "node", node)
+	return record, nil
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "listen", "127.0.0.1:8080", "Address the API listens on")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"benchy/internal/snapshot"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and list node datadir snapshots",
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Freeze every node datadir, keys and topology into a tarball",
+	Long: `Stop the network, archive each node's datadir together with the genesis
+and nodes added with ` + "`node add`" + `, then start the network again.
+
+Restore with: benchy launch-network --from-snapshot [name]`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest, err := snapshot.Create(context.Background(), mustDockerManager(), cfg, args[0])
+		if err != nil {
+			fatal("Failed to create snapshot", err)
+		}
+
+		var total int64
+		for _, node := range manifest.Nodes {
+			total += node.Bytes
+		}
+		// Create has validated the name
+		archivePath, _ := snapshot.Path(cfg.SnapshotDir, manifest.Name)
+		fmt.Printf("✅ Snapshot %s saved to %s (%d nodes, %s)\n", manifest.Name,
+			archivePath, len(manifest.Nodes), formatBytes(total))
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots",
+	Run: func(cmd *cobra.Command, args []string) {
+		manifests, err := snapshot.List(cfg.SnapshotDir)
+		if err != nil {
+			fatal("Failed to list snapshots", err)
+		}
+		if len(manifests) == 0 {
+			fmt.Printf("No snapshots in %s\n", cfg.SnapshotDir)
+			return
+		}
+
+		fmt.Printf("%-24s %-20s %-6s %s\n", "NAME", "CREATED", "NODES", "SIZE")
+		for _, manifest := range manifests {
+			var total int64
+			for _, node := range manifest.Nodes {
+				total += node.Bytes
+			}
+			fmt.Printf("%-24s %-20s %-6d %s\n", manifest.Name,
+				manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"), len(manifest.Nodes), formatBytes(total))
+		}
+	},
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd, snapshotListCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
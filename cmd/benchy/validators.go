@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"benchy/internal/clique"
+	"benchy/internal/monitor"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var sealerBlocks uint64
+
+var validatorsCmd = &cobra.Command{
+	Use:   "validators",
+	Short: "Inspect and manage the Clique signer set",
+}
+
+var validatorsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List current Clique signers and pending votes",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		client, err := monitor.DialValidator()
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		defer client.Close()
+
+		snapshot, err := client.GetSnapshot(ctx)
+		if err != nil {
+			fatal("Failed to get snapshot", err)
+		}
+
+		names := monitor.SignerNames(ctx)
+		signers := make([]common.Address, 0, len(snapshot.Signers))
+		for signer := range snapshot.Signers {
+			signers = append(signers, signer)
+		}
+		sort.Slice(signers, func(i, j int) bool { return signers[i].Hex() < signers[j].Hex() })
+
+		fmt.Printf("🏛️  Clique signers at block #%d (via %s):\n", snapshot.Number, client.Endpoint())
+		for _, signer := range signers {
+			fmt.Printf("   %-42s %s\n", signer.Hex(), names[signer])
+		}
+
+		if len(snapshot.Tally) == 0 {
+			fmt.Println("🗳️  No pending votes")
+			return
+		}
+		fmt.Println("🗳️  Pending votes:")
+		for address, tally := range snapshot.Tally {
+			action := "remove"
+			if tally.Authorize {
+				action = "add"
+			}
+			fmt.Printf("   %-6s %s (%d/%d votes)\n", action, address.Hex(), tally.Votes, len(signers)/2+1)
+		}
+	},
+}
+
+var validatorsProposeCmd = &cobra.Command{
+	Use:   "propose [add|remove] [address]",
+	Short: "Vote on a majority of signers to add or remove a signer",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var auth bool
+		switch args[0] {
+		case "add":
+			auth = true
+		case "remove":
+			auth = false
+		default:
+			slog.Error(fmt.Sprintf("Unknown action: %s (expected add or remove)", args[0]))
+			os.Exit(1)
+		}
+		if !common.IsHexAddress(args[1]) {
+			slog.Error(fmt.Sprintf("Invalid address: %s", args[1]))
+			os.Exit(1)
+		}
+		address := common.HexToAddress(args[1])
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		fmt.Printf("🗳️  Proposing to %s %s\n", args[0], address.Hex())
+		votes, majority, err := castProposal(ctx, address, auth)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		if votes < majority {
+			slog.Error(fmt.Sprintf("Only %d/%d signers voted, proposal will not pass", votes, majority))
+			os.Exit(1)
+		}
+		fmt.Println("✅ Proposal cast on a majority of signers, it applies as they seal their next blocks")
+	},
+}
+
+// castProposal votes on validator nodes until a majority of the current
+// signers has proposed the change.
+func castProposal(ctx context.Context, address common.Address, auth bool) (votes, majority int, err error) {
+	client, err := monitor.DialValidator()
+	if err != nil {
+		return 0, 0, err
+	}
+	signers, err := client.GetSigners(ctx)
+	client.Close()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get signers: %v", err)
+	}
+	majority = len(signers)/2 + 1
+	fmt.Printf("   %d votes needed\n", majority)
+
+	for _, name := range monitor.ValidatorNodes {
+		if votes >= majority {
+			break
+		}
+		endpoint, _ := monitor.NodeEndpoint(name)
+		voter, err := clique.Dial(endpoint)
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: %v\n", name, err)
+			continue
+		}
+		err = voter.Propose(ctx, address, auth)
+		voter.Close()
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: %v\n", name, err)
+			continue
+		}
+		votes++
+		fmt.Printf("   ✅ %s voted\n", name)
+	}
+	return votes, majority, nil
+}
+
+var validatorsSealersCmd = &cobra.Command{
+	Use:   "sealers",
+	Short: "Show which signer sealed each recent block",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client, err := monitor.DialValidator()
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		defer client.Close()
+
+		blocks, err := client.RecentBlocks(ctx, sealerBlocks)
+		if err != nil {
+			fatal("Failed to read recent blocks", err)
+		}
+
+		names := monitor.SignerNames(ctx)
+
+		fmt.Printf("%-8s %-42s %-12s %-6s\n", "Block", "Sealer", "Node", "Turn")
+		fmt.Println(strings.Repeat("-", 72))
+		for _, block := range blocks {
+			turn := "out"
+			if block.InTurn {
+				turn = "in"
+			}
+			fmt.Printf("#%-7d %-42s %-12s %-6s\n", block.Number, block.Signer.Hex(), names[block.Signer], turn)
+		}
+
+		fmt.Println()
+		fmt.Printf("%-42s %-12s %-7s %-7s %-7s %-8s\n", "Sealer", "Node", "Blocks", "In", "Out", "In-turn")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, stats := range clique.SummarizeSigners(blocks) {
+			fmt.Printf("%-42s %-12s %-7d %-7d %-7d %6.1f%%\n",
+				stats.Signer.Hex(), names[stats.Signer], stats.Blocks, stats.InTurn, stats.OutOfTurn, stats.InTurnRatio()*100)
+		}
+	},
+}
+
+func init() {
+	validatorsSealersCmd.Flags().Uint64VarP(&sealerBlocks, "blocks", "n", 20, "Number of recent blocks to inspect")
+
+	validatorsCmd.AddCommand(validatorsListCmd)
+	validatorsCmd.AddCommand(validatorsProposeCmd)
+	validatorsCmd.AddCommand(validatorsSealersCmd)
+	rootCmd.AddCommand(validatorsCmd)
+}
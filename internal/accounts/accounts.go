@@ -0,0 +1,124 @@
+// Package accounts derives benchmark accounts from a mnemonic and keeps
+// track of them between runs.
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// File keeps the derived accounts.
+const File = "benchy_accounts.json"
+
+// DefaultMnemonic is the well-known development mnemonic of Hardhat and
+// Anvil: its accounts hold nothing of value anywhere.
+const DefaultMnemonic = "test test test test test test test test test test test junk"
+
+// Account is the index-th account of the mnemonic.
+type Account struct {
+	Index   int            `json:"index"`
+	Address common.Address `json:"address"`
+}
+
+// Store is the mnemonic and the accounts derived from it so far. Accounts
+// are derived at Path/index.
+type Store struct {
+	Mnemonic string    `json:"mnemonic"`
+	Path     string    `json:"path"`
+	Accounts []Account `json:"accounts"`
+	seed     []byte
+}
+
+// New starts a store over mnemonic, with the standard Ethereum path.
+func New(mnemonic string) (*Store, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+	return &Store{Mnemonic: mnemonic, Path: gethaccounts.DefaultRootDerivationPath.String()}, nil
+}
+
+// Load returns the store in path, nil if there is none.
+func Load(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	store := &Store{}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if !bip39.IsMnemonicValid(store.Mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic in %s", path)
+	}
+	return store, nil
+}
+
+func (s *Store) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	// The mnemonic controls the accounts
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// Derive adds count accounts after the last one and returns them.
+func (s *Store) Derive(count int) ([]Account, error) {
+	var added []Account
+	next := len(s.Accounts)
+	if next > 0 {
+		next = s.Accounts[next-1].Index + 1
+	}
+	for len(added) < count {
+		key, err := s.Key(next)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, Account{Index: next, Address: crypto.PubkeyToAddress(key.PublicKey)})
+		next++
+	}
+	s.Accounts = append(s.Accounts, added...)
+	return added, nil
+}
+
+// Key derives the private key of the index-th account.
+func (s *Store) Key(index int) (*ecdsa.PrivateKey, error) {
+	if s.seed == nil {
+		s.seed = bip39.NewSeed(s.Mnemonic, "")
+	}
+	base, err := gethaccounts.ParseDerivationPath(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %q: %v", s.Path, err)
+	}
+	path := append(base, uint32(index))
+	key, err := DeriveKey(s.seed, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive account %d: %v", index, err)
+	}
+	return key, nil
+}
+
+func (s *Store) Addresses() []common.Address {
+	addresses := make([]common.Address, len(s.Accounts))
+	for i, account := range s.Accounts {
+		addresses[i] = account.Address
+	}
+	return addresses
+}
@@ -0,0 +1,66 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// errInvalidChild is the one-in-2^127 case where BIP-32 skips an index.
+var errInvalidChild = errors.New("invalid child key, use another index")
+
+// DeriveKey derives the private key at path from a BIP-39 seed, as BIP-32
+// does for wallets.
+func DeriveKey(seed []byte, path gethaccounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key, chainCode, err := split(hmacSHA512([]byte("Bitcoin seed"), seed), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			data = append([]byte{0}, math.PaddedBigBytes(key, 32)...)
+		} else {
+			private, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&private.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+		if key, chainCode, err = split(hmacSHA512(chainCode, data), key); err != nil {
+			return nil, err
+		}
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
+
+// split returns the left half of a BIP-32 HMAC added to parent, and the
+// right half as the chain code.
+func split(digest []byte, parent *big.Int) (*big.Int, []byte, error) {
+	order := crypto.S256().Params().N
+	key := new(big.Int).SetBytes(digest[:32])
+	if key.Cmp(order) >= 0 {
+		return nil, nil, errInvalidChild
+	}
+	if parent != nil {
+		key.Add(key, parent).Mod(key, order)
+	}
+	if key.Sign() == 0 {
+		return nil, nil, errInvalidChild
+	}
+	return key, digest[32:], nil
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
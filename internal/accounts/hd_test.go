@@ -0,0 +1,76 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"testing"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestDeriveKeyBIP32 checks the private keys of BIP-32 test vector 1, which
+// mixes hardened and normal indexes.
+func TestDeriveKeyBIP32(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	vectors := []struct {
+		path string
+		key  string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, vector := range vectors {
+		var path gethaccounts.DerivationPath
+		if vector.path != "m" {
+			var err error
+			if path, err = gethaccounts.ParseDerivationPath(vector.path); err != nil {
+				t.Fatalf("%s: %v", vector.path, err)
+			}
+		}
+		key, err := DeriveKey(seed, path)
+		if err != nil {
+			t.Fatalf("%s: %v", vector.path, err)
+		}
+		if got := hex.EncodeToString(crypto.FromECDSA(key)); got != vector.key {
+			t.Errorf("%s: got key %s, want %s", vector.path, got, vector.key)
+		}
+	}
+}
+
+// TestDeriveHardhatAccounts checks the first accounts of the default
+// mnemonic against the ones Hardhat and Anvil print.
+func TestDeriveHardhatAccounts(t *testing.T) {
+	store, err := New(DefaultMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+		"0x90F79bf6EB2c4f870365E785982E1f101E93b906",
+		"0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65",
+	}
+	accounts, err := store.Derive(len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, account := range accounts {
+		if account.Index != i || account.Address.Hex() != want[i] {
+			t.Errorf("account %d: got #%d %s, want %s", i, account.Index, account.Address.Hex(), want[i])
+		}
+	}
+
+	// Deriving more continues after the last account
+	more, err := store.Derive(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if more[0].Index != len(want) || len(store.Accounts) != len(want)+1 {
+		t.Errorf("got account #%d and %d accounts, want #%d and %d", more[0].Index, len(store.Accounts), len(want), len(want)+1)
+	}
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"benchy/internal/output"
+)
+
+// ErrJobNotFound is returned for unknown job IDs.
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when cancelling a job that already ended.
+var ErrJobFinished = errors.New("job already finished")
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+const jobQueueSize = 64
+
+// JobFunc does the work of a job. What it writes to log becomes the job log
+// and its return value the job result. It should stop early when ctx is
+// done.
+type JobFunc func(ctx context.Context, log io.Writer) (interface{}, error)
+
+// Job is one long running operation. Fields are guarded by the manager lock;
+// use JobManager.Get for a consistent copy.
+type Job struct {
+	ID         string        `json:"id"`
+	Kind       string        `json:"kind"`
+	Target     string        `json:"target,omitempty"`
+	Status     JobStatus     `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+	StartedAt  *time.Time    `json:"started_at,omitempty"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	Result     interface{}   `json:"result,omitempty"`
+	Error      *output.Error `json:"error,omitempty"`
+
+	logs   []string
+	run    JobFunc
+	cancel context.CancelFunc
+	ctx    context.Context
+}
+
+// JobManager runs jobs one at a time in submission order. Operations share
+// the docker-compose project, so they never overlap.
+type JobManager struct {
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	nextID int
+	queue  chan *Job
+	// console echoes the job logs, each line prefixed with its job ID
+	console io.Writer
+}
+
+// NewJobManager echoes the job logs to console, stdout if nil.
+func NewJobManager(console io.Writer) *JobManager {
+	if console == nil {
+		console = os.Stdout
+	}
+	return &JobManager{
+		jobs:    make(map[string]*Job),
+		queue:   make(chan *Job, jobQueueSize),
+		console: console,
+	}
+}
+
+// Start runs queued jobs until ctx is cancelled.
+func (jm *JobManager) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case job := <-jm.queue:
+				jm.execute(job)
+			}
+		}
+	}()
+}
+
+// Submit queues a job and returns a copy of it.
+func (jm *JobManager) Submit(kind, target string, run JobFunc) (Job, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	jm.mu.Lock()
+	jm.nextID++
+	job := &Job{
+		ID:        fmt.Sprintf("job-%d", jm.nextID),
+		Kind:      kind,
+		Target:    target,
+		Status:    JobPending,
+		CreatedAt: time.Now().UTC(),
+		run:       run,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	jm.jobs[job.ID] = job
+	snapshot := *job
+	jm.mu.Unlock()
+
+	select {
+	case jm.queue <- job:
+		return snapshot, nil
+	default:
+		jm.finish(job, nil, fmt.Errorf("job queue is full"))
+		return Job{}, fmt.Errorf("job queue is full (%d jobs waiting)", jobQueueSize)
+	}
+}
+
+// Cancel stops a pending job before it starts or signals a running one.
+func (jm *JobManager) Cancel(id string) (Job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	job, ok := jm.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	switch job.Status {
+	case JobPending, JobRunning:
+		job.cancel()
+		// Pending jobs are skipped by the worker; running ones are marked
+		// once their function returns.
+		if job.Status == JobPending {
+			now := time.Now().UTC()
+			job.Status = JobCancelled
+			job.FinishedAt = &now
+			job.Error = output.NewError(output.CodeJobCancelled, fmt.Errorf("job was cancelled before it started"))
+		}
+		return *job, nil
+	}
+	return *job, ErrJobFinished
+}
+
+func (jm *JobManager) Get(id string) (Job, bool) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	job, ok := jm.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Logs returns the lines a job has written so far.
+func (jm *JobManager) Logs(id string) ([]string, bool) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	job, ok := jm.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return append([]string{}, job.logs...), true
+}
+
+// List returns all jobs, oldest first.
+func (jm *JobManager) List() []Job {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	jobs := make([]Job, 0, len(jm.jobs))
+	for _, job := range jm.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	return jobs
+}
+
+func (jm *JobManager) execute(job *Job) {
+	jm.mu.Lock()
+	if job.Status != JobPending {
+		jm.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	job.Status = JobRunning
+	job.StartedAt = &now
+	jm.mu.Unlock()
+
+	log := &jobLog{jm: jm, job: job}
+	result, err := job.run(job.ctx, log)
+	log.flush()
+
+	jm.finish(job, result, err)
+}
+
+func (jm *JobManager) finish(job *Job, result interface{}, err error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	job.Result = result
+	var structured *output.Error
+	switch {
+	case job.ctx.Err() != nil:
+		job.Status = JobCancelled
+		job.Error = output.NewError(output.CodeJobCancelled, fmt.Errorf("job was cancelled"))
+	case errors.As(err, &structured):
+		job.Status = JobFailed
+		job.Error = structured
+	case err != nil:
+		job.Status = JobFailed
+		job.Error = output.NewError(output.CodeJobFailed, err)
+	default:
+		job.Status = JobSucceeded
+	}
+	job.cancel()
+}
+
+// jobLog splits what a job writes into the lines of its log, echoing each
+// one to the console with the job ID.
+type jobLog struct {
+	jm  *JobManager
+	job *Job
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.partial = append(l.partial, p...)
+	for {
+		end := bytes.IndexByte(l.partial, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		l.add(string(l.partial[:end]))
+		l.partial = l.partial[end+1:]
+	}
+}
+
+// flush adds the last line if the job did not end it.
+func (l *jobLog) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.partial) > 0 {
+		l.add(string(l.partial))
+		l.partial = nil
+	}
+}
+
+func (l *jobLog) add(line string) {
+	fmt.Fprintf(l.jm.console, "[%s] %s\n", l.job.ID, line)
+	l.jm.mu.Lock()
+	l.job.logs = append(l.job.logs, line)
+	l.jm.mu.Unlock()
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a console written by the job worker and read by the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitJob polls a job until it reaches status.
+func waitJob(t *testing.T, jm *JobManager, id string, status JobStatus) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, ok := jm.Get(id)
+		if !ok {
+			t.Fatalf("%s is unknown", id)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s is %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func succeed(context.Context, io.Writer) (interface{}, error) {
+	return nil, nil
+}
+
+func TestJobLogs(t *testing.T) {
+	console := &syncBuffer{}
+	jm := NewJobManager(console)
+	jm.Start(t.Context())
+
+	stdout := os.Stdout
+	job, err := jm.Submit("scenario", "1", func(ctx context.Context, log io.Writer) (interface{}, error) {
+		if os.Stdout != stdout {
+			return nil, fmt.Errorf("the job runs with stdout swapped")
+		}
+		fmt.Fprintln(log, "first line")
+		fmt.Fprint(log, "second ")
+		fmt.Fprintln(log, "line")
+		fmt.Fprint(log, "unfinished")
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := waitJob(t, jm, job.ID, JobSucceeded)
+	if done.Result != "done" {
+		t.Errorf("got result %v, want done", done.Result)
+	}
+
+	logs, _ := jm.Logs(job.ID)
+	want := []string{"first line", "second line", "unfinished"}
+	if fmt.Sprint(logs) != fmt.Sprint(want) {
+		t.Errorf("got logs %q, want %q", logs, want)
+	}
+	echoed := "[job-1] first line\n[job-1] second line\n[job-1] unfinished\n"
+	if console.String() != echoed {
+		t.Errorf("got console %q, want %q", console.String(), echoed)
+	}
+}
+
+func TestJobFailure(t *testing.T) {
+	jm := NewJobManager(io.Discard)
+	jm.Start(t.Context())
+
+	job, err := jm.Submit("launch", "", func(context.Context, io.Writer) (interface{}, error) {
+		return nil, errors.New("docker is down")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := waitJob(t, jm, job.ID, JobFailed)
+	if failed.Error == nil || failed.Error.Message != "docker is down" {
+		t.Errorf("got error %+v, want docker is down", failed.Error)
+	}
+	if _, err := jm.Cancel(job.ID); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("cancelling a finished job: got %v, want %v", err, ErrJobFinished)
+	}
+	if _, err := jm.Cancel("job-42"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("cancelling an unknown job: got %v, want %v", err, ErrJobNotFound)
+	}
+}
+
+func TestJobQueueFull(t *testing.T) {
+	// Not started, so every job stays queued
+	jm := NewJobManager(io.Discard)
+	for i := 0; i < jobQueueSize; i++ {
+		if _, err := jm.Submit("scenario", "1", succeed); err != nil {
+			t.Fatalf("job %d: %v", i+1, err)
+		}
+	}
+	if _, err := jm.Submit("scenario", "1", succeed); err == nil {
+		t.Fatal("a job was queued past the queue size")
+	}
+
+	rejected, ok := jm.Get(fmt.Sprintf("job-%d", jobQueueSize+1))
+	if !ok || rejected.Status != JobFailed {
+		t.Errorf("got the rejected job %+v, want it failed", rejected)
+	}
+	if pending := len(jm.List()); pending != jobQueueSize+1 {
+		t.Errorf("got %d jobs, want %d", pending, jobQueueSize+1)
+	}
+}
+
+func TestCancelPendingJob(t *testing.T) {
+	jm := NewJobManager(io.Discard)
+
+	ran := make(chan struct{}, 1)
+	job, err := jm.Submit("scenario", "2", func(context.Context, io.Writer) (interface{}, error) {
+		ran <- struct{}{}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancelled, err := jm.Cancel(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled.Status != JobCancelled || cancelled.FinishedAt == nil {
+		t.Errorf("got %+v, want the job cancelled", cancelled)
+	}
+
+	// The worker skips it and goes on with the next job
+	next, err := jm.Submit("scenario", "1", succeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jm.Start(t.Context())
+	waitJob(t, jm, next.ID, JobSucceeded)
+	select {
+	case <-ran:
+		t.Error("the cancelled job ran")
+	default:
+	}
+	if job, _ := jm.Get(job.ID); job.Status != JobCancelled {
+		t.Errorf("got %s, want the job still cancelled", job.Status)
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	jm := NewJobManager(io.Discard)
+	jm.Start(t.Context())
+
+	started := make(chan struct{})
+	job, err := jm.Submit("fault", "alice", func(ctx context.Context, log io.Writer) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		fmt.Fprintln(log, "stopped early")
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	running, err := jm.Cancel(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if running.Status != JobRunning {
+		t.Errorf("got %s, want the job running until its function returns", running.Status)
+	}
+
+	cancelled := waitJob(t, jm, job.ID, JobCancelled)
+	if cancelled.Error == nil || cancelled.FinishedAt == nil {
+		t.Errorf("got %+v, want the cancellation reported", cancelled)
+	}
+	if logs, _ := jm.Logs(job.ID); len(logs) != 1 || logs[0] != "stopped early" {
+		t.Errorf("got logs %q, want the output after the cancellation", logs)
+	}
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"benchy/internal/output"
+)
+
+const defaultFaultDuration = 40 * time.Second // same as temporary-failure
+
+// Backend performs the operations exposed by the API. Operations run as jobs
+// write their console output to log.
+type Backend interface {
+	Launch(ctx context.Context, log io.Writer) error
+	Clean(ctx context.Context, log io.Writer) error
+	Status(ctx context.Context) (interface{}, error)
+	RunScenario(ctx context.Context, log io.Writer, scenario string) (interface{}, error)
+	InjectFault(ctx context.Context, node string, duration time.Duration) (interface{}, error)
+}
+
+// Server is the HTTP control plane. Launch, clean, scenarios and faults run
+// as jobs; status is answered synchronously.
+type Server struct {
+	backend Backend
+	jobs    *JobManager
+	mux     *http.ServeMux
+}
+
+// NewServer echoes the job logs to console, stdout if nil.
+func NewServer(backend Backend, console io.Writer) *Server {
+	s := &Server{backend: backend, jobs: NewJobManager(console), mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /v1/status", s.handleStatus)
+	s.mux.HandleFunc("POST /v1/network/launch", s.handleLaunch)
+	s.mux.HandleFunc("POST /v1/network/clean", s.handleClean)
+	s.mux.HandleFunc("POST /v1/scenarios/{scenario}", s.handleScenario)
+	s.mux.HandleFunc("POST /v1/faults", s.handleFault)
+	s.mux.HandleFunc("GET /v1/jobs", s.handleListJobs)
+	s.mux.HandleFunc("GET /v1/jobs/{id}", s.handleGetJob)
+	s.mux.HandleFunc("GET /v1/jobs/{id}/logs", s.handleJobLogs)
+	s.mux.HandleFunc("POST /v1/jobs/{id}/cancel", s.handleCancelJob)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves on addr until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	s.jobs.Start(ctx)
+
+	httpServer := &http.Server{Addr: addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.backend.Status(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, output.NewError(output.CodeNetworkInfoFailed, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleLaunch(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, "launch", "", func(ctx context.Context, log io.Writer) (interface{}, error) {
+		return nil, s.backend.Launch(ctx, log)
+	})
+}
+
+func (s *Server) handleClean(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, "clean", "", func(ctx context.Context, log io.Writer) (interface{}, error) {
+		return nil, s.backend.Clean(ctx, log)
+	})
+}
+
+func (s *Server) handleScenario(w http.ResponseWriter, r *http.Request) {
+	scenario := r.PathValue("scenario")
+	s.submit(w, "scenario", scenario, func(ctx context.Context, log io.Writer) (interface{}, error) {
+		return s.backend.RunScenario(ctx, log, scenario)
+	})
+}
+
+type faultRequest struct {
+	Node            string `json:"node"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+func (s *Server) handleFault(w http.ResponseWriter, r *http.Request) {
+	var request faultRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, output.NewError(output.CodeInvalidArgument, fmt.Errorf("invalid body: %v", err)))
+		return
+	}
+	if request.Node == "" {
+		writeError(w, http.StatusBadRequest, output.NewError(output.CodeInvalidArgument, fmt.Errorf("node is required")))
+		return
+	}
+	duration := defaultFaultDuration
+	if request.DurationSeconds > 0 {
+		duration = time.Duration(request.DurationSeconds) * time.Second
+	}
+
+	s.submit(w, "fault", request.Node, func(ctx context.Context, log io.Writer) (interface{}, error) {
+		return s.backend.InjectFault(ctx, request.Node, duration)
+	})
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := s.jobs.List()
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		filtered := jobs[:0]
+		for _, job := range jobs {
+			if job.Kind == kind {
+				filtered = append(filtered, job)
+			}
+		}
+		jobs = filtered
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"jobs": jobs})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.Get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, output.NewError(output.CodeNotFound, ErrJobNotFound))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleJobLogs returns the log lines, starting at ?since=N for polling.
+func (s *Server) handleJobLogs(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	logs, ok := s.jobs.Logs(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, output.NewError(output.CodeNotFound, ErrJobNotFound))
+		return
+	}
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+	if since < 0 || since > len(logs) {
+		since = len(logs)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "next": len(logs), "lines": logs[since:]})
+}
+
+func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Cancel(r.PathValue("id"))
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		writeError(w, http.StatusNotFound, output.NewError(output.CodeNotFound, err))
+	case errors.Is(err, ErrJobFinished):
+		writeError(w, http.StatusConflict, output.NewError(output.CodeConflict, err))
+	default:
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+func (s *Server) submit(w http.ResponseWriter, kind, target string, run JobFunc) {
+	job, err := s.jobs.Submit(kind, target, run)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, output.NewError(output.CodeConflict, err))
+		return
+	}
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, structured *output.Error) {
+	writeJSON(w, status, output.ErrorRecord{Error: structured})
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"benchy/internal/output"
+)
+
+// fakeBackend prints a line per operation and fails the ones given an error.
+type fakeBackend struct {
+	statusErr error
+	faults    chan time.Duration
+}
+
+func (fakeBackend) Launch(ctx context.Context, log io.Writer) error {
+	fmt.Fprintln(log, "launching")
+	return nil
+}
+
+func (fakeBackend) Clean(ctx context.Context, log io.Writer) error {
+	fmt.Fprintln(log, "cleaning")
+	return nil
+}
+
+func (b fakeBackend) Status(ctx context.Context) (interface{}, error) {
+	if b.statusErr != nil {
+		return nil, b.statusErr
+	}
+	return map[string]int{"nodes": 3}, nil
+}
+
+func (fakeBackend) RunScenario(ctx context.Context, log io.Writer, scenario string) (interface{}, error) {
+	for _, step := range []string{"connect", "send", "check"} {
+		fmt.Fprintf(log, "scenario %s: %s\n", scenario, step)
+	}
+	return map[string]string{"scenario": scenario}, nil
+}
+
+func (b fakeBackend) InjectFault(ctx context.Context, node string, duration time.Duration) (interface{}, error) {
+	b.faults <- duration
+	return nil, nil
+}
+
+func newTestServer(t *testing.T, backend Backend) *httptest.Server {
+	t.Helper()
+	s := NewServer(backend, io.Discard)
+	s.jobs.Start(t.Context())
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// do sends a request and decodes the JSON answer into v.
+func do(t *testing.T, method, url, body string, wantStatus int, v interface{}) *http.Response {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != wantStatus {
+		data, _ := io.ReadAll(response.Body)
+		t.Fatalf("%s %s: got %d, want %d: %s", method, url, response.StatusCode, wantStatus, data)
+	}
+	if v != nil {
+		if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, url, err)
+		}
+	}
+	return response
+}
+
+// awaitJob polls the job endpoint until the job is no longer queued or running.
+func awaitJob(t *testing.T, url string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var job Job
+		do(t, http.MethodGet, url, "", http.StatusOK, &job)
+		if job.Status != JobPending && job.Status != JobRunning {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s is still %s", job.ID, job.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestScenarioJob(t *testing.T) {
+	server := newTestServer(t, fakeBackend{})
+
+	var job Job
+	response := do(t, http.MethodPost, server.URL+"/v1/scenarios/1", "", http.StatusAccepted, &job)
+	location := response.Header.Get("Location")
+	if location != "/v1/jobs/"+job.ID || job.Kind != "scenario" || job.Target != "1" {
+		t.Fatalf("got job %+v at %q", job, location)
+	}
+
+	done := awaitJob(t, server.URL+location)
+	if done.Status != JobSucceeded {
+		t.Fatalf("got %s: %+v", done.Status, done.Error)
+	}
+	if result, _ := done.Result.(map[string]interface{}); result["scenario"] != "1" {
+		t.Errorf("got result %v, want scenario 1", done.Result)
+	}
+
+	var logs struct {
+		Next  int      `json:"next"`
+		Lines []string `json:"lines"`
+	}
+	do(t, http.MethodGet, server.URL+location+"/logs", "", http.StatusOK, &logs)
+	if logs.Next != 3 || len(logs.Lines) != 3 || logs.Lines[0] != "scenario 1: connect" {
+		t.Errorf("got logs %+v, want the 3 steps", logs)
+	}
+	do(t, http.MethodGet, server.URL+location+"/logs?since=2", "", http.StatusOK, &logs)
+	if logs.Next != 3 || len(logs.Lines) != 1 || logs.Lines[0] != "scenario 1: check" {
+		t.Errorf("got logs %+v since 2, want the last step", logs)
+	}
+
+	var record output.ErrorRecord
+	do(t, http.MethodPost, server.URL+location+"/cancel", "", http.StatusConflict, &record)
+	if record.Error == nil || record.Error.Code != output.CodeConflict {
+		t.Errorf("got %+v, want a conflict cancelling a finished job", record.Error)
+	}
+}
+
+func TestListJobs(t *testing.T) {
+	server := newTestServer(t, fakeBackend{})
+
+	var launch, clean Job
+	do(t, http.MethodPost, server.URL+"/v1/network/launch", "", http.StatusAccepted, &launch)
+	do(t, http.MethodPost, server.URL+"/v1/network/clean", "", http.StatusAccepted, &clean)
+	awaitJob(t, server.URL+"/v1/jobs/"+clean.ID)
+
+	var list struct {
+		Jobs []Job `json:"jobs"`
+	}
+	do(t, http.MethodGet, server.URL+"/v1/jobs", "", http.StatusOK, &list)
+	if len(list.Jobs) != 2 || list.Jobs[0].ID != launch.ID || list.Jobs[1].ID != clean.ID {
+		t.Errorf("got jobs %+v, want launch then clean", list.Jobs)
+	}
+	do(t, http.MethodGet, server.URL+"/v1/jobs?kind=clean", "", http.StatusOK, &list)
+	if len(list.Jobs) != 1 || list.Jobs[0].ID != clean.ID {
+		t.Errorf("got jobs %+v, want only clean", list.Jobs)
+	}
+}
+
+func TestUnknownJob(t *testing.T) {
+	server := newTestServer(t, fakeBackend{})
+
+	for _, request := range []struct{ method, path string }{
+		{http.MethodGet, "/v1/jobs/job-42"},
+		{http.MethodGet, "/v1/jobs/job-42/logs"},
+		{http.MethodPost, "/v1/jobs/job-42/cancel"},
+	} {
+		var record output.ErrorRecord
+		do(t, request.method, server.URL+request.path, "", http.StatusNotFound, &record)
+		if record.Error == nil || record.Error.Code != output.CodeNotFound {
+			t.Errorf("%s %s: got %+v, want not found", request.method, request.path, record.Error)
+		}
+	}
+}
+
+func TestFault(t *testing.T) {
+	backend := fakeBackend{faults: make(chan time.Duration, 2)}
+	server := newTestServer(t, backend)
+
+	for _, body := range []string{`{"node": `, `{"duration_seconds": 5}`} {
+		var record output.ErrorRecord
+		do(t, http.MethodPost, server.URL+"/v1/faults", body, http.StatusBadRequest, &record)
+		if record.Error == nil || record.Error.Code != output.CodeInvalidArgument {
+			t.Errorf("%s: got %+v, want an invalid argument", body, record.Error)
+		}
+	}
+
+	var job Job
+	do(t, http.MethodPost, server.URL+"/v1/faults", `{"node": "alice"}`, http.StatusAccepted, &job)
+	if job.Kind != "fault" || job.Target != "alice" {
+		t.Errorf("got job %+v, want a fault on alice", job)
+	}
+	if duration := <-backend.faults; duration != defaultFaultDuration {
+		t.Errorf("got a fault of %s, want the default %s", duration, defaultFaultDuration)
+	}
+	do(t, http.MethodPost, server.URL+"/v1/faults", `{"node": "bob", "duration_seconds": 5}`, http.StatusAccepted, nil)
+	if duration := <-backend.faults; duration != 5*time.Second {
+		t.Errorf("got a fault of %s, want 5s", duration)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	server := newTestServer(t, fakeBackend{})
+	var status map[string]int
+	do(t, http.MethodGet, server.URL+"/v1/status", "", http.StatusOK, &status)
+	if status["nodes"] != 3 {
+		t.Errorf("got status %v, want 3 nodes", status)
+	}
+
+	failing := newTestServer(t, fakeBackend{statusErr: errors.New("alice is unreachable")})
+	var record output.ErrorRecord
+	do(t, http.MethodGet, failing.URL+"/v1/status", "", http.StatusBadGateway, &record)
+	if record.Error == nil || record.Error.Code != output.CodeNetworkInfoFailed {
+		t.Errorf("got %+v, want the network info failure", record.Error)
+	}
+}
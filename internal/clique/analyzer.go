@@ -0,0 +1,192 @@
+package clique
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProducerStats describes how one signer performed over the analyzed window.
+type ProducerStats struct {
+	Signer        common.Address
+	Blocks        int
+	InTurn        int
+	OutOfTurn     int
+	ExpectedTurns int
+	MissedTurns   int
+}
+
+func (p ProducerStats) Share(total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(p.Blocks) / float64(total)
+}
+
+type BlockTimeStats struct {
+	Samples      int
+	Average      float64
+	Min          uint64
+	Max          uint64
+	MaxDeviation float64
+	Late         int
+}
+
+type BlockReport struct {
+	FromBlock  uint64
+	ToBlock    uint64
+	Period     uint64
+	Producers  []ProducerStats
+	BlockTimes BlockTimeStats
+}
+
+func (r *BlockReport) TotalBlocks() int {
+	if r.ToBlock < r.FromBlock {
+		return 0
+	}
+	return int(r.ToBlock - r.FromBlock + 1)
+}
+
+type BlockAnalyzer struct {
+	client *Client
+	period uint64
+}
+
+func NewBlockAnalyzer(client *Client, period uint64) *BlockAnalyzer {
+	return &BlockAnalyzer{client: client, period: period}
+}
+
+// Analyze walks the last count blocks and attributes each one to its sealer.
+// The signer set is taken at the head and assumed stable over the window.
+func (ba *BlockAnalyzer) Analyze(ctx context.Context, count uint64) (*BlockReport, error) {
+	signers, err := ba.client.GetSigners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := ba.client.RecentBlocks(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeBlocks(blocks, signers, ba.period), nil
+}
+
+// AnalyzeBlocks computes producer and block-time statistics. Clique rotates
+// the in-turn slot over the signers sorted by address.
+func AnalyzeBlocks(blocks []SealedBlock, signers []common.Address, period uint64) *BlockReport {
+	report := &BlockReport{Period: period}
+	if len(blocks) == 0 {
+		return report
+	}
+	report.FromBlock = blocks[0].Number
+	report.ToBlock = blocks[len(blocks)-1].Number
+
+	sorted := make([]common.Address, len(signers))
+	copy(sorted, signers)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i][:], sorted[j][:]) < 0 })
+
+	producers := make(map[common.Address]*ProducerStats)
+	producer := func(signer common.Address) *ProducerStats {
+		stats, ok := producers[signer]
+		if !ok {
+			stats = &ProducerStats{Signer: signer}
+			producers[signer] = stats
+		}
+		return stats
+	}
+	for _, signer := range sorted {
+		producer(signer)
+	}
+
+	for _, block := range blocks {
+		sealer := producer(block.Signer)
+		sealer.Blocks++
+		if block.InTurn {
+			sealer.InTurn++
+		} else {
+			sealer.OutOfTurn++
+		}
+
+		if len(sorted) == 0 {
+			continue
+		}
+		expected := producer(sorted[block.Number%uint64(len(sorted))])
+		expected.ExpectedTurns++
+		if expected.Signer != block.Signer {
+			expected.MissedTurns++
+		}
+	}
+
+	for _, stats := range producers {
+		report.Producers = append(report.Producers, *stats)
+	}
+	sort.Slice(report.Producers, func(i, j int) bool {
+		return bytes.Compare(report.Producers[i].Signer[:], report.Producers[j].Signer[:]) < 0
+	})
+
+	report.BlockTimes = blockTimeStats(blocks, period)
+	return report
+}
+
+func blockTimeStats(blocks []SealedBlock, period uint64) BlockTimeStats {
+	var stats BlockTimeStats
+	var total uint64
+
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].Time < blocks[i-1].Time {
+			continue
+		}
+		delta := blocks[i].Time - blocks[i-1].Time
+		total += delta
+		stats.Samples++
+
+		if stats.Samples == 1 || delta < stats.Min {
+			stats.Min = delta
+		}
+		if delta > stats.Max {
+			stats.Max = delta
+		}
+		deviation := float64(delta) - float64(period)
+		if deviation < 0 {
+			deviation = -deviation
+		}
+		if deviation > stats.MaxDeviation {
+			stats.MaxDeviation = deviation
+		}
+		if delta > period {
+			stats.Late++
+		}
+	}
+
+	if stats.Samples > 0 {
+		stats.Average = float64(total) / float64(stats.Samples)
+	}
+	return stats
+}
+
+// LoadPeriod reads the Clique block period from a genesis file.
+func LoadPeriod(genesisPath string) (uint64, error) {
+	data, err := os.ReadFile(genesisPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read genesis %s: %v", genesisPath, err)
+	}
+
+	var genesis struct {
+		Config struct {
+			Clique *struct {
+				Period uint64 `json:"period"`
+			} `json:"clique"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return 0, fmt.Errorf("failed to parse genesis %s: %v", genesisPath, err)
+	}
+	if genesis.Config.Clique == nil {
+		return 0, fmt.Errorf("genesis %s has no clique config", genesisPath)
+	}
+	return genesis.Config.Clique.Period, nil
+}
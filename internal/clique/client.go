@@ -0,0 +1,117 @@
+package clique
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrNotClique is returned by the clique_* calls of a node that does not
+// serve the namespace, such as geth --dev.
+var ErrNotClique = errors.New("network is not Clique")
+
+// Snapshot is the voting state returned by clique_getSnapshot. Signer values
+// are kept raw because Geth and Nethermind encode them differently.
+type Snapshot struct {
+	Number  uint64                             `json:"number"`
+	Hash    common.Hash                        `json:"hash"`
+	Signers map[common.Address]json.RawMessage `json:"signers"`
+	Recents map[uint64]common.Address          `json:"recents"`
+	Votes   []*Vote                            `json:"votes"`
+	Tally   map[common.Address]Tally           `json:"tally"`
+}
+
+type Vote struct {
+	Signer    common.Address `json:"signer"`
+	Block     uint64         `json:"block"`
+	Address   common.Address `json:"address"`
+	Authorize bool           `json:"authorize"`
+}
+
+type Tally struct {
+	Authorize bool `json:"authorize"`
+	Votes     int  `json:"votes"`
+}
+
+// Client talks to the clique_* namespace of a single node.
+type Client struct {
+	endpoint string
+	rpc      *rpc.Client
+	eth      *ethclient.Client
+}
+
+func Dial(endpoint string) (*Client, error) {
+	rpcClient, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %v", endpoint, err)
+	}
+	return &Client{
+		endpoint: endpoint,
+		rpc:      rpcClient,
+		eth:      ethclient.NewClient(rpcClient),
+	}, nil
+}
+
+func (c *Client) Close() {
+	c.rpc.Close()
+}
+
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+func (c *Client) GetSigners(ctx context.Context) ([]common.Address, error) {
+	var signers []common.Address
+	if err := c.call(ctx, &signers, "clique_getSigners", "latest"); err != nil {
+		return nil, err
+	}
+	return signers, nil
+}
+
+func (c *Client) GetSnapshot(ctx context.Context) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := c.call(ctx, &snapshot, "clique_getSnapshot", "latest"); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Propose casts this node's vote to add (auth=true) or remove a signer.
+func (c *Client) Propose(ctx context.Context, address common.Address, auth bool) error {
+	return c.call(ctx, nil, "clique_propose", address, auth)
+}
+
+func (c *Client) Discard(ctx context.Context, address common.Address) error {
+	return c.call(ctx, nil, "clique_discard", address)
+}
+
+// call runs a clique_* method, telling a node without the namespace apart
+// from a failed call.
+func (c *Client) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	err := c.rpc.CallContext(ctx, result, method, args...)
+	if err == nil {
+		return nil
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFound {
+		return fmt.Errorf("%w: %s does not serve %s (geth --dev?)", ErrNotClique, c.endpoint, method)
+	}
+	return fmt.Errorf("%s failed on %s: %v", method, c.endpoint, err)
+}
+
+// methodNotFound is the JSON-RPC code of an unknown method.
+const methodNotFound = -32601
+
+// Coinbase returns the account the node seals blocks with.
+func (c *Client) Coinbase(ctx context.Context) (common.Address, error) {
+	var coinbase common.Address
+	if err := c.rpc.CallContext(ctx, &coinbase, "eth_coinbase"); err != nil {
+		return common.Address{}, fmt.Errorf("eth_coinbase failed on %s: %v", c.endpoint, err)
+	}
+	return coinbase, nil
+}
@@ -0,0 +1,137 @@
+package clique
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Clique encodes whether a block was sealed in turn in its difficulty.
+var diffInTurn = big.NewInt(2)
+
+type SealedBlock struct {
+	Number uint64
+	Hash   common.Hash
+	Time   uint64
+	Signer common.Address
+	InTurn bool
+}
+
+type SignerStats struct {
+	Signer    common.Address
+	Blocks    int
+	InTurn    int
+	OutOfTurn int
+}
+
+func (s SignerStats) InTurnRatio() float64 {
+	if s.Blocks == 0 {
+		return 0
+	}
+	return float64(s.InTurn) / float64(s.Blocks)
+}
+
+// RecoverSigner extracts the sealer address from the signature stored at the
+// end of the header extra-data.
+func RecoverSigner(header *types.Header) (common.Address, error) {
+	if len(header.Extra) < crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("block %v has no seal in extra-data", header.Number)
+	}
+	signature := header.Extra[len(header.Extra)-crypto.SignatureLength:]
+
+	pubkey, err := crypto.Ecrecover(clique.SealHash(header).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer of block %v: %v", header.Number, err)
+	}
+
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	return signer, nil
+}
+
+func IsInTurn(header *types.Header) bool {
+	return header.Difficulty != nil && header.Difficulty.Cmp(diffInTurn) == 0
+}
+
+func SealedBlockFromHeader(header *types.Header) (SealedBlock, error) {
+	signer, err := RecoverSigner(header)
+	if err != nil {
+		return SealedBlock{}, err
+	}
+	return SealedBlock{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+		Time:   header.Time,
+		Signer: signer,
+		InTurn: IsInTurn(header),
+	}, nil
+}
+
+// RecentBlocks walks back from the head and returns up to count sealed blocks,
+// oldest first. The genesis block carries no seal and is skipped.
+func (c *Client) RecentBlocks(ctx context.Context, count uint64) ([]SealedBlock, error) {
+	head, err := c.eth.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head on %s: %v", c.endpoint, err)
+	}
+
+	start := uint64(1)
+	if head >= count {
+		start = head - count + 1
+	}
+	if start == 0 {
+		start = 1
+	}
+
+	var blocks []SealedBlock
+	for number := start; number <= head; number++ {
+		header, err := c.eth.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get header %d on %s: %v", number, c.endpoint, err)
+		}
+		block, err := SealedBlockFromHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// SummarizeSigners aggregates blocks per sealer, sorted by block count.
+func SummarizeSigners(blocks []SealedBlock) []SignerStats {
+	bySigner := make(map[common.Address]*SignerStats)
+	for _, block := range blocks {
+		stats, ok := bySigner[block.Signer]
+		if !ok {
+			stats = &SignerStats{Signer: block.Signer}
+			bySigner[block.Signer] = stats
+		}
+		stats.Blocks++
+		if block.InTurn {
+			stats.InTurn++
+		} else {
+			stats.OutOfTurn++
+		}
+	}
+
+	result := make([]SignerStats, 0, len(bySigner))
+	for _, stats := range bySigner {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Blocks != result[j].Blocks {
+			return result[i].Blocks > result[j].Blocks
+		}
+		return result[i].Signer.Hex() < result[j].Signer.Hex()
+	})
+
+	return result
+}
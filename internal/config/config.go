@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Node describes one node of the benchmarked network.
+type Node struct {
+	Name      string `yaml:"name"`
+	Client    string `yaml:"client"`
+	Endpoint  string `yaml:"endpoint"`
+	WSURL     string `yaml:"ws,omitempty"`
+	Address   string `yaml:"address"`
+	Validator bool   `yaml:"validator"`
+	// DataDir is the chain directory inside the container, for snapshots
+	DataDir string `yaml:"datadir,omitempty"`
+}
+
+// ContainerDataDir returns DataDir or the geth default.
+func (n Node) ContainerDataDir() string {
+	if n.DataDir == "" {
+		return "/root/.ethereum"
+	}
+	return n.DataDir
+}
+
+// DisplayName is the capitalized node name used in console output.
+func (n Node) DisplayName() string {
+	if n.Name == "" {
+		return ""
+	}
+	return strings.ToUpper(n.Name[:1]) + n.Name[1:]
+}
+
+// Backends running the nodes.
+const (
+	BackendDocker = "docker"
+	// BackendInproc is the devnet started by launch-network --backend inproc
+	BackendInproc = "inproc"
+)
+
+// Config is loaded from --config. Missing fields keep their defaults.
+type Config struct {
+	Backend    string `yaml:"backend"`
+	ComposeDir string `yaml:"compose_dir"`
+	Genesis    string `yaml:"genesis"`
+	// NodesFile lists nodes added with `benchy node add`, on top of Nodes
+	NodesFile   string `yaml:"nodes_file"`
+	SnapshotDir string `yaml:"snapshot_dir"`
+	// RunsDir holds one directory of logs per benchy invocation
+	RunsDir string `yaml:"runs_dir"`
+	Nodes   []Node `yaml:"nodes"`
+}
+
+// Default matches docker/docker-compose.yml.
+func Default() *Config {
+	return &Config{
+		Backend:     BackendDocker,
+		ComposeDir:  "docker",
+		Genesis:     "docker/genesis.json",
+		NodesFile:   "docker/benchy-nodes.yml",
+		SnapshotDir: "snapshots",
+		RunsDir:     "runs",
+		Nodes: []Node{
+			{Name: "alice", Client: "Geth", Endpoint: "http://localhost:8545", WSURL: "ws://localhost:8546",
+				Address: "0x71562b71999873db5b286df957af199ec94617f7", Validator: true},
+			{Name: "bob", Client: "Nethermind", Endpoint: "http://localhost:8547",
+				Address: "0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1", Validator: true},
+			{Name: "cassandra", Client: "Geth", Endpoint: "http://localhost:8549",
+				Address: "0x571a6be5bfe6990543da4ba819d3c1c54567ea0b", Validator: true},
+			{Name: "driss", Client: "Nethermind", Endpoint: "http://localhost:8551",
+				Address: "0x9876543210fedcba9876543210fedcba98765431"},
+			{Name: "elena", Client: "Geth", Endpoint: "http://localhost:8553",
+				Address: "0x9876543210fedcba9876543210fedcba98765432"},
+		},
+	}
+}
+
+// Load reads a YAML config over the defaults. An empty path returns the
+// defaults.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+		}
+	}
+
+	added, err := ReadAddedNodes(cfg.NodesFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Nodes = append(cfg.Nodes, added...)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	return cfg, nil
+}
+
+// Write saves the config as YAML, for Load.
+func (c *Config) Write(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// ReadAddedNodes returns the nodes recorded in path, none if it is missing.
+func ReadAddedNodes(path string) ([]Node, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	var nodes []Node
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return nodes, nil
+}
+
+// WriteAddedNodes replaces the nodes recorded in path.
+func WriteAddedNodes(path string, nodes []Node) error {
+	if len(nodes) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", path, err)
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	if c.Backend != BackendDocker && c.Backend != BackendInproc {
+		return fmt.Errorf("unknown backend %q (use %s or %s)", c.Backend, BackendDocker, BackendInproc)
+	}
+	if len(c.Nodes) == 0 {
+		return fmt.Errorf("no nodes defined")
+	}
+	seen := make(map[string]bool)
+	// Each node has its own account, or balances and transactions would be
+	// attributed to the wrong node
+	owners := make(map[string]string)
+	for i, node := range c.Nodes {
+		if node.Name == "" {
+			return fmt.Errorf("node %d has no name", i)
+		}
+		if node.Endpoint == "" {
+			return fmt.Errorf("node %s has no endpoint", node.Name)
+		}
+		if seen[node.Name] {
+			return fmt.Errorf("node %s is defined twice", node.Name)
+		}
+		seen[node.Name] = true
+		if node.Address == "" {
+			continue
+		}
+		address := strings.ToLower(node.Address)
+		if owner, ok := owners[address]; ok {
+			return fmt.Errorf("nodes %s and %s share the address %s", owner, node.Name, node.Address)
+		}
+		owners[address] = node.Name
+	}
+	return nil
+}
+
+// Select keeps only the named nodes, in config order. Unknown names are an
+// error listing the valid ones.
+func (c *Config) Select(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var selected []Node
+	for _, node := range c.Nodes {
+		if wanted[node.Name] {
+			selected = append(selected, node)
+			delete(wanted, node.Name)
+		}
+	}
+	if len(wanted) > 0 {
+		var unknown []string
+		for name := range wanted {
+			unknown = append(unknown, name)
+		}
+		return fmt.Errorf("unknown nodes: %s (known: %s)", strings.Join(unknown, ", "), strings.Join(c.NodeNames(), ", "))
+	}
+
+	c.Nodes = selected
+	return nil
+}
+
+func (c *Config) NodeNames() []string {
+	names := make([]string, 0, len(c.Nodes))
+	for _, node := range c.Nodes {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+// Endpoints maps node names to their JSON-RPC endpoint.
+func (c *Config) Endpoints() map[string]string {
+	endpoints := make(map[string]string, len(c.Nodes))
+	for _, node := range c.Nodes {
+		endpoints[node.Name] = node.Endpoint
+	}
+	return endpoints
+}
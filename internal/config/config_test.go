@@ -0,0 +1,20 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultNodesHaveTheirOwnAddress(t *testing.T) {
+	cfg := Default()
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Addresses compare regardless of case
+	cfg.Nodes[2].Address = "0x" + strings.ToUpper(cfg.Nodes[0].Address[2:])
+	err := cfg.validate()
+	if err == nil || !strings.Contains(err.Error(), "alice and cassandra share the address") {
+		t.Errorf("got %v, want alice and cassandra reported sharing an address", err)
+	}
+}
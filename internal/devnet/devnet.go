@@ -0,0 +1,100 @@
+// Package devnet runs a Clique network of go-ethereum nodes inside the benchy
+// process, for developing scenarios without Docker.
+//
+// go-ethereum's node package links against Go runtime internals that Go 1.23
+// and later refuse by default, so the nodes are only built with the inproc
+// tag and -ldflags=-checklinkname=0 (make build-inproc).
+package devnet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"benchy/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ConfigFile is where launch-network --backend inproc writes the config
+// other benchy commands use to reach the devnet.
+const ConfigFile = "benchy-inproc.yml"
+
+// GenesisFile is the genesis the devnet actually started from, with its
+// signers and funded accounts, referenced by ConfigFile.
+const GenesisFile = "benchy-inproc-genesis.json"
+
+var (
+	// NodeBalance funds the signing account of every node.
+	NodeBalance = new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e18))
+	// AccountBalance funds the addresses of the configured nodes.
+	AccountBalance = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+)
+
+// ErrNotBuilt is returned by Start in binaries built without the inproc tag.
+var ErrNotBuilt = errors.New("this benchy binary has no in-process backend, build it with `make build-inproc`")
+
+// Options selects the nodes and the genesis template of a devnet.
+type Options struct {
+	Nodes []config.Node
+	// Genesis is a genesis file whose chain config, gas limit and alloc are
+	// reused. Its Clique signers are replaced by the validator nodes.
+	Genesis string
+	// Period overrides the Clique block period when not zero.
+	Period uint64
+}
+
+// buildGenesis reads the template and makes the validator keys the Clique
+// signers.
+func buildGenesis(opts Options, keys []*ecdsa.PrivateKey) (*core.Genesis, error) {
+	data, err := os.ReadFile(opts.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis %s: %v", opts.Genesis, err)
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis %s: %v", opts.Genesis, err)
+	}
+	if genesis.Config == nil || genesis.Config.Clique == nil {
+		return nil, fmt.Errorf("genesis %s has no clique config", opts.Genesis)
+	}
+	if opts.Period > 0 {
+		genesis.Config.Clique.Period = opts.Period
+	}
+	if genesis.Alloc == nil {
+		genesis.Alloc = make(core.GenesisAlloc)
+	}
+
+	var signers []common.Address
+	for i, nodeConfig := range opts.Nodes {
+		account := crypto.PubkeyToAddress(keys[i].PublicKey)
+		genesis.Alloc[account] = core.GenesisAccount{Balance: NodeBalance}
+		if common.IsHexAddress(nodeConfig.Address) {
+			address := common.HexToAddress(nodeConfig.Address)
+			if _, funded := genesis.Alloc[address]; !funded {
+				genesis.Alloc[address] = core.GenesisAccount{Balance: AccountBalance}
+			}
+		}
+		if nodeConfig.Validator {
+			signers = append(signers, account)
+		}
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no validator among the selected nodes")
+	}
+	sort.Slice(signers, func(i, j int) bool { return bytes.Compare(signers[i][:], signers[j][:]) < 0 })
+
+	extra := make([]byte, 32, 32+len(signers)*common.AddressLength+crypto.SignatureLength)
+	for _, signer := range signers {
+		extra = append(extra, signer[:]...)
+	}
+	genesis.ExtraData = append(extra, make([]byte, crypto.SignatureLength)...)
+	return genesis, nil
+}
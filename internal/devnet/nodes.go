@@ -0,0 +1,198 @@
+//go:build inproc
+
+package devnet
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+
+	"benchy/internal/config"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// keyPassword protects the throwaway keystores of the nodes.
+const keyPassword = "benchy"
+
+// Node is one running go-ethereum node.
+type Node struct {
+	Config  config.Node
+	Stack   *node.Node
+	Backend *eth.Ethereum
+	// Account signs the node's blocks and eth_sendTransaction calls.
+	Account common.Address
+}
+
+// Devnet is a set of connected in-process nodes.
+type Devnet struct {
+	Nodes   []*Node
+	Genesis *core.Genesis
+}
+
+// Start creates the nodes on ephemeral localhost ports, connects them to
+// each other and starts sealing on the validators.
+func Start(opts Options) (*Devnet, error) {
+	if len(opts.Nodes) == 0 {
+		return nil, fmt.Errorf("no nodes to start")
+	}
+	// geth logs to stderr at info level by default; keep them in the run log
+	log.Root().SetHandler(log.FuncHandler(func(record *log.Record) error {
+		slog.Debug(record.Msg, record.Ctx...)
+		return nil
+	}))
+	keys := make([]*ecdsa.PrivateKey, len(opts.Nodes))
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	genesis, err := buildGenesis(opts, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	devnet := &Devnet{Genesis: genesis}
+	for i, nodeConfig := range opts.Nodes {
+		started, err := startNode(nodeConfig, keys[i], genesis)
+		if err != nil {
+			devnet.Close()
+			return nil, fmt.Errorf("failed to start %s: %v", nodeConfig.Name, err)
+		}
+		devnet.Nodes = append(devnet.Nodes, started)
+		slog.Debug("In-process node started", "node", nodeConfig.Name, "endpoint", started.Stack.HTTPEndpoint())
+	}
+
+	// A full mesh, as on the compose network
+	for i, a := range devnet.Nodes {
+		for _, b := range devnet.Nodes[i+1:] {
+			a.Stack.Server().AddPeer(b.Stack.Server().Self())
+		}
+	}
+
+	for _, n := range devnet.Nodes {
+		if !n.Config.Validator {
+			continue
+		}
+		if err := n.Backend.StartMining(); err != nil {
+			devnet.Close()
+			return nil, fmt.Errorf("failed to start sealing on %s: %v", n.Config.Name, err)
+		}
+	}
+	return devnet, nil
+}
+
+func startNode(nodeConfig config.Node, key *ecdsa.PrivateKey, genesis *core.Genesis) (*Node, error) {
+	stack, err := node.New(&node.Config{
+		Name:        "benchy-" + nodeConfig.Name,
+		HTTPHost:    "127.0.0.1",
+		HTTPModules: []string{"eth", "net", "web3", "txpool", "clique", "admin", "miner", "debug", "benchy"},
+		// The WebSocket server shares the HTTP port
+		WSHost:    "127.0.0.1",
+		WSModules: []string{"eth", "net", "web3", "txpool", "clique"},
+		P2P: p2p.Config{
+			ListenAddr:  "127.0.0.1:0",
+			NoDiscovery: true,
+			MaxPeers:    50,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The keystore directory is temporary and removed on Close
+	store := keystore.NewKeyStore(stack.KeyStoreDir(), keystore.LightScryptN, keystore.LightScryptP)
+	stack.AccountManager().AddBackend(store)
+	account, err := store.ImportECDSA(key, keyPassword)
+	if err != nil {
+		stack.Close()
+		return nil, err
+	}
+	if err := store.Unlock(account, keyPassword); err != nil {
+		stack.Close()
+		return nil, err
+	}
+
+	ethConfig := ethconfig.Defaults
+	ethConfig.Genesis = genesis
+	ethConfig.NetworkId = genesis.Config.ChainID.Uint64()
+	ethConfig.SyncMode = downloader.FullSync
+	ethConfig.Miner.Etherbase = account.Address
+	ethConfig.Miner.GasPrice = big.NewInt(params.GWei)
+	backend, err := eth.New(stack, &ethConfig)
+	if err != nil {
+		stack.Close()
+		return nil, err
+	}
+
+	filterSystem := filters.NewFilterSystem(backend.APIBackend, filters.Config{LogCacheSize: ethConfig.FilterLogCacheSize})
+	stack.RegisterAPIs([]rpc.API{
+		{Namespace: "eth", Service: filters.NewFilterAPI(filterSystem, false)},
+		{Namespace: "benchy", Service: newStatsAPI()},
+	})
+
+	if err := stack.Start(); err != nil {
+		stack.Close()
+		return nil, err
+	}
+	return &Node{Config: nodeConfig, Stack: stack, Backend: backend, Account: account.Address}, nil
+}
+
+// Config returns base with its nodes pointing at the devnet. Node addresses
+// become the node accounts so balances reflect sealing and scenarios, and
+// added Docker nodes are left out.
+func (d *Devnet) Config(base *config.Config) *config.Config {
+	cfg := *base
+	cfg.Backend = config.BackendInproc
+	cfg.Genesis = GenesisFile
+	cfg.NodesFile = ""
+	cfg.Nodes = make([]config.Node, len(d.Nodes))
+	for i, n := range d.Nodes {
+		nodeConfig := n.Config
+		nodeConfig.Endpoint = n.Stack.HTTPEndpoint()
+		nodeConfig.WSURL = n.Stack.WSEndpoint()
+		nodeConfig.Address = n.Account.Hex()
+		cfg.Nodes[i] = nodeConfig
+	}
+	return &cfg
+}
+
+// WriteGenesis saves the genesis of the devnet to path.
+func (d *Devnet) WriteGenesis(path string) error {
+	data, err := json.MarshalIndent(d.Genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// Close stops every node.
+func (d *Devnet) Close() error {
+	var firstErr error
+	for _, n := range d.Nodes {
+		if err := n.Stack.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,56 @@
+package devnet
+
+import (
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/process"
+)
+
+// StatsMethod returns the RuntimeStats of the process running the devnet.
+// It stands in for docker stats, which has no container to look at.
+const StatsMethod = "benchy_runtimeStats"
+
+// RuntimeStats are shared by every node of the devnet, since they run in one
+// process.
+type RuntimeStats struct {
+	CPUPercent       float64 `json:"cpuPercent"`
+	MemoryBytes      uint64  `json:"memoryBytes"`
+	MemoryLimitBytes uint64  `json:"memoryLimitBytes"`
+	HeapBytes        uint64  `json:"heapBytes"`
+	Goroutines       int     `json:"goroutines"`
+}
+
+type statsAPI struct {
+	mu      sync.Mutex
+	process *process.Process
+}
+
+func newStatsAPI() *statsAPI {
+	proc, _ := process.NewProcess(int32(os.Getpid()))
+	return &statsAPI{process: proc}
+}
+
+// RuntimeStats reports CPU usage since the previous call, Go memory
+// obtained from the OS and the goroutine count.
+func (api *statsAPI) RuntimeStats() RuntimeStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	stats := RuntimeStats{
+		MemoryBytes: memStats.Sys,
+		HeapBytes:   memStats.HeapAlloc,
+		Goroutines:  runtime.NumGoroutine(),
+	}
+	if host, err := mem.VirtualMemory(); err == nil {
+		stats.MemoryLimitBytes = host.Total
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if api.process != nil {
+		stats.CPUPercent, _ = api.process.Percent(0)
+	}
+	return stats
+}
@@ -0,0 +1,24 @@
+//go:build !inproc
+
+package devnet
+
+import "benchy/internal/config"
+
+// Devnet is empty without the inproc tag; Start always fails.
+type Devnet struct{}
+
+func Start(opts Options) (*Devnet, error) {
+	return nil, ErrNotBuilt
+}
+
+func (d *Devnet) Config(base *config.Config) *config.Config {
+	return base
+}
+
+func (d *Devnet) WriteGenesis(path string) error {
+	return ErrNotBuilt
+}
+
+func (d *Devnet) Close() error {
+	return nil
+}
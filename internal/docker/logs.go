@@ -0,0 +1,222 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of client errors looked for in container logs.
+const (
+	LogBadBlock     = "bad_block"
+	LogInvalidSeal  = "invalid_seal"
+	LogPeerDrop     = "peer_drop"
+	LogOOM          = "oom"
+	LogDBCorruption = "db_corruption"
+)
+
+// LogErrorKinds lists the kinds in display order.
+var LogErrorKinds = []string{LogBadBlock, LogInvalidSeal, LogPeerDrop, LogOOM, LogDBCorruption}
+
+type logPattern struct {
+	kind   string
+	regexp *regexp.Regexp
+}
+
+func pattern(kind, expr string) logPattern {
+	return logPattern{kind: kind, regexp: regexp.MustCompile(expr)}
+}
+
+// Every client can run out of memory.
+var commonLogPatterns = []logPattern{
+	pattern(LogOOM, `(?i)out of memory|cannot allocate memory|OOM[- ]?kill`),
+}
+
+// clientLogPatterns are matched by lowercase client name.
+var clientLogPatterns = map[string][]logPattern{
+	"geth": {
+		pattern(LogBadBlock, `(?i)bad block|invalid block`),
+		pattern(LogInvalidSeal, `(?i)unauthorized signer|invalid (clique )?seal|recently signed`),
+		pattern(LogPeerDrop, `(?i)removing p2p peer|peer dropped|dropping peer`),
+		pattern(LogDBCorruption, `(?i)missing trie node|database (corruption|corrupted)|corrupted|unclean shutdown.*(repair|rewind)`),
+	},
+	"nethermind": {
+		pattern(LogBadBlock, `(?i)invalid block|rejected block|bad block`),
+		pattern(LogInvalidSeal, `(?i)invalid (clique )?seal|unauthorized signer|signer.*not authorized`),
+		pattern(LogPeerDrop, `(?i)disconnect(ing|ed) .*peer|peer .*disconnect|dropping peer`),
+		pattern(LogDBCorruption, `(?i)rocksdb.*corrupt|corruption|missing trie node`),
+	},
+}
+
+func logPatternsFor(client string) []logPattern {
+	patterns := clientLogPatterns[strings.ToLower(client)]
+	return append(append([]logPattern{}, patterns...), commonLogPatterns...)
+}
+
+// LogSource is a node whose container logs are scanned.
+type LogSource struct {
+	Node   string
+	Client string
+}
+
+// LogMatch is one log line that matched an error pattern.
+type LogMatch struct {
+	Time time.Time
+	Node string
+	Kind string
+	Line string
+}
+
+const recentLogMatches = 20
+
+// LogScanner counts error pattern matches per node and kind.
+type LogScanner struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int
+	recent []LogMatch
+}
+
+func NewLogScanner() *LogScanner {
+	return &LogScanner{counts: make(map[string]map[string]int)}
+}
+
+// Scan matches one line of source's logs. A line counts once per kind.
+func (s *LogScanner) Scan(source LogSource, line string) {
+	for _, pattern := range logPatternsFor(source.Client) {
+		if !pattern.regexp.MatchString(line) {
+			continue
+		}
+		s.mu.Lock()
+		if s.counts[source.Node] == nil {
+			s.counts[source.Node] = make(map[string]int)
+		}
+		s.counts[source.Node][pattern.kind]++
+		s.recent = append(s.recent, LogMatch{Time: time.Now(), Node: source.Node, Kind: pattern.kind, Line: line})
+		if len(s.recent) > recentLogMatches {
+			s.recent = s.recent[len(s.recent)-recentLogMatches:]
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Counts returns a copy of the match counts by node and kind.
+func (s *LogScanner) Counts() map[string]map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]map[string]int, len(s.counts))
+	for node, kinds := range s.counts {
+		counts[node] = make(map[string]int, len(kinds))
+		for kind, count := range kinds {
+			counts[node][kind] = count
+		}
+	}
+	return counts
+}
+
+// Recent returns the last matched lines, oldest first.
+func (s *LogScanner) Recent() []LogMatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogMatch{}, s.recent...)
+}
+
+// LogCollector follows the container logs of nodes into files while
+// scanning them.
+type LogCollector struct {
+	scanner *LogScanner
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// CollectLogs writes the logs each node produces from now on to
+// dir/<node>.log until Stop is called.
+func (dm *DockerManager) CollectLogs(dir string, sources []LogSource) (*LogCollector, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	collector := &LogCollector{scanner: NewLogScanner(), cancel: cancel}
+	since := time.Now().UTC().Format(time.RFC3339)
+
+	for _, source := range sources {
+		file, err := os.Create(filepath.Join(dir, source.Node+".log"))
+		if err != nil {
+			collector.Stop()
+			return nil, fmt.Errorf("failed to create log file for %s: %v", source.Node, err)
+		}
+		args := []string{"logs", "--follow", "--no-color", "--no-log-prefix", "--since", since, source.Node}
+		collector.wg.Add(1)
+		go func(source LogSource) {
+			defer collector.wg.Done()
+			defer file.Close()
+			if err := dm.streamLogs(ctx, args, file, source, collector.scanner); err != nil && ctx.Err() == nil {
+				slog.Warn("Log collection stopped", "node", source.Node, "error", err)
+			}
+		}(source)
+	}
+	return collector, nil
+}
+
+func (c *LogCollector) Scanner() *LogScanner {
+	return c.scanner
+}
+
+// Stop ends collection and waits for the log files to be flushed.
+func (c *LogCollector) Stop() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+// ScanRecentLogs scans the last tail lines of each node's logs.
+func (dm *DockerManager) ScanRecentLogs(ctx context.Context, sources []LogSource, tail int) *LogScanner {
+	scanner := NewLogScanner()
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source LogSource) {
+			defer wg.Done()
+			args := []string{"logs", "--no-color", "--no-log-prefix", "--tail", fmt.Sprintf("%d", tail), source.Node}
+			if err := dm.streamLogs(ctx, args, io.Discard, source, scanner); err != nil {
+				slog.Debug("Log scan failed", "node", source.Node, "error", err)
+			}
+		}(source)
+	}
+	wg.Wait()
+	return scanner
+}
+
+// streamLogs runs a docker-compose logs command, copying its output to w and
+// scanning it line by line.
+func (dm *DockerManager) streamLogs(ctx context.Context, args []string, w io.Writer, source LogSource, scanner *LogScanner) error {
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := dm.runtime.Compose(ctx, dm.composeDir, args, nil, writer, w)
+		writer.Close()
+		done <- err
+	}()
+
+	lines := bufio.NewScanner(reader)
+	lines.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lines.Scan() {
+		line := lines.Text()
+		fmt.Fprintln(w, line)
+		scanner.Scan(source, line)
+	}
+	// Unblock the command if scanning stopped early
+	reader.CloseWithError(lines.Err())
+	if err := <-done; err != nil {
+		return fmt.Errorf("docker-compose %s failed: %v", strings.Join(args, " "), err)
+	}
+	return lines.Err()
+}
@@ -1,118 +1,157 @@
 package docker
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"benchy/internal/state"
 )
 
 type DockerManager struct {
 	composeDir string
+	runtime    Runtime
+	// startupWait gives the nodes time to initialize after a launch
+	startupWait time.Duration
+	// out receives the output of docker-compose up, the console if nil
+	out io.Writer
+}
+
+// NewDockerManager drives the compose project in composeDir, relative to the
+// working directory unless absolute.
+func NewDockerManager(composeDir string) (*DockerManager, error) {
+	absDir, err := filepath.Abs(composeDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose directory %s: %v", composeDir, err)
+	}
+	if _, err := os.Stat(filepath.Join(absDir, "docker-compose.yml")); err != nil {
+		return nil, fmt.Errorf("no docker-compose.yml in %s: %v", absDir, err)
+	}
+	return &DockerManager{composeDir: absDir, runtime: composeCLI{}, startupWait: 15 * time.Second}, nil
+}
+
+// NewDockerManagerWithRuntime drives composeDir through runtime instead of
+// the docker-compose CLI. The directory need not hold a compose file, and
+// launches do not wait for nodes to initialize.
+func NewDockerManagerWithRuntime(composeDir string, runtime Runtime) *DockerManager {
+	return &DockerManager{composeDir: composeDir, runtime: runtime}
 }
 
-func NewDockerManager() (*DockerManager, error) {
-	pwd, _ := os.Getwd()
-	composeDir := filepath.Join(pwd, "docker")
-	return &DockerManager{composeDir: composeDir}, nil
+// WithOutput returns a manager of the same project that writes the output
+// of docker-compose up to out instead of stdout.
+func (dm *DockerManager) WithOutput(out io.Writer) *DockerManager {
+	copied := *dm
+	copied.out = out
+	return &copied
 }
 
 func (dm *DockerManager) CleanNetwork() error {
-	fmt.Println("🧹 Cleaning up existing containers and persistent state...")
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	
-	if err := os.Chdir(dm.composeDir); err != nil {
-		return fmt.Errorf("failed to change to docker directory: %v", err)
-	}
+	slog.Info("🧹 Cleaning up existing containers and persistent state")
 
-	// Nettoyer Docker
-	cmd := exec.Command("docker-compose", "down", "-v")
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("Warning: cleanup failed (this is normal if first run): %v\n", err)
+	if err := dm.compose("down", "-v"); err != nil {
+		slog.Warn("Cleanup failed (this is normal on the first run)", "error", err)
 	}
 
-	// Supprimer le fichier d'état
-	stateFile := filepath.Join(originalDir, "benchy_state.json")
-	if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
-		fmt.Printf("Warning: failed to remove state file: %v\n", err)
-	} else if err == nil {
-		fmt.Println("🗑️  Removed persistent state file")
+	store := state.Default()
+	if err := store.Reset(); err != nil {
+		slog.Warn("Failed to remove state file", "error", err)
+	} else {
+		slog.Info("🗑️  Removed persistent state file", "path", store.Path())
 	}
 
 	return nil
 }
 
 func (dm *DockerManager) LaunchNetwork() error {
-	fmt.Println("🚀 Launching REAL Ethereum network with Docker...")
+	return dm.LaunchNetworkFrom(nil)
+}
+
+// LaunchNetworkFrom launches the network, calling restore once the
+// containers exist but before they start, so their data can be replaced.
+func (dm *DockerManager) LaunchNetworkFrom(restore func() error) error {
+	slog.Info("🚀 Launching REAL Ethereum network with Docker")
 	
-	// Nettoyer complètement avant de lancer
 	if err := dm.CleanNetwork(); err != nil {
 		return err
 	}
 	
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	
-	if err := os.Chdir(dm.composeDir); err != nil {
-		return fmt.Errorf("failed to change to docker directory: %v", err)
+	if restore != nil {
+		if err := dm.compose("up", "--no-start"); err != nil {
+			return fmt.Errorf("failed to create network: %v", err)
+		}
+		if err := restore(); err != nil {
+			return err
+		}
 	}
 
-	fmt.Println("🔄 Starting network containers...")
-	cmd := exec.Command("docker-compose", "up", "-d")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	
-	if err := cmd.Run(); err != nil {
+	slog.Info("🔄 Starting network containers")
+	stdout, stderr := dm.output()
+	err := dm.runtime.Compose(context.Background(), dm.composeDir, []string{"up", "-d"}, nil, stdout, stderr)
+	if err != nil {
 		return fmt.Errorf("failed to start network: %v", err)
 	}
 
-	fmt.Println("⏳ Waiting for nodes to initialize...")
-	time.Sleep(15 * time.Second)
+	if dm.startupWait > 0 {
+		slog.Info("⏳ Waiting for nodes to initialize")
+		time.Sleep(dm.startupWait)
+	}
 
-	fmt.Println("✅ Network launched successfully!")
-	fmt.Println("📍 Nodes accessible at:")
-	fmt.Println("  - Alice (Geth):      http://localhost:8545")
-	fmt.Println("  - Bob (Nethermind):  http://localhost:8547")
-	fmt.Println("  - Cassandra (Geth):  http://localhost:8549")
-	fmt.Println("  - Driss (Nethermind): http://localhost:8551")
-	fmt.Println("  - Elena (Geth):      http://localhost:8553")
+	slog.Info("✅ Network launched successfully!")
 
 	return nil
 }
 
 func (dm *DockerManager) StopContainer(containerName string, duration int) error {
-	fmt.Printf("⚠️  Stopping %s for %d seconds...\n", containerName, duration)
-	
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
+	slog.Warn("Stopping container", "container", containerName, "seconds", duration)
 	
-	if err := os.Chdir(dm.composeDir); err != nil {
-		return fmt.Errorf("failed to change to docker directory: %v", err)
-	}
-
 	// Stop container
-	cmd := exec.Command("docker-compose", "stop", containerName)
-	if err := cmd.Run(); err != nil {
+	if err := dm.compose("stop", containerName); err != nil {
 		return fmt.Errorf("failed to stop container: %v", err)
 	}
 
-	// Countdown visuel avec monitoring en parallèle
-	fmt.Printf("📊 Monitor with 'benchy infos' in another terminal to see %s as 🔴 OFF\n", containerName)
-	for i := duration; i > 0; i-- {
-		fmt.Printf("\r⏳ Restarting in %d seconds...", i)
-		time.Sleep(1 * time.Second)
-	}
-	fmt.Print("\n")
+	slog.Info("📊 Monitor with 'benchy infos' in another terminal to see it as 🔴 OFF", "container", containerName)
+	slog.Info("⏳ Waiting before restart", "seconds", duration)
+	time.Sleep(time.Duration(duration) * time.Second)
 
 	// Restart container
-	fmt.Printf("🔄 Restarting %s...\n", containerName)
-	cmd = exec.Command("docker-compose", "start", containerName)
-	if err := cmd.Run(); err != nil {
+	slog.Info("🔄 Restarting container", "container", containerName)
+	if err := dm.compose("start", containerName); err != nil {
 		return fmt.Errorf("failed to restart container: %v", err)
 	}
 
-	fmt.Printf("✅ %s is back online! Run 'benchy infos' to confirm.\n", containerName)
+	slog.Info("✅ Container is back online! Run 'benchy infos' to confirm.", "container", containerName)
+	return nil
+}
+// StopNode stops a node's container and leaves it down.
+func (dm *DockerManager) StopNode(nodeName string) error {
+	return dm.compose("stop", nodeName)
+}
+
+// StartNode starts a previously stopped node's container.
+func (dm *DockerManager) StartNode(nodeName string) error {
+	return dm.compose("start", nodeName)
+}
+
+// output returns where docker-compose up writes.
+func (dm *DockerManager) output() (stdout, stderr io.Writer) {
+	if dm.out != nil {
+		return dm.out, dm.out
+	}
+	return os.Stdout, os.Stderr
+}
+
+// compose runs a docker-compose command from the compose directory without
+// changing the process working directory, so it is safe from goroutines.
+func (dm *DockerManager) compose(args ...string) error {
+	var output bytes.Buffer
+	if err := dm.runtime.Compose(context.Background(), dm.composeDir, args, nil, &output, &output); err != nil {
+		return fmt.Errorf("docker-compose %s failed: %v (%s)", strings.Join(args, " "), err, strings.TrimSpace(output.String()))
+	}
 	return nil
-}
\ No newline at end of file
+}
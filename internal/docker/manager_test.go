@@ -0,0 +1,224 @@
+package docker_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"benchy/internal/docker"
+	"benchy/internal/fake"
+)
+
+// newTestManager drives a fake runtime from a temporary compose directory.
+// The persistent state is written to a temporary working directory.
+func newTestManager(t *testing.T, services ...string) (*docker.DockerManager, *fake.Runtime, string) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	dir := t.TempDir()
+	runtime := fake.NewRuntime(services...)
+	return docker.NewDockerManagerWithRuntime(dir, runtime), runtime, dir
+}
+
+func TestLaunchNetworkFrom(t *testing.T) {
+	dm, runtime, _ := newTestManager(t, "alice", "bob")
+
+	var restored []string
+	err := dm.LaunchNetworkFrom(func() error {
+		restored = []string{runtime.State("alice"), runtime.State("bob")}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The containers exist but do not run while their data is restored
+	if want := []string{fake.StateCreated, fake.StateCreated}; !reflect.DeepEqual(restored, want) {
+		t.Errorf("got states %v during restore, want %v", restored, want)
+	}
+	for _, name := range []string{"alice", "bob"} {
+		if state := runtime.State(name); state != fake.StateRunning {
+			t.Errorf("%s is %s after the launch", name, state)
+		}
+	}
+	want := [][]string{{"down", "-v"}, {"up", "--no-start"}, {"up", "-d"}}
+	if got := runtime.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got commands %v, want %v", got, want)
+	}
+}
+
+func TestLaunchNetworkFromFailedRestore(t *testing.T) {
+	dm, runtime, _ := newTestManager(t, "alice")
+
+	failure := errors.New("restore failed")
+	if err := dm.LaunchNetworkFrom(func() error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("got %v, want the restore error", err)
+	}
+	if state := runtime.State("alice"); state != fake.StateCreated {
+		t.Errorf("alice is %s, want it left created", state)
+	}
+}
+
+func TestNodeLifecycle(t *testing.T) {
+	dm, runtime, _ := newTestManager(t, "alice", "bob")
+
+	steps := []struct {
+		name  string
+		apply func(string) error
+		want  string
+	}{
+		{"stop", dm.StopNode, fake.StateStopped},
+		{"start", dm.StartNode, fake.StateRunning},
+		{"pause", dm.PauseNode, fake.StatePaused},
+		{"unpause", dm.UnpauseNode, fake.StateRunning},
+		{"kill", dm.KillNode, fake.StateStopped},
+		{"restart", dm.RestartNode, fake.StateRunning},
+	}
+	for _, step := range steps {
+		if err := step.apply("bob"); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if state := runtime.State("bob"); state != step.want {
+			t.Errorf("%s: bob is %s, want %s", step.name, state, step.want)
+		}
+	}
+	if state := runtime.State("alice"); state != fake.StateRunning {
+		t.Errorf("alice is %s, want it untouched", state)
+	}
+
+	if err := dm.StopAll(); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.State("alice") != fake.StateStopped || runtime.State("bob") != fake.StateStopped {
+		t.Error("StopAll left a node running")
+	}
+	if err := dm.StartNode("frank"); err == nil || !strings.Contains(err.Error(), "docker-compose start frank failed") {
+		t.Errorf("got %v starting an unknown node", err)
+	}
+}
+
+func TestFakeNodeFollowsContainer(t *testing.T) {
+	dm, runtime, _ := newTestManager(t)
+	chain, err := fake.NewChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	node, err := chain.NewNode("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+	runtime.Bind("alice", node)
+
+	if err := dm.StopNode("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if node.Online() {
+		t.Error("the node answers while its container is stopped")
+	}
+	if err := dm.StartNode("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if !node.Online() {
+		t.Error("the node is offline once its container started")
+	}
+}
+
+func TestAddRemoveNode(t *testing.T) {
+	dm, runtime, dir := newTestManager(t, "alice")
+	spec := docker.NodeSpec{Name: "frank", Image: "ethereum/client-go:stable", HTTPPort: 8561, P2PPort: 30311}
+
+	if err := dm.AddNode(spec); err != nil {
+		t.Fatal(err)
+	}
+	if state := runtime.State("frank"); state != fake.StateRunning {
+		t.Errorf("frank is %s after being added", state)
+	}
+	override, err := os.ReadFile(filepath.Join(dir, "docker-compose.override.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"frank:", "container_name: benchy-frank", "8561:8545", "30311:30303"} {
+		if !strings.Contains(string(override), want) {
+			t.Errorf("override file lacks %q:\n%s", want, override)
+		}
+	}
+	if err := dm.AddNode(spec); err == nil {
+		t.Error("the same node was added twice")
+	}
+
+	if err := dm.RemoveNode("frank"); err != nil {
+		t.Fatal(err)
+	}
+	if state := runtime.State("frank"); state != fake.StateRemoved {
+		t.Errorf("frank is %s after being removed", state)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "docker-compose.override.yml")); !os.IsNotExist(err) {
+		t.Errorf("the override file outlived its last node: %v", err)
+	}
+	if err := dm.RemoveNode("alice"); err == nil {
+		t.Error("a node of the compose file was removed")
+	}
+}
+
+func TestDataDirSize(t *testing.T) {
+	dm, _, _ := newTestManager(t, "alice")
+
+	size, err := dm.DataDirSize("alice", "/root/.ethereum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != fake.DataDirKiB*1024 {
+		t.Errorf("got %d bytes, want %d", size, fake.DataDirKiB*1024)
+	}
+	if err := dm.StopNode("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.DataDirSize("alice", "/root/.ethereum"); err == nil {
+		t.Error("a stopped container reported its datadir size")
+	}
+}
+
+func TestCollectLogs(t *testing.T) {
+	dm, runtime, _ := newTestManager(t, "alice", "bob")
+	runtime.AddLogs("alice", "Imported new chain segment", "Removing p2p peer before the run")
+	logDir := filepath.Join(t.TempDir(), "logs")
+
+	sources := []docker.LogSource{{Node: "alice", Client: "Geth"}, {Node: "bob", Client: "Nethermind"}}
+	collector, err := dm.CollectLogs(logDir, sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.AddLogs("alice", "Removing p2p peer id=1234")
+	runtime.AddLogs("bob", "Invalid block 42 rejected", "Processed block 43")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		counts := collector.Scanner().Counts()
+		if counts["alice"][docker.LogPeerDrop] > 0 && counts["bob"][docker.LogBadBlock] > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got counts %v, want alice's peer drop and bob's bad block", counts)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	collector.Stop()
+
+	bob, err := os.ReadFile(filepath.Join(logDir, "bob.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bob), "Processed block 43") {
+		t.Errorf("bob.log lacks the collected lines:\n%s", bob)
+	}
+
+	// Scanning the recent logs also sees the lines from before the run
+	scanner := dm.ScanRecentLogs(t.Context(), sources[:1], 10)
+	if count := scanner.Counts()["alice"][docker.LogPeerDrop]; count != 2 {
+		t.Errorf("got %d peer drops in alice's recent logs, want 2", count)
+	}
+}
@@ -0,0 +1,218 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overrideFile holds nodes added with `benchy node add`. docker-compose
+// merges it automatically, so every compose command sees them.
+const overrideFile = "docker-compose.override.yml"
+
+// NodeSpec describes a node added to the running network.
+type NodeSpec struct {
+	Name     string
+	Image    string
+	HTTPPort int
+	P2PPort  int
+	Mine     bool
+}
+
+type composeService struct {
+	Image         string   `yaml:"image"`
+	ContainerName string   `yaml:"container_name"`
+	Ports         []string `yaml:"ports"`
+	Command       string   `yaml:"command"`
+	Networks      []string `yaml:"networks"`
+}
+
+type composeOverride struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+// RestartNode restarts a node's container.
+func (dm *DockerManager) RestartNode(nodeName string) error {
+	return dm.compose("restart", nodeName)
+}
+
+// PauseNode freezes a node's processes without stopping the container.
+func (dm *DockerManager) PauseNode(nodeName string) error {
+	return dm.compose("pause", nodeName)
+}
+
+func (dm *DockerManager) UnpauseNode(nodeName string) error {
+	return dm.compose("unpause", nodeName)
+}
+
+// KillNode sends SIGKILL, simulating a crash rather than a clean shutdown.
+func (dm *DockerManager) KillNode(nodeName string) error {
+	return dm.compose("kill", nodeName)
+}
+
+// StopAll stops every container of the network, keeping their data.
+func (dm *DockerManager) StopAll() error {
+	return dm.compose("stop")
+}
+
+func (dm *DockerManager) StartAll() error {
+	return dm.compose("start")
+}
+
+// ComposeFile returns the path of a file in the compose directory.
+func (dm *DockerManager) ComposeFile(name string) string {
+	return filepath.Join(dm.composeDir, name)
+}
+
+// OverrideFile is the compose file holding added nodes.
+func (dm *DockerManager) OverrideFile() string {
+	return dm.ComposeFile(overrideFile)
+}
+
+// DataDirSize returns the disk usage in bytes of path inside a node's
+// container.
+func (dm *DockerManager) DataDirSize(nodeName, path string) (int64, error) {
+	var output bytes.Buffer
+	args := []string{"exec", "-T", nodeName, "du", "-sk", path}
+	if err := dm.runtime.Compose(context.Background(), dm.composeDir, args, nil, &output, nil); err != nil {
+		return 0, fmt.Errorf("du %s on %s failed: %v", path, nodeName, err)
+	}
+	fields := strings.Fields(output.String())
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected du output %q", output)
+	}
+	kib, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected du output %q", output)
+	}
+	return kib * 1024, nil
+}
+
+// NodeLogs streams a node's logs to stdout.
+func (dm *DockerManager) NodeLogs(nodeName string, tail int, follow bool) error {
+	args := []string{"logs", "--no-color", "--tail", fmt.Sprintf("%d", tail)}
+	if follow {
+		args = append(args, "--follow")
+	}
+	args = append(args, nodeName)
+	return dm.composeAttached(args...)
+}
+
+// ExecNode runs a command inside a node's container attached to the terminal.
+func (dm *DockerManager) ExecNode(nodeName string, command []string) error {
+	return dm.composeAttached(append([]string{"exec", nodeName}, command...)...)
+}
+
+func (dm *DockerManager) composeAttached(args ...string) error {
+	if err := dm.runtime.Compose(context.Background(), dm.composeDir, args, os.Stdin, os.Stdout, os.Stderr); err != nil {
+		return fmt.Errorf("docker-compose %s failed: %v", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// AddNode declares a geth service in the override file and starts it on the
+// benchy network.
+func (dm *DockerManager) AddNode(spec NodeSpec) error {
+	override, err := dm.readOverride()
+	if err != nil {
+		return err
+	}
+	if _, exists := override.Services[spec.Name]; exists {
+		return fmt.Errorf("node %s already exists", spec.Name)
+	}
+
+	command := []string{
+		"--dev",
+		"--datadir /root/.ethereum",
+		"--http",
+		"--http.addr 0.0.0.0",
+		"--http.port 8545",
+		`--http.corsdomain "*"`,
+		`--http.api "eth,net,web3,personal,miner,debug,admin"`,
+		"--verbosity 3",
+	}
+	if spec.Mine {
+		command = append(command, "--mine")
+	}
+
+	override.Services[spec.Name] = composeService{
+		Image:         spec.Image,
+		ContainerName: "benchy-" + spec.Name,
+		Ports:         []string{fmt.Sprintf("%d:8545", spec.HTTPPort), fmt.Sprintf("%d:30303", spec.P2PPort)},
+		Command:       strings.Join(command, " "),
+		Networks:      []string{"benchy-network"},
+	}
+	if err := dm.writeOverride(override); err != nil {
+		return err
+	}
+
+	if err := dm.compose("up", "-d", spec.Name); err != nil {
+		// Leave no declared but unstartable service behind
+		delete(override.Services, spec.Name)
+		dm.writeOverride(override)
+		return err
+	}
+	return nil
+}
+
+// RemoveNode stops and deletes a node added with AddNode.
+func (dm *DockerManager) RemoveNode(nodeName string) error {
+	override, err := dm.readOverride()
+	if err != nil {
+		return err
+	}
+	if _, exists := override.Services[nodeName]; !exists {
+		return fmt.Errorf("node %s was not added with `benchy node add`", nodeName)
+	}
+
+	if err := dm.compose("rm", "--stop", "--force", "-v", nodeName); err != nil {
+		return err
+	}
+	delete(override.Services, nodeName)
+	return dm.writeOverride(override)
+}
+
+func (dm *DockerManager) readOverride() (*composeOverride, error) {
+	override := &composeOverride{Services: make(map[string]composeService)}
+
+	data, err := os.ReadFile(filepath.Join(dm.composeDir, overrideFile))
+	if os.IsNotExist(err) {
+		return override, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", overrideFile, err)
+	}
+	if err := yaml.Unmarshal(data, override); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", overrideFile, err)
+	}
+	if override.Services == nil {
+		override.Services = make(map[string]composeService)
+	}
+	return override, nil
+}
+
+func (dm *DockerManager) writeOverride(override *composeOverride) error {
+	path := filepath.Join(dm.composeDir, overrideFile)
+	if len(override.Services) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", overrideFile, err)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(override)
+	if err != nil {
+		return err
+	}
+	header := "# Nodes added with `benchy node add`. Managed by benchy, do not edit.\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", overrideFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"os/exec"
+)
+
+// Runtime runs docker-compose commands for a DockerManager. The default
+// runs the real CLI; internal/fake provides one without Docker.
+type Runtime interface {
+	// Compose runs `docker-compose args...` from dir. Nil readers and
+	// writers are left unconnected.
+	Compose(ctx context.Context, dir string, args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+type composeCLI struct{}
+
+func (composeCLI) Compose(ctx context.Context, dir string, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, "docker-compose", args...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
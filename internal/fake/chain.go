@@ -0,0 +1,311 @@
+// Package fake provides in-process stand-ins for the benchy network: JSON-RPC
+// nodes over a simulated chain and a container runtime without Docker. They
+// let the monitor, scenarios and Docker manager run on any machine.
+package fake
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ChainID is the chain id of go-ethereum's simulated backend.
+const ChainID = 1337
+
+const gasLimit = 30_000_000
+
+// SuggestedTip is the priority fee the nodes suggest, as geth does on an
+// idle chain.
+var SuggestedTip = big.NewInt(1e9)
+
+var (
+	// CoinbaseBalance funds the chain's coinbase, which signs
+	// eth_sendTransaction.
+	CoinbaseBalance = new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e18))
+	// AccountBalance funds every address given to NewChain.
+	AccountBalance = new(big.Int).Mul(big.NewInt(1_000), big.NewInt(1e18))
+)
+
+// Chain is a simulated blockchain shared by fake nodes, so they agree on
+// blocks and balances like a synced network. By default every accepted
+// transaction is mined at once, as geth --dev does.
+type Chain struct {
+	mu       sync.Mutex
+	sendMu   sync.Mutex // orders coinbase nonces
+	backend  *backends.SimulatedBackend
+	key      *ecdsa.PrivateKey
+	coinbase common.Address
+	signer   types.Signer
+	autoMine bool
+	noClique bool
+	pruned   bool
+	pending  int
+	// txFeed announces the hash of every accepted transaction
+	txFeed event.Feed
+}
+
+// NewChain funds a generated coinbase and the given accounts.
+func NewChain(accounts ...common.Address) (*Chain, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	coinbase := crypto.PubkeyToAddress(key.PublicKey)
+
+	alloc := core.GenesisAlloc{coinbase: {Balance: CoinbaseBalance}}
+	for _, account := range accounts {
+		if account != coinbase {
+			alloc[account] = core.GenesisAccount{Balance: AccountBalance}
+		}
+	}
+	return &Chain{
+		backend:  backends.NewSimulatedBackend(alloc, gasLimit),
+		key:      key,
+		coinbase: coinbase,
+		signer:   types.LatestSignerForChainID(big.NewInt(ChainID)),
+		autoMine: true,
+	}, nil
+}
+
+func (c *Chain) Coinbase() common.Address {
+	return c.coinbase
+}
+
+// SetAutoMine switches between mining on every transaction and leaving them
+// pending until Mine is called.
+func (c *Chain) SetAutoMine(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.autoMine = enabled
+}
+
+// SetClique switches the clique namespace of the nodes on or off. Without
+// it they answer like geth --dev, which lacks the namespace.
+func (c *Chain) SetClique(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.noClique = !enabled
+}
+
+// SetPruned drops the state of past blocks, as a node pruning its history
+// does: balances are then only served at the head.
+func (c *Chain) SetPruned(pruned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruned = pruned
+}
+
+func (c *Chain) isPruned() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pruned
+}
+
+func (c *Chain) clique() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.noClique
+}
+
+// Mine seals count blocks, the first including any pending transactions.
+func (c *Chain) Mine(count int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < count; i++ {
+		c.backend.Commit()
+	}
+	c.pending = 0
+}
+
+// Pending returns the number of transactions waiting for the next block.
+func (c *Chain) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending
+}
+
+// BlockNumber returns the head block number.
+func (c *Chain) BlockNumber() uint64 {
+	return c.backend.Blockchain().CurrentBlock().Number.Uint64()
+}
+
+// Send adds a signed transaction, mining it unless auto-mining is off.
+func (c *Chain) Send(tx *types.Transaction) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The simulated backend panics on transactions it cannot apply
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("transaction rejected: %v", r)
+		}
+	}()
+	if err := c.validate(tx); err != nil {
+		return err
+	}
+	if err := c.backend.SendTransaction(context.Background(), tx); err != nil {
+		return err
+	}
+	c.txFeed.Send(tx.Hash())
+	if c.autoMine {
+		c.backend.Commit()
+		return nil
+	}
+	c.pending++
+	return nil
+}
+
+// validate rejects unaffordable transactions with geth's error. It checks
+// the latest balance; overspending pending transactions are caught by the
+// recover in Send.
+func (c *Chain) validate(tx *types.Transaction) error {
+	sender, err := types.Sender(c.signer, tx)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %v", err)
+	}
+	balance, err := c.backend.BalanceAt(context.Background(), sender, nil)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(tx.Cost()) < 0 {
+		return fmt.Errorf("insufficient funds for gas * price + value: address %s have %s want %s", sender.Hex(), balance, tx.Cost())
+	}
+	return nil
+}
+
+// SendFromCoinbase signs and sends a legacy transaction from the coinbase.
+func (c *Chain) SendFromCoinbase(to *common.Address, value *big.Int, gas uint64, data []byte) (common.Hash, error) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	ctx := context.Background()
+	nonce, err := c.backend.PendingNonceAt(ctx, c.coinbase)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gasPrice, err := c.GasPrice()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	if gas == 0 {
+		gas = 21000
+		if len(data) > 0 || to == nil {
+			gas = 3_000_000
+		}
+	}
+	tx, err := types.SignNewTx(c.key, c.signer, &types.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Value:    value,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     data,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), c.Send(tx)
+}
+
+// GasPrice suggests the pending base fee plus SuggestedTip, like geth.
+func (c *Chain) GasPrice() (*big.Int, error) {
+	baseFee, err := c.backend.SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Add(baseFee, SuggestedTip), nil
+}
+
+// SubscribeTransactions announces the hashes of accepted transactions on ch.
+func (c *Chain) SubscribeTransactions(ch chan<- common.Hash) event.Subscription {
+	return c.txFeed.Subscribe(ch)
+}
+
+// Transaction returns a transaction with the block that holds it, a nil
+// block while it is pending, and a nil transaction if it is unknown.
+func (c *Chain) Transaction(hash common.Hash) (*types.Transaction, *types.Block, int, error) {
+	ctx := context.Background()
+	tx, pending, err := c.backend.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil, 0, nil
+	}
+	if err != nil || pending {
+		return tx, nil, 0, err
+	}
+	receipt, err := c.backend.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	block, err := c.backend.BlockByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return tx, block, int(receipt.TransactionIndex), nil
+}
+
+// Block returns the block at number, or by hash when number is nil; nil if
+// there is none.
+func (c *Chain) Block(number *rpc.BlockNumber, hash *common.Hash) (*types.Block, error) {
+	ctx := context.Background()
+	if hash != nil {
+		block, err := c.backend.BlockByHash(ctx, *hash)
+		if err != nil {
+			return nil, nil
+		}
+		return block, nil
+	}
+	var requested *big.Int
+	if *number >= 0 {
+		if uint64(*number) > c.BlockNumber() {
+			return nil, nil
+		}
+		requested = big.NewInt(number.Int64())
+	}
+	block, err := c.backend.BlockByNumber(ctx, requested)
+	if err != nil {
+		return nil, errNoBlock
+	}
+	return block, nil
+}
+
+// BlockReceipts returns the receipts of the transactions of block.
+func (c *Chain) BlockReceipts(block *types.Block) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		receipt, err := c.backend.TransactionReceipt(context.Background(), tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+// Receipt returns the receipt of a mined transaction, nil if unknown.
+func (c *Chain) Receipt(hash common.Hash) (*types.Receipt, error) {
+	receipt, err := c.backend.TransactionReceipt(context.Background(), hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	return receipt, err
+}
+
+// Close stops the simulated blockchain.
+func (c *Chain) Close() error {
+	return c.backend.Close()
+}
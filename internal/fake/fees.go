@@ -0,0 +1,116 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxFeeHistory is the most blocks eth_feeHistory returns, as in geth.
+const maxFeeHistory = 1024
+
+// feeHistory is the result of eth_feeHistory.
+type feeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory reports the base fee, gas used ratio and tip percentiles of
+// count blocks up to newest, like geth: the base fees run one block past
+// newest, and the percentiles weigh each tip by the gas its transaction
+// used.
+func (c *Chain) FeeHistory(ctx context.Context, count uint64, newest rpc.BlockNumber, percentiles []float64) (*feeHistory, error) {
+	for i, percentile := range percentiles {
+		if percentile < 0 || percentile > 100 || (i > 0 && percentile < percentiles[i-1]) {
+			return nil, fmt.Errorf("invalid reward percentiles %v", percentiles)
+		}
+	}
+	head := c.BlockNumber()
+	last := head
+	if newest >= 0 {
+		if uint64(newest) > head {
+			return nil, fmt.Errorf("request beyond head block: requested %d, head %d", newest, head)
+		}
+		last = uint64(newest)
+	}
+	count = min(count, maxFeeHistory, last+1)
+
+	history := &feeHistory{OldestBlock: (*hexutil.Big)(new(big.Int).SetUint64(last + 1 - count))}
+	if count == 0 {
+		return history, nil
+	}
+	config := c.backend.Blockchain().Config()
+	for number := last + 1 - count; number <= last; number++ {
+		block, err := c.backend.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, err
+		}
+		header := block.Header()
+		history.BaseFee = append(history.BaseFee, (*hexutil.Big)(baseFee(header)))
+		history.GasUsedRatio = append(history.GasUsedRatio, float64(header.GasUsed)/float64(header.GasLimit))
+		if len(percentiles) > 0 {
+			rewards, err := c.rewards(ctx, block, percentiles)
+			if err != nil {
+				return nil, err
+			}
+			history.Reward = append(history.Reward, rewards)
+		}
+		if number == last {
+			history.BaseFee = append(history.BaseFee, (*hexutil.Big)(eip1559.CalcBaseFee(config, header)))
+		}
+	}
+	return history, nil
+}
+
+func baseFee(header *types.Header) *big.Int {
+	if header.BaseFee == nil {
+		return new(big.Int)
+	}
+	return header.BaseFee
+}
+
+// rewards returns the tips paid at percentiles of the gas used in block.
+func (c *Chain) rewards(ctx context.Context, block *types.Block, percentiles []float64) ([]*hexutil.Big, error) {
+	rewards := make([]*hexutil.Big, len(percentiles))
+	if len(block.Transactions()) == 0 {
+		for i := range rewards {
+			rewards[i] = (*hexutil.Big)(new(big.Int))
+		}
+		return rewards, nil
+	}
+
+	type paid struct {
+		tip *big.Int
+		gas uint64
+	}
+	var tips []paid
+	for _, tx := range block.Transactions() {
+		receipt, err := c.backend.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		tip, _ := tx.EffectiveGasTip(baseFee(block.Header()))
+		tips = append(tips, paid{tip: tip, gas: receipt.GasUsed})
+	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].tip.Cmp(tips[j].tip) < 0 })
+
+	var index int
+	sum := tips[0].gas
+	for i, percentile := range percentiles {
+		threshold := uint64(float64(block.GasUsed()) * percentile / 100)
+		for sum < threshold && index < len(tips)-1 {
+			index++
+			sum += tips[index].gas
+		}
+		rewards[i] = (*hexutil.Big)(tips[index].tip)
+	}
+	return rewards, nil
+}
@@ -0,0 +1,63 @@
+package fake
+
+import (
+	"benchy/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Network is a fake node per configured node, over one chain, with a
+// runtime whose services control them.
+type Network struct {
+	// Config is the source config with endpoints pointing at the fake nodes.
+	Config  *config.Config
+	Chain   *Chain
+	Nodes   map[string]*Node
+	Runtime *Runtime
+}
+
+// NewNetwork fakes the nodes of cfg, funding their addresses. Both the HTTP
+// and WebSocket endpoints point at the fake nodes.
+func NewNetwork(cfg *config.Config) (*Network, error) {
+	var accounts []common.Address
+	for _, node := range cfg.Nodes {
+		if common.IsHexAddress(node.Address) {
+			accounts = append(accounts, common.HexToAddress(node.Address))
+		}
+	}
+	chain, err := NewChain(accounts...)
+	if err != nil {
+		return nil, err
+	}
+
+	faked := *cfg
+	faked.Nodes = make([]config.Node, len(cfg.Nodes))
+	network := &Network{
+		Config:  &faked,
+		Chain:   chain,
+		Nodes:   make(map[string]*Node),
+		Runtime: NewRuntime(cfg.NodeNames()...),
+	}
+	for i, node := range cfg.Nodes {
+		fakeNode, err := chain.NewNode(node.Name)
+		if err != nil {
+			network.Close()
+			return nil, err
+		}
+		fakeNode.SetPeerCount(uint64(len(cfg.Nodes) - 1))
+		network.Nodes[node.Name] = fakeNode
+		network.Runtime.Bind(node.Name, fakeNode)
+
+		node.Endpoint = fakeNode.URL
+		node.WSURL = fakeNode.WSURL
+		faked.Nodes[i] = node
+	}
+	return network, nil
+}
+
+func (n *Network) Close() {
+	for _, node := range n.Nodes {
+		node.Close()
+	}
+	n.Chain.Close()
+}
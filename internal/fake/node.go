@@ -0,0 +1,468 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ClientVersion is reported by web3_clientVersion.
+const ClientVersion = "Geth/v1.13.5-fake/linux-amd64/go1.24"
+
+var errNoBlock = errors.New("header not found")
+
+// Node serves the JSON-RPC methods benchy uses over httptest servers, one
+// for HTTP and one for WebSocket subscriptions. It answers with HTTP 503
+// while offline and drops its WebSocket connections, like a stopped
+// container.
+type Node struct {
+	Name string
+	// URL is the JSON-RPC endpoint of the node.
+	URL string
+	// WSURL is the WebSocket endpoint, for eth_subscribe.
+	WSURL string
+
+	chain    *Chain
+	server   *httptest.Server
+	wsServer *httptest.Server
+	rpc      *rpc.Server
+
+	mu        sync.RWMutex
+	online    bool
+	peerCount uint64
+	wsConns   map[net.Conn]struct{}
+}
+
+// NewNode starts serving chain under name.
+func (c *Chain) NewNode(name string) (*Node, error) {
+	node := &Node{Name: name, chain: c, rpc: rpc.NewServer(), online: true, wsConns: make(map[net.Conn]struct{})}
+	services := map[string]interface{}{
+		"clique": &cliqueService{chain: c},
+		"eth":    &ethService{chain: c},
+		"net":    &netService{node: node},
+		"txpool": &txpoolService{chain: c},
+		"web3":   web3Service{},
+	}
+	for namespace, service := range services {
+		if err := node.rpc.RegisterName(namespace, service); err != nil {
+			node.rpc.Stop()
+			return nil, err
+		}
+	}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	node.URL = node.server.URL
+
+	ws := node.rpc.WebsocketHandler([]string{"*"})
+	node.wsServer = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !node.Online() {
+			http.Error(w, "node is offline", http.StatusServiceUnavailable)
+			return
+		}
+		ws.ServeHTTP(w, r)
+	}))
+	// Upgraded connections leave the server's tracking, so keep them to
+	// drop them when the node goes offline
+	node.wsServer.Config.ConnState = node.trackWS
+	node.wsServer.Start()
+	node.WSURL = "ws" + strings.TrimPrefix(node.wsServer.URL, "http")
+	return node, nil
+}
+
+func (n *Node) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !n.Online() {
+		http.Error(w, "node is offline", http.StatusServiceUnavailable)
+		return
+	}
+	n.rpc.ServeHTTP(w, r)
+}
+
+func (n *Node) trackWS(conn net.Conn, state http.ConnState) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		n.wsConns[conn] = struct{}{}
+	case http.StateClosed:
+		delete(n.wsConns, conn)
+	}
+}
+
+// SetOnline makes the node answer or refuse requests. Going offline ends
+// the WebSocket subscriptions.
+func (n *Node) SetOnline(online bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.online = online
+	if !online {
+		for conn := range n.wsConns {
+			conn.Close()
+			delete(n.wsConns, conn)
+		}
+	}
+}
+
+func (n *Node) Online() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.online
+}
+
+// SetPeerCount sets the value of net_peerCount.
+func (n *Node) SetPeerCount(count uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.peerCount = count
+}
+
+func (n *Node) Close() {
+	n.SetOnline(false)
+	n.server.Close()
+	n.wsServer.Close()
+	n.rpc.Stop()
+}
+
+type ethService struct {
+	chain *Chain
+}
+
+func (s *ethService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(ChainID))
+}
+
+func (s *ethService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.chain.BlockNumber())
+}
+
+func (s *ethService) Accounts() []common.Address {
+	return []common.Address{s.chain.coinbase}
+}
+
+func (s *ethService) Coinbase() common.Address {
+	return s.chain.coinbase
+}
+
+func (s *ethService) GasPrice() (*hexutil.Big, error) {
+	price, err := s.chain.GasPrice()
+	return (*hexutil.Big)(price), err
+}
+
+func (s *ethService) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(SuggestedTip)
+}
+
+func (s *ethService) FeeHistory(ctx context.Context, blockCount hexutil.Uint64, newest rpc.BlockNumber, percentiles []float64) (*feeHistory, error) {
+	return s.chain.FeeHistory(ctx, uint64(blockCount), newest, percentiles)
+}
+
+// Balances and nonces are only served at the head or pending block.
+func (s *ethService) GetBalance(ctx context.Context, address common.Address, block rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	if s.chain.isPruned() && historical(block, s.chain.BlockNumber()) {
+		return nil, errors.New("missing trie node " + common.Hash{}.Hex() + " (path ) state is not available")
+	}
+	balance, err := s.chain.backend.BalanceAt(ctx, address, nil)
+	return (*hexutil.Big)(balance), err
+}
+
+func (s *ethService) GetTransactionCount(ctx context.Context, address common.Address, block rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if number, ok := block.Number(); ok && number == rpc.PendingBlockNumber {
+		nonce, err := s.chain.backend.PendingNonceAt(ctx, address)
+		return hexutil.Uint64(nonce), err
+	}
+	nonce, err := s.chain.backend.NonceAt(ctx, address, nil)
+	return hexutil.Uint64(nonce), err
+}
+
+// historical reports whether block names a block before head.
+func historical(block rpc.BlockNumberOrHash, head uint64) bool {
+	if _, ok := block.Hash(); ok {
+		return true
+	}
+	number, _ := block.Number()
+	return number >= 0 && uint64(number) < head
+}
+
+// Code and calls are only served at the head block.
+func (s *ethService) GetCode(ctx context.Context, address common.Address, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	return s.chain.backend.CodeAt(ctx, address, nil)
+}
+
+type callArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Gas   *hexutil.Uint64 `json:"gas"`
+	Value *hexutil.Big    `json:"value"`
+	Data  *hexutil.Bytes  `json:"data"`
+	Input *hexutil.Bytes  `json:"input"`
+}
+
+func (s *ethService) Call(ctx context.Context, args callArgs, block *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	msg := ethereum.CallMsg{From: args.From, To: args.To, Value: (*big.Int)(args.Value)}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	return s.chain.backend.CallContract(ctx, msg, nil)
+}
+
+func (s *ethService) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), s.chain.Send(tx)
+}
+
+type sendTxArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Gas   *hexutil.Uint64 `json:"gas"`
+	Data  *hexutil.Bytes  `json:"data"`
+	Input *hexutil.Bytes  `json:"input"`
+}
+
+// SendTransaction signs with the coinbase, the only unlocked account.
+func (s *ethService) SendTransaction(args sendTxArgs) (common.Hash, error) {
+	if args.From != s.chain.coinbase {
+		return common.Hash{}, errors.New("unknown account")
+	}
+	var gas uint64
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	}
+	var data []byte
+	if args.Input != nil {
+		data = *args.Input
+	} else if args.Data != nil {
+		data = *args.Data
+	}
+	return s.chain.SendFromCoinbase(args.To, (*big.Int)(args.Value), gas, data)
+}
+
+func (s *ethService) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	return s.chain.Receipt(hash)
+}
+
+// GetTransactionByHash returns null for unknown transactions and no block
+// fields for pending ones.
+func (s *ethService) GetTransactionByHash(hash common.Hash) (map[string]interface{}, error) {
+	tx, block, index, err := s.chain.Transaction(hash)
+	if tx == nil || err != nil {
+		return nil, err
+	}
+	return marshalTransaction(tx, block, index, s.chain.signer)
+}
+
+func (s *ethService) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	block, err := s.chain.Block(&number, nil)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return marshalBlock(block, fullTx, s.chain.signer)
+}
+
+func (s *ethService) GetBlockByHash(hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	block, err := s.chain.Block(nil, &hash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return marshalBlock(block, fullTx, s.chain.signer)
+}
+
+// GetBlockReceipts returns null for unknown blocks.
+func (s *ethService) GetBlockReceipts(blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	number, _ := blockNrOrHash.Number()
+	hash, byHash := blockNrOrHash.Hash()
+	var block *types.Block
+	var err error
+	if byHash {
+		block, err = s.chain.Block(nil, &hash)
+	} else {
+		block, err = s.chain.Block(&number, nil)
+	}
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return s.chain.BlockReceipts(block)
+}
+
+// NewHeads is the newHeads subscription of eth_subscribe.
+func (s *ethService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	heads := make(chan *types.Header, 16)
+	headSub, err := s.chain.backend.SubscribeNewHead(context.Background(), heads)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		defer headSub.Unsubscribe()
+		for {
+			select {
+			case header := <-heads:
+				notifier.Notify(sub.ID, header)
+			case <-headSub.Err():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// NewPendingTransactions is the newPendingTransactions subscription of
+// eth_subscribe. It sends hashes only.
+func (s *ethService) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	hashes := make(chan common.Hash, 256)
+	txSub := s.chain.SubscribeTransactions(hashes)
+	go func() {
+		defer txSub.Unsubscribe()
+		for {
+			select {
+			case hash := <-hashes:
+				notifier.Notify(sub.ID, hash)
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// marshalBlock renders a block the way geth's eth_getBlockByNumber does.
+func marshalBlock(block *types.Block, fullTx bool, signer types.Signer) (map[string]interface{}, error) {
+	data, err := json.Marshal(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	transactions := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if !fullTx {
+			transactions[i] = tx.Hash()
+			continue
+		}
+		if transactions[i], err = marshalTransaction(tx, block, i, signer); err != nil {
+			return nil, err
+		}
+	}
+	fields["transactions"] = transactions
+	fields["uncles"] = []common.Hash{}
+	fields["size"] = hexutil.Uint64(block.Size())
+	return fields, nil
+}
+
+// marshalTransaction renders tx at index in block, or as pending when block
+// is nil.
+func marshalTransaction(tx *types.Transaction, block *types.Block, index int, signer types.Signer) (map[string]interface{}, error) {
+	data, err := tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	fields["from"] = from
+	fields["blockHash"] = nil
+	fields["blockNumber"] = nil
+	fields["transactionIndex"] = nil
+	if block != nil {
+		fields["blockHash"] = block.Hash()
+		fields["blockNumber"] = (*hexutil.Big)(block.Number())
+		fields["transactionIndex"] = hexutil.Uint64(index)
+	}
+	return fields, nil
+}
+
+// cliqueService reports the coinbase as the only signer.
+type cliqueService struct {
+	chain *Chain
+}
+
+func (s *cliqueService) GetSigners(block *rpc.BlockNumberOrHash) ([]common.Address, error) {
+	if !s.chain.clique() {
+		return nil, methodNotFound("clique_getSigners")
+	}
+	return []common.Address{s.chain.coinbase}, nil
+}
+
+// methodNotFound is the error geth answers for a namespace it does not
+// serve.
+type methodNotFound string
+
+func (e methodNotFound) Error() string {
+	return "the method " + string(e) + " does not exist/is not available"
+}
+
+func (e methodNotFound) ErrorCode() int {
+	return -32601
+}
+
+type netService struct {
+	node *Node
+}
+
+func (s *netService) PeerCount() hexutil.Uint {
+	s.node.mu.RLock()
+	defer s.node.mu.RUnlock()
+	return hexutil.Uint(s.node.peerCount)
+}
+
+func (s *netService) Version() string {
+	return big.NewInt(ChainID).String()
+}
+
+func (s *netService) Listening() bool {
+	return true
+}
+
+type txpoolService struct {
+	chain *Chain
+}
+
+func (s *txpoolService) Status() map[string]hexutil.Uint {
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(s.chain.Pending()),
+		"queued":  0,
+	}
+}
+
+type web3Service struct{}
+
+func (web3Service) ClientVersion() string {
+	return ClientVersion
+}
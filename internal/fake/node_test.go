@@ -0,0 +1,223 @@
+package fake
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestNode serves a chain funding a generated key, with a client on it.
+func newTestNode(t *testing.T) (*Chain, *Node, *ethclient.Client, *keyedSigner) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &keyedSigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+	chain, err := NewChain(signer.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	node, err := chain.NewNode("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(node.Close)
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return chain, node, client, signer
+}
+
+type keyedSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	nonce   uint64
+}
+
+// transfer signs a 1 wei EIP-1559 transfer paying tip.
+func (s *keyedSigner) transfer(t *testing.T, tip int64) *types.Transaction {
+	t.Helper()
+	tx, err := types.SignNewTx(s.key, types.LatestSignerForChainID(big.NewInt(ChainID)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(ChainID),
+		Nonce:     s.nonce,
+		To:        &s.address,
+		Value:     big.NewInt(1),
+		Gas:       21000,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(100e9),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.nonce++
+	return tx
+}
+
+func TestTransactionLifecycle(t *testing.T) {
+	chain, _, client, signer := newTestNode(t)
+	ctx := context.Background()
+	chain.SetAutoMine(false)
+
+	tx := signer.transfer(t, 2e9)
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, pending, err := client.TransactionByHash(ctx, tx.Hash()); err != nil || !pending {
+		t.Fatalf("before mining: pending %v, error %v", pending, err)
+	}
+
+	chain.Mine(1)
+	found, pending, err := client.TransactionByHash(ctx, tx.Hash())
+	if err != nil || pending || found.Hash() != tx.Hash() {
+		t.Fatalf("after mining: pending %v, error %v", pending, err)
+	}
+
+	block, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipts, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != 1 || receipts[0].TxHash != tx.Hash() || receipts[0].GasUsed != 21000 {
+		t.Fatalf("got receipts %+v, want the transfer", receipts)
+	}
+	if _, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.NumberU64()+10))); err == nil {
+		t.Error("receipts of a future block were found")
+	}
+	if _, _, err := client.TransactionByHash(ctx, common.Hash{1}); err == nil {
+		t.Error("an unknown transaction was found")
+	}
+}
+
+func TestFeeMarket(t *testing.T) {
+	chain, _, client, signer := newTestNode(t)
+	ctx := context.Background()
+
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil || tip.Cmp(SuggestedTip) != 0 {
+		t.Fatalf("got tip %v (%v), want %v", tip, err, SuggestedTip)
+	}
+
+	chain.SetAutoMine(false)
+	for _, tip := range []int64{1e9, 3e9} {
+		if err := client.SendTransaction(ctx, signer.transfer(t, tip)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chain.Mine(1)
+	chain.Mine(1)
+
+	head := chain.BlockNumber()
+	history, err := client.FeeHistory(ctx, 2, nil, []float64{0, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.OldestBlock.Uint64() != head-1 || len(history.BaseFee) != 3 || len(history.GasUsedRatio) != 2 {
+		t.Fatalf("got oldest %v, %d base fees, %d ratios", history.OldestBlock, len(history.BaseFee), len(history.GasUsedRatio))
+	}
+	// The first block holds both transfers, the second none
+	rewards := history.Reward
+	if rewards[0][0].Int64() != 1e9 || rewards[0][1].Int64() != 3e9 || rewards[1][1].Sign() != 0 {
+		t.Errorf("got rewards %v, want [1 gwei 3 gwei] then zeros", rewards)
+	}
+	if history.GasUsedRatio[0] == 0 || history.GasUsedRatio[1] != 0 {
+		t.Errorf("got gas used ratios %v", history.GasUsedRatio)
+	}
+	if _, err := client.FeeHistory(ctx, 1, big.NewInt(int64(head)+1), nil); err == nil {
+		t.Error("fee history beyond the head was served")
+	}
+}
+
+func TestCliqueSigners(t *testing.T) {
+	chain, node, _, _ := newTestNode(t)
+	client, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var signers []common.Address
+	if err := client.Call(&signers, "clique_getSigners", "latest"); err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 || signers[0] != chain.Coinbase() {
+		t.Errorf("got signers %v, want the coinbase %s", signers, chain.Coinbase().Hex())
+	}
+
+	chain.SetClique(false)
+	err = client.Call(&signers, "clique_getSigners", "latest")
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32601 {
+		t.Errorf("got %v without the clique namespace, want method not found", err)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	chain, node, _, signer := newTestNode(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, node.WSURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	heads := make(chan *types.Header, 4)
+	headSub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := make(chan common.Hash, 4)
+	txSub, err := client.Client().EthSubscribe(ctx, hashes, "newPendingTransactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer txSub.Unsubscribe()
+
+	tx := signer.transfer(t, 1e9)
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case hash := <-hashes:
+		if hash != tx.Hash() {
+			t.Errorf("got pending %s, want %s", hash.Hex(), tx.Hash().Hex())
+		}
+	case <-ctx.Done():
+		t.Fatal("no pending transaction notification")
+	}
+	select {
+	case header := <-heads:
+		if header.Number.Uint64() != chain.BlockNumber() {
+			t.Errorf("got head #%d, want #%d", header.Number, chain.BlockNumber())
+		}
+	case <-ctx.Done():
+		t.Fatal("no new head notification")
+	}
+
+	// A stopped node drops its subscribers
+	node.SetOnline(false)
+	select {
+	case <-headSub.Err():
+	case <-ctx.Done():
+		t.Fatal("the subscription survived the node going offline")
+	}
+	if _, err := ethclient.DialContext(ctx, node.WSURL); err == nil {
+		t.Error("an offline node accepted a WebSocket connection")
+	}
+}
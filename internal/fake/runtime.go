@@ -0,0 +1,307 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"benchy/internal/docker"
+	"benchy/internal/monitor"
+)
+
+// Container states, as reported by State.
+const (
+	StateCreated = "created"
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateStopped = "exited"
+	StateRemoved = "removed"
+)
+
+// DataDirKiB is the datadir size reported by `du -sk` inside containers.
+const DataDirKiB = 1024
+
+// Runtime stands in for docker-compose and docker stats. It tracks the
+// state of each service, records the commands it receives and takes the
+// bound fake nodes offline when their container stops.
+type Runtime struct {
+	mu       sync.Mutex
+	services map[string]*service
+	order    []string
+	commands [][]string
+}
+
+type service struct {
+	state string
+	node  *Node
+	logs  []logLine
+	stats monitor.ContainerStats
+}
+
+type logLine struct {
+	time time.Time
+	text string
+}
+
+var (
+	_ docker.Runtime           = (*Runtime)(nil)
+	_ monitor.ContainerRuntime = (*Runtime)(nil)
+)
+
+// NewRuntime declares services, all running.
+func NewRuntime(services ...string) *Runtime {
+	r := &Runtime{services: make(map[string]*service)}
+	for _, name := range services {
+		r.add(name, StateRunning)
+	}
+	return r
+}
+
+func (r *Runtime) add(name, state string) *service {
+	svc := &service{state: state, stats: monitor.ContainerStats{
+		CPUUsage:         1.5,
+		MemoryUsage:      "128MiB / 2GiB",
+		MemoryLimit:      "2GiB",
+		MemoryUsageBytes: 128 << 20,
+		MemoryLimitBytes: 2 << 30,
+		PIDs:             12,
+	}}
+	r.services[name] = svc
+	r.order = append(r.order, name)
+	return svc
+}
+
+// Bind ties node to a service, so the node is online only while the
+// container runs.
+func (r *Runtime) Bind(name string, node *Node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	svc, exists := r.services[name]
+	if !exists {
+		svc = r.add(name, StateRunning)
+	}
+	svc.node = node
+	node.SetOnline(svc.state == StateRunning)
+}
+
+// State returns the state of a service, StateRemoved if unknown.
+func (r *Runtime) State(name string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if svc, exists := r.services[name]; exists {
+		return svc.state
+	}
+	return StateRemoved
+}
+
+// AddLogs appends lines to a service's logs, including for followers.
+func (r *Runtime) AddLogs(name string, lines ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if svc, exists := r.services[name]; exists {
+		for _, line := range lines {
+			svc.logs = append(svc.logs, logLine{time: time.Now(), text: line})
+		}
+	}
+}
+
+// SetStats replaces the stats reported while a service runs.
+func (r *Runtime) SetStats(name string, stats monitor.ContainerStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if svc, exists := r.services[name]; exists {
+		svc.stats = stats
+	}
+}
+
+// Commands returns the docker-compose arguments received so far.
+func (r *Runtime) Commands() [][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]string{}, r.commands...)
+}
+
+// ContainerStats reports benchy-<service> containers.
+func (r *Runtime) ContainerStats(containerName string) (*monitor.ContainerStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	svc, exists := r.services[strings.TrimPrefix(containerName, "benchy-")]
+	if !exists || svc.state != StateRunning {
+		return &monitor.ContainerStats{IsRunning: false}, nil
+	}
+	stats := svc.stats
+	stats.IsRunning = true
+	return &stats, nil
+}
+
+// Compose applies the docker-compose subcommands benchy uses.
+func (r *Runtime) Compose(ctx context.Context, dir string, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	r.mu.Lock()
+	r.commands = append(r.commands, append([]string{}, args...))
+	r.mu.Unlock()
+
+	if len(args) == 0 {
+		return fmt.Errorf("no command")
+	}
+	command, flags, names := parseCompose(args[1:], args[0] == "exec")
+
+	switch args[0] {
+	case "up":
+		state := StateRunning
+		if _, created := flags["--no-start"]; created {
+			state = StateCreated
+		}
+		return r.transition(names, state, true)
+	case "down":
+		return r.transition(nil, StateRemoved, false)
+	case "start", "restart", "unpause":
+		return r.transition(names, StateRunning, false)
+	case "stop", "kill":
+		return r.transition(names, StateStopped, false)
+	case "pause":
+		return r.transition(names, StatePaused, false)
+	case "rm":
+		return r.transition(names, StateRemoved, false)
+	case "logs":
+		return r.logs(ctx, names, flags, stdout)
+	case "exec":
+		return r.exec(names, command, stdout)
+	}
+	return nil
+}
+
+// parseCompose splits the arguments after the subcommand into flags, service
+// names and, for exec, the command run in the container.
+func parseCompose(args []string, exec bool) (command []string, flags map[string]string, names []string) {
+	flags = make(map[string]string)
+	valued := map[string]bool{"--tail": true, "--since": true, "-n": true}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") && len(names) == 0 {
+			if valued[arg] && i+1 < len(args) {
+				flags[arg] = args[i+1]
+				i++
+			} else {
+				flags[arg] = ""
+			}
+			continue
+		}
+		if exec && len(names) == 1 {
+			command = args[i:]
+			break
+		}
+		names = append(names, arg)
+	}
+	return command, flags, names
+}
+
+// transition moves the named services, or all of them, to state. Unknown
+// services are created by up and rejected otherwise.
+func (r *Runtime) transition(names []string, state string, create bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(names) == 0 {
+		names = r.order
+	}
+	for _, name := range names {
+		svc, exists := r.services[name]
+		if !exists {
+			if !create {
+				return fmt.Errorf("no such service: %s", name)
+			}
+			svc = r.add(name, state)
+		}
+		if state == StateRunning && svc.state == StateRemoved && !create {
+			return fmt.Errorf("no container found for %s", name)
+		}
+		svc.state = state
+		if svc.node != nil {
+			svc.node.SetOnline(state == StateRunning)
+		}
+	}
+	return nil
+}
+
+func (r *Runtime) logs(ctx context.Context, names []string, flags map[string]string, stdout io.Writer) error {
+	if len(names) != 1 {
+		return fmt.Errorf("logs needs one service")
+	}
+	lines, err := r.logLines(names[0], 0)
+	if err != nil {
+		return err
+	}
+	offset := len(lines)
+	if value, ok := flags["--since"]; ok {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %v", value, err)
+		}
+		for len(lines) > 0 && lines[0].time.Before(since) {
+			lines = lines[1:]
+		}
+	}
+	if tail, ok := flags["--tail"]; ok {
+		if n, err := strconv.Atoi(tail); err == nil && n < len(lines) {
+			lines = lines[len(lines)-n:]
+		}
+	}
+	writeLines(stdout, lines)
+
+	_, follow := flags["--follow"]
+	if _, short := flags["-f"]; !follow && !short {
+		return nil
+	}
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		added, err := r.logLines(names[0], offset)
+		if err != nil {
+			return err
+		}
+		offset += len(added)
+		writeLines(stdout, added)
+	}
+}
+
+func (r *Runtime) logLines(name string, offset int) ([]logLine, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	svc, exists := r.services[name]
+	if !exists {
+		return nil, fmt.Errorf("no such service: %s", name)
+	}
+	return append([]logLine{}, svc.logs[offset:]...), nil
+}
+
+func writeLines(w io.Writer, lines []logLine) {
+	if w == nil {
+		return
+	}
+	for _, line := range lines {
+		fmt.Fprintln(w, line.text)
+	}
+}
+
+// exec answers `du -sk <path>` and accepts any other command silently.
+func (r *Runtime) exec(names, command []string, stdout io.Writer) error {
+	if len(names) != 1 {
+		return fmt.Errorf("exec needs a service")
+	}
+	if state := r.State(names[0]); state != StateRunning {
+		return fmt.Errorf("service %s is not running (%s)", names[0], state)
+	}
+	if len(command) == 3 && command[0] == "du" && command[1] == "-sk" && stdout != nil {
+		fmt.Fprintf(stdout, "%d\t%s\n", DataDirKiB, command[2])
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RunLogName is the log file written in each run directory.
+const RunLogName = "benchy.log"
+
+// Options selects how logs are written.
+type Options struct {
+	// Level is the minimum console level: debug, info, warn or error
+	Level string
+	// Format is the console format: pretty, text or json
+	Format string
+	// RunDir receives a JSON log of the run at debug level, if set
+	RunDir string
+}
+
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", value)
+}
+
+// Setup installs the default slog logger. The returned function closes the
+// run log file.
+func Setup(opts Options) (func() error, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var console slog.Handler
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(opts.Format) {
+	case "", "pretty":
+		console = NewConsoleHandler(level)
+	case "text":
+		console = slog.NewTextHandler(stdout{}, handlerOpts)
+	case "json":
+		console = slog.NewJSONHandler(stdout{}, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected pretty, text or json)", opts.Format)
+	}
+
+	closeLog := func() error { return nil }
+	handler := console
+	if opts.RunDir != "" {
+		file, err := os.OpenFile(filepath.Join(opts.RunDir, RunLogName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open run log: %v", err)
+		}
+		handler = fanout{console, slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})}
+		closeLog = file.Close
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return closeLog, nil
+}
+
+// NewRunDir creates base/<timestamp>-<command> for the logs of one run.
+func NewRunDir(base, command string) (string, error) {
+	name := time.Now().Format("20060102-150405") + "-" + strings.ReplaceAll(command, " ", "-")
+	dir := filepath.Join(base, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create run directory: %v", err)
+	}
+	return dir, nil
+}
+
+// stdout writes to the current os.Stdout, which commands redirect while a
+// machine readable format or a job capture owns it.
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// fanout sends each record to every handler that accepts its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range f {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, handler := range f {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, handler := range f {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, handler := range f {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
+
+// ConsoleHandler prints the friendly console output: the message, prefixed
+// with an emoji for warnings and errors, followed by key=value attributes.
+type ConsoleHandler struct {
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string
+	out    io.Writer
+}
+
+func NewConsoleHandler(level slog.Leveler) *ConsoleHandler {
+	return &ConsoleHandler{level: level, out: stdout{}}
+}
+
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *ConsoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString("❌ ")
+	case record.Level >= slog.LevelWarn:
+		line.WriteString("⚠️  ")
+	case record.Level < slog.LevelInfo:
+		line.WriteString("🔎 ")
+	}
+	line.WriteString(record.Message)
+
+	for _, attr := range h.attrs {
+		fmt.Fprintf(&line, " %s=%v", attr.Key, attr.Value.Resolve())
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		if !attr.Equal(slog.Attr{}) {
+			fmt.Fprintf(&line, " %s%s=%v", h.prefix, attr.Key, attr.Value.Resolve())
+		}
+		return true
+	})
+	line.WriteByte('\n')
+
+	_, err := io.WriteString(h.out, line.String())
+	return err
+}
+
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.prefix + attr.Key, Value: attr.Value})
+	}
+	return &clone
+}
+
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
@@ -0,0 +1,312 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"benchy/internal/clique"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	alertEvalInterval   = 5 * time.Second
+	alertRecentSize     = 20
+	defaultCliquePeriod = 5 * time.Second // when the genesis cannot be read
+)
+
+// Rule names, also used as the "rule" field of logged alerts.
+const (
+	AlertNodeOffline   = "node_offline"
+	AlertHeadLag       = "head_lag"
+	AlertChainStalled  = "chain_stalled"
+	AlertTxPoolPending = "txpool_pending"
+	AlertMemory        = "memory"
+)
+
+type AlertState string
+
+const (
+	AlertFiring   AlertState = "firing"
+	AlertResolved AlertState = "resolved"
+)
+
+// AlertRules configures the thresholds. A zero value disables the rule.
+type AlertRules struct {
+	OfflineAfter       time.Duration `yaml:"offline_after"`
+	MaxHeadLag         uint64        `yaml:"max_head_lag"`
+	StallPeriods       uint64        `yaml:"stall_periods"` // multiples of the Clique period
+	TxPoolPendingLimit uint64        `yaml:"txpool_pending_limit"`
+	MemoryLimit        string        `yaml:"memory_limit"` // e.g. "2GiB"
+}
+
+func DefaultAlertRules() AlertRules {
+	return AlertRules{
+		OfflineAfter:       30 * time.Second,
+		MaxHeadLag:         5,
+		StallPeriods:       3,
+		TxPoolPendingLimit: 5000,
+	}
+}
+
+// LoadAlertRules reads rules from a YAML file over the defaults. An empty
+// path returns the defaults.
+func LoadAlertRules(path string) (AlertRules, error) {
+	rules := DefaultAlertRules()
+	if path == "" {
+		return rules, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("failed to read alert rules %s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("failed to parse alert rules %s: %v", path, err)
+	}
+	if rules.MemoryLimit != "" && parseByteSize(rules.MemoryLimit) == 0 {
+		return rules, fmt.Errorf("invalid memory_limit %q", rules.MemoryLimit)
+	}
+	return rules, nil
+}
+
+// Alert is a rule changing state, as written to the JSONL log and webhook.
+type Alert struct {
+	Time    time.Time  `json:"time"`
+	Rule    string     `json:"rule"`
+	Node    string     `json:"node,omitempty"`
+	State   AlertState `json:"state"`
+	Message string     `json:"message"`
+}
+
+func (a Alert) key() string {
+	return a.Rule + "/" + a.Node
+}
+
+// AlertEngine evaluates the rules against live node state and sends state
+// changes to the terminal, an optional JSONL file and an optional webhook.
+type AlertEngine struct {
+	rules       AlertRules
+	memoryLimit uint64
+	period      time.Duration
+	logPath     string
+	webhookURL  string
+	httpClient  *http.Client
+
+	mu           sync.Mutex
+	offlineSince map[string]time.Time
+	active       map[string]Alert
+	recent       []Alert
+	sinkErr      error
+}
+
+func NewAlertEngine(rules AlertRules, logPath, webhookURL string) *AlertEngine {
+	period := defaultCliquePeriod
+	if seconds, err := clique.LoadPeriod(genesisFile); err == nil && seconds > 0 {
+		period = time.Duration(seconds) * time.Second
+	}
+
+	return &AlertEngine{
+		rules:        rules,
+		memoryLimit:  parseByteSize(rules.MemoryLimit),
+		period:       period,
+		logPath:      logPath,
+		webhookURL:   webhookURL,
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+		offlineSince: make(map[string]time.Time),
+		active:       make(map[string]Alert),
+	}
+}
+
+// Run evaluates the live snapshot periodically until ctx is cancelled.
+// Alerts are also pushed to the live event stream.
+func (ae *AlertEngine) Run(ctx context.Context, live *LiveMonitor) {
+	go func() {
+		ticker := time.NewTicker(alertEvalInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			for _, alert := range ae.Evaluate(live.Snapshot(), time.Now()) {
+				live.emit(alert.Node, LiveEvent{
+					Kind:    EventAlert,
+					Message: fmt.Sprintf("%s: %s", alert.State, alert.Message),
+				})
+			}
+		}
+	}()
+}
+
+// Evaluate checks every rule and returns the alerts that fired or resolved.
+func (ae *AlertEngine) Evaluate(states map[string]LiveNodeState, now time.Time) []Alert {
+	ae.mu.Lock()
+	defer ae.mu.Unlock()
+
+	firing := make(map[string]Alert)
+	fire := func(rule, node, message string) {
+		alert := Alert{Time: now, Rule: rule, Node: node, State: AlertFiring, Message: message}
+		firing[alert.key()] = alert
+	}
+
+	var highest uint64
+	var lastHead time.Time
+	for _, state := range states {
+		if state.Online && state.BlockNumber > highest {
+			highest = state.BlockNumber
+		}
+		if state.LastHeadAt.After(lastHead) {
+			lastHead = state.LastHeadAt
+		}
+	}
+
+	for name, state := range states {
+		if !state.Online {
+			if _, ok := ae.offlineSince[name]; !ok {
+				ae.offlineSince[name] = now
+			}
+			down := now.Sub(ae.offlineSince[name])
+			if ae.rules.OfflineAfter > 0 && down >= ae.rules.OfflineAfter {
+				fire(AlertNodeOffline, name, fmt.Sprintf("%s offline for %s", name, down.Round(time.Second)))
+			}
+			continue
+		}
+		delete(ae.offlineSince, name)
+
+		if lag := highest - state.BlockNumber; ae.rules.MaxHeadLag > 0 && lag > ae.rules.MaxHeadLag {
+			fire(AlertHeadLag, name, fmt.Sprintf("%s is %d blocks behind (#%d vs #%d)", name, lag, state.BlockNumber, highest))
+		}
+		if ae.rules.TxPoolPendingLimit > 0 && state.TxPoolPending > ae.rules.TxPoolPendingLimit {
+			fire(AlertTxPoolPending, name, fmt.Sprintf("%s has %d pending transactions (limit %d)",
+				name, state.TxPoolPending, ae.rules.TxPoolPendingLimit))
+		}
+		if ae.memoryLimit > 0 && state.MemoryBytes > ae.memoryLimit {
+			fire(AlertMemory, name, fmt.Sprintf("%s uses %s of memory (limit %s)",
+				name, formatByteSize(state.MemoryBytes), ae.rules.MemoryLimit))
+		}
+	}
+
+	if stallAfter := time.Duration(ae.rules.StallPeriods) * ae.period; stallAfter > 0 && !lastHead.IsZero() {
+		if idle := now.Sub(lastHead); idle > stallAfter {
+			fire(AlertChainStalled, "", fmt.Sprintf("no new block for %s (%d × %s period)",
+				idle.Round(time.Second), ae.rules.StallPeriods, ae.period))
+		}
+	}
+
+	var changes []Alert
+	for key, alert := range firing {
+		if _, ok := ae.active[key]; !ok {
+			changes = append(changes, alert)
+		}
+		ae.active[key] = alert
+	}
+	for key, alert := range ae.active {
+		if _, ok := firing[key]; ok {
+			continue
+		}
+		delete(ae.active, key)
+		alert.Time = now
+		alert.State = AlertResolved
+		changes = append(changes, alert)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key() < changes[j].key() })
+
+	for _, alert := range changes {
+		ae.dispatch(alert)
+	}
+	return changes
+}
+
+// dispatch records an alert and sends it to the configured sinks. Called
+// with ae.mu held.
+func (ae *AlertEngine) dispatch(alert Alert) {
+	ae.recent = append(ae.recent, alert)
+	if len(ae.recent) > alertRecentSize {
+		ae.recent = ae.recent[len(ae.recent)-alertRecentSize:]
+	}
+
+	line, err := json.Marshal(alert)
+	if err != nil {
+		ae.sinkErr = err
+		return
+	}
+	if ae.logPath != "" {
+		if err := appendLine(ae.logPath, line); err != nil {
+			ae.sinkErr = err
+		}
+	}
+	if ae.webhookURL != "" {
+		go ae.post(line)
+	}
+}
+
+func (ae *AlertEngine) post(body []byte) {
+	response, err := ae.httpClient.Post(ae.webhookURL, "application/json", bytes.NewReader(body))
+	if err == nil {
+		response.Body.Close()
+		if response.StatusCode >= 300 {
+			err = fmt.Errorf("webhook returned %s", response.Status)
+		}
+	}
+	if err != nil {
+		ae.mu.Lock()
+		ae.sinkErr = err
+		ae.mu.Unlock()
+	}
+}
+
+func appendLine(path string, line []byte) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert log: %v", err)
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// DisplayAlerts prints firing alerts and the latest state changes.
+func (ae *AlertEngine) DisplayAlerts() {
+	ae.mu.Lock()
+	defer ae.mu.Unlock()
+
+	active := make([]Alert, 0, len(ae.active))
+	for _, alert := range ae.active {
+		active = append(active, alert)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].key() < active[j].key() })
+
+	if len(active) == 0 {
+		fmt.Println("\n✅ No active alerts")
+	} else {
+		// Ring the terminal bell so a stalled chain is noticed off screen
+		fmt.Printf("\a\n🚨 Active alerts (%d):\n", len(active))
+		for _, alert := range active {
+			fmt.Printf("   [%s] %s\n", alert.Rule, alert.Message)
+		}
+	}
+
+	if len(ae.recent) > 0 {
+		fmt.Println("📜 Recent alert changes:")
+		start := len(ae.recent) - 5
+		if start < 0 {
+			start = 0
+		}
+		for _, alert := range ae.recent[start:] {
+			fmt.Printf("   %s %-8s %s\n", alert.Time.Format("15:04:05"), alert.State, alert.Message)
+		}
+	}
+	if ae.sinkErr != nil {
+		slog.Warn("Alert delivery failed", "error", ae.sinkErr)
+	}
+}
@@ -0,0 +1,343 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"benchy/internal/clique"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// blockPropagationPoll is the head polling interval of nodes without WS,
+	// short enough to be well below the delays it measures.
+	blockPropagationPoll = 50 * time.Millisecond
+	// blockPropagationGrace is how long a block must be known before a node
+	// that has not seen it counts as having missed it.
+	blockPropagationGrace = 5 * time.Second
+)
+
+// BlockPropagation records the wall-clock time each node first reported
+// each new head, to measure how long blocks take to reach the nodes after
+// their sealer had them.
+type BlockPropagation struct {
+	mu      sync.Mutex
+	clients map[string]string
+	signers map[common.Address]string
+	blocks  map[common.Hash]*blockSighting
+	order   []common.Hash
+	// start is the highest head when tracking began; those blocks and
+	// the older ones were not sealed during the run
+	start   uint64
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped time.Time
+}
+
+type blockSighting struct {
+	number uint64
+	// sealer is the node that sealed the block, empty if it is not a
+	// selected node
+	sealer string
+	seen   map[string]time.Time
+}
+
+// TrackBlockPropagation follows the head of every node, over newHeads when
+// the node has a WebSocket endpoint and by tight polling otherwise, until
+// Stop is called.
+func (nm *NetworkMonitor) TrackBlockPropagation(ctx context.Context) *BlockPropagation {
+	signersCtx, cancelSigners := context.WithTimeout(ctx, 5*time.Second)
+	signers := SignerNames(signersCtx)
+	start := nm.highestHead(signersCtx)
+	cancelSigners()
+
+	ctx, cancel := context.WithCancel(ctx)
+	bp := &BlockPropagation{
+		clients: make(map[string]string),
+		signers: signers,
+		blocks:  make(map[common.Hash]*blockSighting),
+		start:   start,
+		cancel:  cancel,
+	}
+	for name, node := range nm.nodes {
+		bp.clients[name] = node.Client
+		bp.wg.Add(1)
+		go func(name string) {
+			defer bp.wg.Done()
+			bp.watch(ctx, name)
+		}(name)
+	}
+	return bp
+}
+
+// highestHead returns the highest block number the nodes report, 0 when
+// none answers.
+func (nm *NetworkMonitor) highestHead(ctx context.Context) uint64 {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var head uint64
+	for name := range nm.nodes {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			client, err := ethclient.DialContext(ctx, nodeEndpoints[name])
+			if err != nil {
+				return
+			}
+			defer client.Close()
+			if number, err := client.BlockNumber(ctx); err == nil {
+				mu.Lock()
+				head = max(head, number)
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+	return head
+}
+
+// Stop ends the tracking and waits for the watchers.
+func (bp *BlockPropagation) Stop() {
+	bp.cancel()
+	bp.wg.Wait()
+	bp.mu.Lock()
+	bp.stopped = time.Now()
+	bp.mu.Unlock()
+}
+
+func (bp *BlockPropagation) watch(ctx context.Context, name string) {
+	for ctx.Err() == nil {
+		if endpoint, ok := wsEndpoints[name]; ok {
+			if err := bp.subscribe(ctx, name, endpoint); err == nil {
+				return
+			}
+		}
+		bp.poll(ctx, name, liveWSRetry)
+	}
+}
+
+func (bp *BlockPropagation) subscribe(ctx context.Context, name, endpoint string) error {
+	client, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	heads := make(chan *types.Header, 16)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case header := <-heads:
+			bp.record(name, header, time.Now())
+		case err := <-sub.Err():
+			return err
+		}
+	}
+}
+
+// poll follows the head over HTTP for duration, or until ctx is done.
+func (bp *BlockPropagation) poll(ctx context.Context, name string, duration time.Duration) {
+	client, err := ethclient.DialContext(ctx, nodeEndpoints[name])
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	deadline := time.NewTimer(duration)
+	defer deadline.Stop()
+	ticker := time.NewTicker(blockPropagationPoll)
+	defer ticker.Stop()
+	var last common.Hash
+	for {
+		reqCtx, cancel := context.WithTimeout(ctx, time.Second)
+		header, err := client.HeaderByNumber(reqCtx, nil)
+		cancel()
+		if err == nil && header.Hash() != last {
+			last = header.Hash()
+			bp.record(name, header, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (bp *BlockPropagation) record(name string, header *types.Header, at time.Time) {
+	// A first poll, or a node catching up, reports blocks from before the
+	// run
+	if header.Number.Uint64() <= bp.start {
+		return
+	}
+	hash := header.Hash()
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	sighting, ok := bp.blocks[hash]
+	if !ok {
+		sighting = &blockSighting{number: header.Number.Uint64(), seen: make(map[string]time.Time)}
+		if signer, err := clique.RecoverSigner(header); err == nil {
+			sighting.sealer = bp.signers[signer]
+		}
+		bp.blocks[hash] = sighting
+		bp.order = append(bp.order, hash)
+	}
+	if _, seen := sighting.seen[name]; !seen {
+		sighting.seen[name] = at
+	}
+}
+
+// PropagationDelays is the distribution of the delays of one node, or of
+// the nodes running one client.
+type PropagationDelays struct {
+	Name      string
+	Client    string
+	Delays    LatencySummary
+	Histogram LatencyHistogram
+}
+
+// BlockPropagationReport summarizes how long blocks took to reach each node
+// after their sealer reported them. The sealer's own sightings are left out.
+type BlockPropagationReport struct {
+	Blocks int
+	// Unattributed counts the blocks whose sealer is not a selected node or
+	// missed them; their delays are relative to the first node seeing them.
+	Unattributed int
+	Nodes        []PropagationDelays
+	Clients      []PropagationDelays
+}
+
+// Report summarizes the delays recorded so far.
+func (bp *BlockPropagation) Report() BlockPropagationReport {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	end := bp.stopped
+	if end.IsZero() {
+		end = time.Now()
+	}
+	report := BlockPropagationReport{}
+	delays := make(map[string][]time.Duration)
+	missed := make(map[string]int)
+	for _, hash := range bp.order {
+		sighting := bp.blocks[hash]
+		report.Blocks++
+
+		reference, ok := sighting.seen[sighting.sealer]
+		if !ok {
+			report.Unattributed++
+			for _, at := range sighting.seen {
+				if reference.IsZero() || at.Before(reference) {
+					reference = at
+				}
+			}
+		}
+		for name := range bp.clients {
+			if name == sighting.sealer {
+				continue
+			}
+			at, seen := sighting.seen[name]
+			if !seen {
+				if end.Sub(reference) >= blockPropagationGrace {
+					missed[name]++
+				}
+				continue
+			}
+			// The sealer's notification can trail a peer's by a few
+			// microseconds; that is no propagation delay
+			delays[name] = append(delays[name], max(0, at.Sub(reference)))
+		}
+	}
+
+	byClient := make(map[string][]time.Duration)
+	missedByClient := make(map[string]int)
+	var clients []string
+	for _, name := range NodeOrder {
+		client, tracked := bp.clients[name]
+		if !tracked {
+			continue
+		}
+		report.Nodes = append(report.Nodes, PropagationDelays{
+			Name:      name,
+			Client:    client,
+			Delays:    SummarizeLatencies(delays[name], missed[name]),
+			Histogram: NewLatencyHistogram(delays[name]),
+		})
+		if _, known := byClient[client]; !known {
+			clients = append(clients, client)
+		}
+		byClient[client] = append(byClient[client], delays[name]...)
+		missedByClient[client] += missed[name]
+	}
+	for _, client := range clients {
+		report.Clients = append(report.Clients, PropagationDelays{
+			Client:    client,
+			Delays:    SummarizeLatencies(byClient[client], missedByClient[client]),
+			Histogram: NewLatencyHistogram(byClient[client]),
+		})
+	}
+	return report
+}
+
+// DisplayBlockPropagation prints the delay distributions and histograms.
+func DisplayBlockPropagation(report BlockPropagationReport) {
+	if report.Blocks == 0 {
+		fmt.Println("\n🧱 Block propagation: no new block during the run")
+		return
+	}
+	fmt.Printf("\n🧱 Block propagation over %d blocks (delay after the sealer, ms: min / p50 / avg / p95 / max):\n", report.Blocks)
+	if report.Unattributed > 0 {
+		fmt.Printf("   %d block(s) measured from their first sighting, their sealer was not watched\n", report.Unattributed)
+	}
+	for _, node := range report.Nodes {
+		displayDelays(fmt.Sprintf("%s (%s)", node.Name, node.Client), node)
+	}
+	fmt.Println("   Per client:")
+	for _, client := range report.Clients {
+		displayDelays(client.Client, client)
+	}
+}
+
+func displayDelays(label string, delays PropagationDelays) {
+	summary := delays.Delays
+	line := fmt.Sprintf("   %-24s n/a", label)
+	if summary.Samples > 0 {
+		line = fmt.Sprintf("   %-24s %.0f / %.0f / %.0f / %.0f / %.0f", label,
+			summary.Min, summary.P50, summary.Avg, summary.P95, summary.Max)
+	}
+	if summary.Missed > 0 {
+		line += fmt.Sprintf(" (%d missed)", summary.Missed)
+	}
+	fmt.Println(line)
+	if summary.Samples == 0 {
+		return
+	}
+
+	largest := 0
+	for _, count := range delays.Histogram.Counts {
+		largest = max(largest, count)
+	}
+	for i, count := range delays.Histogram.Counts {
+		if count == 0 {
+			continue
+		}
+		bar := strings.Repeat("█", max(1, count*30/largest))
+		fmt.Printf("      %-11s %-30s %d\n", delays.Histogram.Label(i), bar, count)
+	}
+}
@@ -1,31 +1,114 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 )
 
-func (nm *NetworkMonitor) DisplayNetworkInfoContinuous(updateInterval int) error {
+// DisplayNetworkInfoContinuous refreshes the network view every
+// updateInterval seconds. Alerts are evaluated when alerts is not nil and
+// client log errors are shown when logErrors is not nil.
+func (nm *NetworkMonitor) DisplayNetworkInfoContinuous(updateInterval int, alerts *AlertEngine, logErrors LogErrorSource) error {
 	if updateInterval <= 0 {
 		updateInterval = 60 // Default 60 seconds
 	}
-	
-	fmt.Printf("🔄 Starting continuous monitoring (update every %d seconds)...\n", updateInterval)
+
+	slog.Info("🔄 Starting continuous monitoring", "interval_seconds", updateInterval)
 	fmt.Println("Press Ctrl+C to stop")
-	
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Node state is kept current in the background; each refresh only
+	// renders what has already been collected.
+	live := NewLiveMonitor(nm)
+	live.Start(ctx)
+	if alerts != nil {
+		alerts.Run(ctx, live)
+	}
+
+	// Leave the watchers a moment to report before the first render
+	time.Sleep(livePollInterval)
+
+	ticker := time.NewTicker(time.Duration(updateInterval) * time.Second)
+	defer ticker.Stop()
+
 	for {
 		// Clear screen (simple)
 		fmt.Print("\033[2J\033[H")
-		
+
 		// Display timestamp
 		fmt.Printf("🕐 Last update: %s\n\n", time.Now().Format("15:04:05"))
-		
-		// Utiliser la version optimisée pour les mises à jour continues
-		if err := nm.DisplayNetworkInfoFast(); err != nil {
-			return err
+
+		displayLiveSnapshot(live.Snapshot())
+		DisplayResourceSummaries(live.ResourceSummaries())
+		DisplayHostSummary(live.HostSummary())
+		if logErrors != nil {
+			DisplayLogErrors(logErrors.Counts())
+		}
+		if alerts != nil {
+			alerts.DisplayAlerts()
+		}
+
+		<-ticker.C
+	}
+}
+
+func displayLiveSnapshot(states map[string]LiveNodeState) {
+	fmt.Println("📊 LIVE Network Information:")
+	fmt.Println("=" + strings.Repeat("=", 120))
+
+	fmt.Printf("%-12s %-11s %-8s %-5s %-8s %-10s %-6s %-6s %-6s %-15s %-8s %-8s\n",
+		"Node", "Client", "Status", "Feed", "Block", "Head", "Age", "Peers", "CPU%", "Memory", "TxPool", "Seen")
+	fmt.Println("-" + strings.Repeat("-", 120))
+
+	for _, name := range NodeOrder {
+		state, exists := states[name]
+		if !exists {
+			continue
+		}
+
+		status := "🔴 OFF"
+		if state.Online {
+			status = "🟢 ON"
+		}
+
+		head := "-"
+		age := "-"
+		if !state.LastHeadAt.IsZero() {
+			head = state.BlockHash.Hex()[:10]
+			age = fmt.Sprintf("%ds", int(time.Since(state.LastHeadAt).Seconds()))
 		}
-		
-		// Wait for next update
-		time.Sleep(time.Duration(updateInterval) * time.Second)
+
+		seen := "-"
+		if state.Source == "ws" {
+			seen = fmt.Sprintf("%d", state.PendingSeen)
+		}
+
+		memoryDisplay := "N/A"
+		if state.MemoryUsage != "" {
+			memoryDisplay = state.MemoryUsage
+		}
+
+		fmt.Printf("%-12s %-11s %-8s %-5s #%-7d %-10s %-6s %-6d %5.1f%% %-15s %-8d %-8s\n",
+			state.Name,
+			state.Client,
+			status,
+			state.Source,
+			state.BlockNumber,
+			head,
+			age,
+			state.PeerCount,
+			state.CPUUsage,
+			memoryDisplay,
+			state.TxPoolSize,
+			seen,
+		)
 	}
-}
\ No newline at end of file
+
+	fmt.Println("=" + strings.Repeat("=", 120))
+	fmt.Println("🔗 Consensus: Clique PoA | Network ID: 1337 | Validators: Alice, Bob, Cassandra")
+}
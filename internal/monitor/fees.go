@@ -0,0 +1,171 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// feeHistoryLimit is the most blocks a single eth_feeHistory call returns.
+const feeHistoryLimit = 1024
+
+// FeePercentiles are the priority fee percentiles read per block.
+var FeePercentiles = []float64{10, 50, 90}
+
+// BlockFees is the fee market state of one block.
+type BlockFees struct {
+	Number  uint64
+	BaseFee *big.Int
+	// Tips are the priority fees paid at FeePercentiles, weighted by gas
+	Tips         []*big.Int
+	Transactions int
+	GasUsed      uint64
+	GasLimit     uint64
+}
+
+// GasUsedRatio is the share of the gas limit used.
+func (b BlockFees) GasUsedRatio() float64 {
+	if b.GasLimit == 0 {
+		return 0
+	}
+	return float64(b.GasUsed) / float64(b.GasLimit)
+}
+
+// Fullness is the gas used relative to the EIP-1559 target of half the
+// limit: above 1 the base fee rises, below it falls.
+func (b BlockFees) Fullness() float64 {
+	return 2 * b.GasUsedRatio()
+}
+
+// AnalyzeFees reads the fees of blocks first to last with eth_feeHistory
+// and the headers.
+func AnalyzeFees(ctx context.Context, client *ethclient.Client, first, last uint64) ([]BlockFees, error) {
+	var blocks []BlockFees
+	for start := first; start <= last; start += feeHistoryLimit {
+		end := min(last, start+feeHistoryLimit-1)
+		history, err := client.FeeHistory(ctx, end-start+1, new(big.Int).SetUint64(end), FeePercentiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fee history: %v", err)
+		}
+		oldest := history.OldestBlock.Uint64()
+		for i := range history.GasUsedRatio {
+			number := oldest + uint64(i)
+			block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read block %d: %v", number, err)
+			}
+			fees := BlockFees{
+				Number:       number,
+				BaseFee:      new(big.Int),
+				Transactions: len(block.Transactions()),
+				GasUsed:      block.GasUsed(),
+				GasLimit:     block.GasLimit(),
+			}
+			if i < len(history.BaseFee) && history.BaseFee[i] != nil {
+				fees.BaseFee = history.BaseFee[i]
+			}
+			if i < len(history.Reward) {
+				fees.Tips = history.Reward[i]
+			}
+			blocks = append(blocks, fees)
+		}
+	}
+	return blocks, nil
+}
+
+// FeeSummary aggregates the blocks of a run.
+type FeeSummary struct {
+	Blocks int
+	// Base fees in gwei
+	BaseFeeMin, BaseFeeAvg, BaseFeeMax float64
+	// Median tips in gwei
+	TipMedianMin, TipMedianAvg, TipMedianMax float64
+	GasUsedRatioAvg, GasUsedRatioMax         float64
+	FullnessAvg                              float64
+	Transactions                             int
+}
+
+func SummarizeFees(blocks []BlockFees) FeeSummary {
+	summary := FeeSummary{Blocks: len(blocks)}
+	if len(blocks) == 0 {
+		return summary
+	}
+	tips := 0
+	for i, block := range blocks {
+		baseFee := Gwei(block.BaseFee)
+		ratio := block.GasUsedRatio()
+		if i == 0 || baseFee < summary.BaseFeeMin {
+			summary.BaseFeeMin = baseFee
+		}
+		summary.BaseFeeMax = max(summary.BaseFeeMax, baseFee)
+		summary.BaseFeeAvg += baseFee
+		summary.GasUsedRatioAvg += ratio
+		summary.GasUsedRatioMax = max(summary.GasUsedRatioMax, ratio)
+		summary.FullnessAvg += block.Fullness()
+		summary.Transactions += block.Transactions
+
+		// Empty blocks report zero tips, which says nothing about the market
+		if block.Transactions == 0 || len(block.Tips) < 2 {
+			continue
+		}
+		tip := Gwei(block.Tips[1])
+		if tips == 0 || tip < summary.TipMedianMin {
+			summary.TipMedianMin = tip
+		}
+		summary.TipMedianMax = max(summary.TipMedianMax, tip)
+		summary.TipMedianAvg += tip
+		tips++
+	}
+	count := float64(len(blocks))
+	summary.BaseFeeAvg /= count
+	summary.GasUsedRatioAvg /= count
+	summary.FullnessAvg /= count
+	if tips > 0 {
+		summary.TipMedianAvg /= float64(tips)
+	}
+	return summary
+}
+
+// Gwei converts wei for display.
+func Gwei(wei *big.Int) float64 {
+	if wei == nil {
+		return 0
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei)).Float64()
+	return gwei
+}
+
+// DisplayFees prints the fee market per block and its summary.
+func DisplayFees(blocks []BlockFees) {
+	if len(blocks) == 0 {
+		fmt.Println("\n⛽ Fee market: no block during the run")
+		return
+	}
+	fmt.Printf("\n⛽ Fee market over blocks #%d-#%d:\n", blocks[0].Number, blocks[len(blocks)-1].Number)
+	fmt.Printf("%-9s %-14s %-30s %-6s %-9s %-9s\n", "Block", "Base fee", "Tips p10 / p50 / p90 (gwei)", "Txs", "Gas used", "Fullness")
+	fmt.Println("-" + strings.Repeat("-", 80))
+	for _, block := range blocks {
+		tips := "-"
+		if block.Transactions > 0 && len(block.Tips) == len(FeePercentiles) {
+			parts := make([]string, len(block.Tips))
+			for i, tip := range block.Tips {
+				parts[i] = fmt.Sprintf("%.3f", Gwei(tip))
+			}
+			tips = strings.Join(parts, " / ")
+		}
+		fmt.Printf("#%-8d %-14s %-30s %-6d %8.1f%% %8.2f\n", block.Number, fmt.Sprintf("%.4f gwei", Gwei(block.BaseFee)),
+			tips, block.Transactions, 100*block.GasUsedRatio(), block.Fullness())
+	}
+
+	summary := SummarizeFees(blocks)
+	fmt.Printf("   Base fee: %.4f / %.4f / %.4f gwei (min / avg / max)\n", summary.BaseFeeMin, summary.BaseFeeAvg, summary.BaseFeeMax)
+	if summary.TipMedianMax > 0 {
+		fmt.Printf("   Median tip: %.3f / %.3f / %.3f gwei (min / avg / max)\n", summary.TipMedianMin, summary.TipMedianAvg, summary.TipMedianMax)
+	}
+	fmt.Printf("   Gas used: %.1f%% avg, %.1f%% max of the limit; fullness %.2f of the target\n",
+		100*summary.GasUsedRatioAvg, 100*summary.GasUsedRatioMax, summary.FullnessAvg)
+}
@@ -0,0 +1,313 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+)
+
+// HostSaturationThreshold is the CPU, memory or disk usage percentage above
+// which the host is considered the bottleneck of a run.
+var HostSaturationThreshold = 90.0
+
+// DiskUsage is the filesystem usage of a directory holding node data.
+type DiskUsage struct {
+	Path        string
+	TotalBytes  uint64
+	UsedBytes   uint64
+	UsedPercent float64
+}
+
+// HostSample is one reading of the host. Disk I/O values are cumulative
+// counters over all physical disks.
+type HostSample struct {
+	Time             time.Time
+	CPUPercent       float64
+	MemoryUsedBytes  uint64
+	MemoryTotalBytes uint64
+	MemoryPercent    float64
+	Disks            []DiskUsage
+	DiskReadBytes    uint64
+	DiskWriteBytes   uint64
+}
+
+// Saturation lists the resources above threshold in this sample.
+func (s HostSample) Saturation(threshold float64) []string {
+	var saturated []string
+	if s.CPUPercent >= threshold {
+		saturated = append(saturated, fmt.Sprintf("CPU %.1f%%", s.CPUPercent))
+	}
+	if s.MemoryPercent >= threshold {
+		saturated = append(saturated, fmt.Sprintf("memory %.1f%%", s.MemoryPercent))
+	}
+	for _, usage := range s.Disks {
+		if usage.UsedPercent >= threshold {
+			saturated = append(saturated, fmt.Sprintf("disk %s %.1f%%", usage.Path, usage.UsedPercent))
+		}
+	}
+	return saturated
+}
+
+// HostSummary describes the host over a run.
+type HostSummary struct {
+	Samples          int           `json:"samples" yaml:"samples"`
+	CPUPercent       MetricSummary `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryPercent    MetricSummary `json:"memory_percent" yaml:"memory_percent"`
+	MemoryTotalBytes uint64        `json:"memory_total_bytes" yaml:"memory_total_bytes"`
+	DiskReadRate     MetricSummary `json:"disk_read_bytes_per_sec" yaml:"disk_read_bytes_per_sec"`
+	DiskWriteRate    MetricSummary `json:"disk_write_bytes_per_sec" yaml:"disk_write_bytes_per_sec"`
+	// Highest usage seen per data directory
+	DiskUsedPercent  map[string]float64 `json:"disk_used_percent" yaml:"disk_used_percent"`
+	Threshold        float64            `json:"saturation_threshold" yaml:"saturation_threshold"`
+	SaturatedSamples int                `json:"saturated_samples" yaml:"saturated_samples"`
+	Warnings         []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// Saturated reports whether the host crossed the threshold during the run.
+func (s HostSummary) Saturated() bool {
+	return s.SaturatedSamples > 0
+}
+
+// HostSampler keeps a bounded history of host level metrics.
+type HostSampler struct {
+	paths   []string
+	mu      sync.RWMutex
+	history []HostSample
+}
+
+// NewHostSampler samples disk usage of the filesystems holding paths.
+func NewHostSampler(paths []string) *HostSampler {
+	return &HostSampler{paths: paths}
+}
+
+// Start samples the host each interval until ctx is cancelled.
+func (hs *HostSampler) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			hs.SampleOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SampleOnce records one host sample. Metrics that cannot be read are left
+// at zero rather than dropping the whole sample.
+func (hs *HostSampler) SampleOnce(ctx context.Context) HostSample {
+	sample := HostSample{Time: time.Now()}
+
+	// CPU usage since the previous call; the first call measures over 0s
+	if percents, err := cpu.PercentWithContext(ctx, 0, false); err == nil && len(percents) > 0 {
+		sample.CPUPercent = percents[0]
+	}
+	if memory, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		sample.MemoryUsedBytes = memory.Used
+		sample.MemoryTotalBytes = memory.Total
+		sample.MemoryPercent = memory.UsedPercent
+	}
+	for _, path := range hs.paths {
+		usage, err := disk.UsageWithContext(ctx, path)
+		if err != nil {
+			continue
+		}
+		sample.Disks = append(sample.Disks, DiskUsage{
+			Path:        path,
+			TotalBytes:  usage.Total,
+			UsedBytes:   usage.Used,
+			UsedPercent: usage.UsedPercent,
+		})
+	}
+	if counters, err := disk.IOCountersWithContext(ctx); err == nil {
+		for name, counter := range counters {
+			if !physicalDisk(name) {
+				continue
+			}
+			sample.DiskReadBytes += counter.ReadBytes
+			sample.DiskWriteBytes += counter.WriteBytes
+		}
+	}
+
+	hs.mu.Lock()
+	hs.history = append(hs.history, sample)
+	if len(hs.history) > resourceHistorySize {
+		hs.history = hs.history[len(hs.history)-resourceHistorySize:]
+	}
+	hs.mu.Unlock()
+
+	return sample
+}
+
+// physicalDisk reports whether an I/O counter is a whole physical disk.
+// Linux also counts partitions, which repeat their disk's I/O, and virtual
+// devices (loop, device mapper, zram) that end up on a disk or in memory:
+// only disks under /sys/block with a backing device are kept. Elsewhere
+// the counters are whole disks already.
+func physicalDisk(name string) bool {
+	if _, err := os.Stat("/sys/block"); err != nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join("/sys/block", name, "device"))
+	return err == nil
+}
+
+// Latest returns the most recent host sample.
+func (hs *HostSampler) Latest() (HostSample, bool) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	if len(hs.history) == 0 {
+		return HostSample{}, false
+	}
+	return hs.history[len(hs.history)-1], true
+}
+
+// Summary aggregates the history against HostSaturationThreshold.
+func (hs *HostSampler) Summary() HostSummary {
+	hs.mu.RLock()
+	samples := append([]HostSample(nil), hs.history...)
+	hs.mu.RUnlock()
+
+	summary := HostSummary{
+		Samples:         len(samples),
+		Threshold:       HostSaturationThreshold,
+		DiskUsedPercent: make(map[string]float64),
+	}
+	if len(samples) == 0 {
+		return summary
+	}
+
+	var cpuValues, memoryValues, reads, writes []float64
+	warned := make(map[string]bool)
+	for i, sample := range samples {
+		cpuValues = append(cpuValues, sample.CPUPercent)
+		memoryValues = append(memoryValues, sample.MemoryPercent)
+		for _, usage := range sample.Disks {
+			if usage.UsedPercent > summary.DiskUsedPercent[usage.Path] {
+				summary.DiskUsedPercent[usage.Path] = usage.UsedPercent
+			}
+		}
+
+		if saturated := sample.Saturation(HostSaturationThreshold); len(saturated) > 0 {
+			summary.SaturatedSamples++
+			for _, resource := range saturated {
+				// Only the resource name, values vary between samples
+				name := resource[:strings.LastIndex(resource, " ")]
+				if !warned[name] {
+					warned[name] = true
+					summary.Warnings = append(summary.Warnings,
+						fmt.Sprintf("%s at %s", resource, sample.Time.Format("15:04:05")))
+				}
+			}
+		}
+
+		if i == 0 {
+			continue
+		}
+		previous := samples[i-1]
+		elapsed := sample.Time.Sub(previous.Time).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+		reads = append(reads, counterRate(previous.DiskReadBytes, sample.DiskReadBytes, elapsed))
+		writes = append(writes, counterRate(previous.DiskWriteBytes, sample.DiskWriteBytes, elapsed))
+	}
+
+	summary.CPUPercent = summarize(cpuValues)
+	summary.MemoryPercent = summarize(memoryValues)
+	summary.MemoryTotalBytes = samples[len(samples)-1].MemoryTotalBytes
+	summary.DiskReadRate = summarize(reads)
+	summary.DiskWriteRate = summarize(writes)
+	return summary
+}
+
+// DataPaths returns the directories holding node data: the volumes mounted
+// in the containers and the Docker root directory for their writable layers.
+func (rs *ResourceSampler) DataPaths(ctx context.Context) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, name := range rs.containers {
+		inspect, err := rs.docker.ContainerInspect(ctx, name)
+		if err != nil {
+			continue
+		}
+		for _, mount := range inspect.Mounts {
+			add(mount.Source)
+		}
+	}
+	if info, err := rs.docker.Info(ctx); err == nil {
+		add(info.DockerRootDir)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// NodeDataPaths resolves the data directories of every benchy container, or
+// nil when the Docker API is unavailable.
+func NodeDataPaths(ctx context.Context) []string {
+	containers := make([]string, 0, len(NodeOrder))
+	for _, name := range NodeOrder {
+		containers = append(containers, fmt.Sprintf("benchy-%s", name))
+	}
+	sampler, err := NewResourceSampler(containers)
+	if err != nil {
+		return nil
+	}
+	defer sampler.Close()
+	return sampler.DataPaths(ctx)
+}
+
+// DisplayHostSummary prints host usage and any saturation warnings.
+func DisplayHostSummary(summary HostSummary) {
+	if summary.Samples == 0 {
+		return
+	}
+	fmt.Println("\n🖥️  Host resources over the run (min / avg / max / p95):")
+	fmt.Printf("   CPU:        %.1f / %.1f / %.1f / %.1f %%\n",
+		summary.CPUPercent.Min, summary.CPUPercent.Avg, summary.CPUPercent.Max, summary.CPUPercent.P95)
+	fmt.Printf("   Memory:     %.1f / %.1f / %.1f / %.1f %% of %s\n",
+		summary.MemoryPercent.Min, summary.MemoryPercent.Avg, summary.MemoryPercent.Max, summary.MemoryPercent.P95,
+		formatByteSize(summary.MemoryTotalBytes))
+	fmt.Printf("   Disk R/W:   %.1f / %.1f KiB/s avg, %.1f / %.1f KiB/s max\n",
+		summary.DiskReadRate.Avg/1024, summary.DiskWriteRate.Avg/1024,
+		summary.DiskReadRate.Max/1024, summary.DiskWriteRate.Max/1024)
+
+	paths := make([]string, 0, len(summary.DiskUsedPercent))
+	for path := range summary.DiskUsedPercent {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Printf("   Disk usage: %s %.1f%%\n", path, summary.DiskUsedPercent[path])
+	}
+
+	if summary.Saturated() {
+		fmt.Printf("⚠️  Host saturated (≥ %.0f%%) in %d/%d samples, results may be host bound:\n",
+			summary.Threshold, summary.SaturatedSamples, summary.Samples)
+		for _, warning := range summary.Warnings {
+			fmt.Printf("   - %s\n", warning)
+		}
+	}
+}
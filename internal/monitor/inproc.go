@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"benchy/internal/devnet"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// InprocRuntime is the ContainerRuntime of the in-process devnet. Its nodes
+// share one process, so each reports the Go runtime stats of that process:
+// goroutines stand in for PIDs.
+type InprocRuntime struct{}
+
+func (InprocRuntime) ContainerStats(containerName string) (*ContainerStats, error) {
+	endpoint, exists := nodeEndpoints[strings.TrimPrefix(containerName, "benchy-")]
+	if !exists {
+		return &ContainerStats{IsRunning: false}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return &ContainerStats{IsRunning: false}, nil
+	}
+	defer client.Close()
+
+	var stats devnet.RuntimeStats
+	if err := client.CallContext(ctx, &stats, devnet.StatsMethod); err != nil {
+		return &ContainerStats{IsRunning: false}, nil
+	}
+	return &ContainerStats{
+		CPUUsage:         stats.CPUPercent,
+		MemoryUsage:      fmt.Sprintf("%s / %s", formatByteSize(stats.MemoryBytes), formatByteSize(stats.MemoryLimitBytes)),
+		MemoryLimit:      formatByteSize(stats.MemoryLimitBytes),
+		IsRunning:        true,
+		MemoryUsageBytes: stats.MemoryBytes,
+		MemoryLimitBytes: stats.MemoryLimitBytes,
+		PIDs:             uint64(stats.Goroutines),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// LatencySummary describes a latency distribution in milliseconds. Missed
+// counts the expected observations that never happened.
+type LatencySummary struct {
+	Samples int     `json:"samples" yaml:"samples"`
+	Missed  int     `json:"missed" yaml:"missed"`
+	Min     float64 `json:"min_ms" yaml:"min_ms"`
+	P50     float64 `json:"p50_ms" yaml:"p50_ms"`
+	Avg     float64 `json:"avg_ms" yaml:"avg_ms"`
+	P95     float64 `json:"p95_ms" yaml:"p95_ms"`
+	Max     float64 `json:"max_ms" yaml:"max_ms"`
+}
+
+func SummarizeLatencies(latencies []time.Duration, missed int) LatencySummary {
+	summary := LatencySummary{Samples: len(latencies), Missed: missed}
+	if len(latencies) == 0 {
+		return summary
+	}
+	sorted := make([]float64, len(latencies))
+	total := 0.0
+	for i, latency := range latencies {
+		sorted[i] = float64(latency) / float64(time.Millisecond)
+		total += sorted[i]
+	}
+	sort.Float64s(sorted)
+
+	summary.Min = sorted[0]
+	summary.P50 = percentile(sorted, 0.50)
+	summary.Avg = total / float64(len(sorted))
+	summary.P95 = percentile(sorted, 0.95)
+	summary.Max = sorted[len(sorted)-1]
+	return summary
+}
+
+// percentile uses the nearest rank of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// latencyBuckets are the upper bounds of the histogram buckets, in
+// milliseconds.
+var latencyBuckets = []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// LatencyHistogram counts latencies per bucket. Counts has one more entry
+// than Bounds for the latencies above the last bound.
+type LatencyHistogram struct {
+	Bounds []float64 `json:"bounds_ms" yaml:"bounds_ms"`
+	Counts []int     `json:"counts" yaml:"counts"`
+}
+
+func NewLatencyHistogram(latencies []time.Duration) LatencyHistogram {
+	histogram := LatencyHistogram{Bounds: latencyBuckets, Counts: make([]int, len(latencyBuckets)+1)}
+	for _, latency := range latencies {
+		ms := float64(latency) / float64(time.Millisecond)
+		bucket := sort.SearchFloat64s(latencyBuckets, ms)
+		histogram.Counts[bucket]++
+	}
+	return histogram
+}
+
+// Label names bucket i, such as "25-50ms" or ">5000ms".
+func (h LatencyHistogram) Label(i int) string {
+	switch {
+	case i == 0:
+		return fmt.Sprintf("≤%.0fms", h.Bounds[0])
+	case i == len(h.Bounds):
+		return fmt.Sprintf(">%.0fms", h.Bounds[i-1])
+	}
+	return fmt.Sprintf("%.0f-%.0fms", h.Bounds[i-1], h.Bounds[i])
+}
@@ -0,0 +1,521 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	livePollInterval  = 2 * time.Second  // HTTP head polling for nodes without WS
+	liveSlowInterval  = 10 * time.Second // peers and txpool, for every node
+	liveStatsInterval = 5 * time.Second  // one docker stats call for all containers
+	liveWSRetry       = 30 * time.Second // how long to poll before retrying WS
+	liveSeenBlocks    = 256              // block hashes remembered for receipt checks
+	liveEventBuffer   = 128
+)
+
+type LiveEventKind string
+
+const (
+	EventNodeOffline LiveEventKind = "offline"
+	EventNodeOnline  LiveEventKind = "restart"
+	EventReorg       LiveEventKind = "reorg"
+	EventFailedTx    LiveEventKind = "failed-tx"
+	EventHostBusy    LiveEventKind = "host-saturated"
+	EventAlert       LiveEventKind = "alert"
+)
+
+type LiveEvent struct {
+	Time    time.Time
+	Node    string
+	Kind    LiveEventKind
+	Message string
+}
+
+// LiveNodeState is the incrementally updated view of one node.
+type LiveNodeState struct {
+	Name        string
+	Client      string
+	Source      string
+	Online      bool
+	BlockNumber uint64
+	BlockHash   common.Hash
+	BlockTime   time.Time
+	LastHeadAt  time.Time
+	// Transactions in the head block and the time since its parent
+	BlockTxCount  uint64
+	BlockInterval time.Duration
+	PeerCount     uint64
+	TxPoolSize    uint64
+	TxPoolPending uint64
+	PendingSeen   uint64
+	CPUUsage      float64
+	MemoryUsage   string
+	MemoryBytes   uint64
+	LastError     string
+}
+
+// TPS is the throughput of the head block over its interval with the parent.
+func (s LiveNodeState) TPS() float64 {
+	if s.BlockInterval <= 0 {
+		return 0
+	}
+	return float64(s.BlockTxCount) / s.BlockInterval.Seconds()
+}
+
+// LiveMonitor keeps node state up to date from subscriptions and background
+// polling, so rendering never has to query the nodes.
+type LiveMonitor struct {
+	mu        sync.RWMutex
+	states    map[string]*LiveNodeState
+	events    chan LiveEvent
+	seen      map[common.Hash]struct{}
+	seenOrder []common.Hash
+	resources *ResourceSampler
+	// containers is read when the Docker API is not used
+	containers ContainerRuntime
+	host       *HostSampler
+	saturated  bool
+}
+
+func NewLiveMonitor(nm *NetworkMonitor) *LiveMonitor {
+	states := make(map[string]*LiveNodeState)
+	for name, node := range nm.nodes {
+		states[name] = &LiveNodeState{
+			Name:   node.Name,
+			Client: node.Client,
+			Source: "http",
+		}
+	}
+	containers := make([]string, 0, len(states))
+	for name := range states {
+		containers = append(containers, fmt.Sprintf("benchy-%s", name))
+	}
+	sort.Strings(containers)
+
+	// Without a usable Docker API the monitor falls back to the docker CLI.
+	// Other runtimes are always read through the network monitor's.
+	var resources *ResourceSampler
+	var err error
+	if _, docker := nm.containers.(dockerStats); docker {
+		resources, err = NewResourceSampler(containers)
+	}
+	var dataPaths []string
+	if err != nil || resources == nil {
+		resources = nil
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		dataPaths = resources.DataPaths(ctx)
+		cancel()
+	}
+
+	return &LiveMonitor{
+		states:     states,
+		events:     make(chan LiveEvent, liveEventBuffer),
+		seen:       make(map[common.Hash]struct{}),
+		resources:  resources,
+		containers: nm.containers,
+		host:       NewHostSampler(dataPaths),
+	}
+}
+
+// HostSummary returns host usage since the monitor started.
+func (lm *LiveMonitor) HostSummary() HostSummary {
+	return lm.host.Summary()
+}
+
+// ResourceSummaries returns min/avg/max/p95 resource usage per container
+// since the monitor started.
+func (lm *LiveMonitor) ResourceSummaries() []ResourceSummary {
+	if lm.resources == nil {
+		return nil
+	}
+	return lm.resources.Summaries()
+}
+
+// Start launches one watcher per node plus the container stats sampler. They
+// stop when ctx is cancelled.
+func (lm *LiveMonitor) Start(ctx context.Context) {
+	for name := range lm.states {
+		go lm.watchNode(ctx, name)
+	}
+	go lm.sampleStats(ctx)
+}
+
+// Snapshot returns a copy of the current state of every node.
+func (lm *LiveMonitor) Snapshot() map[string]LiveNodeState {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	result := make(map[string]LiveNodeState, len(lm.states))
+	for name, state := range lm.states {
+		result[name] = *state
+	}
+	return result
+}
+
+func (lm *LiveMonitor) update(name string, fn func(state *LiveNodeState)) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	if state, ok := lm.states[name]; ok {
+		fn(state)
+	}
+}
+
+func (lm *LiveMonitor) recordHead(ctx context.Context, name, source string, client *ethclient.Client, header *types.Header) {
+	hash := header.Hash()
+	number := header.Number.Uint64()
+
+	var events []LiveEvent
+	isNew := false
+	lm.update(name, func(state *LiveNodeState) {
+		if !state.Online && !state.LastHeadAt.IsZero() {
+			events = append(events, LiveEvent{Kind: EventNodeOnline, Message: fmt.Sprintf("back online at #%d", number)})
+		}
+		state.Online = true
+		state.Source = source
+		state.LastError = ""
+		if number == state.BlockNumber && hash == state.BlockHash {
+			return
+		}
+		isNew = true
+
+		if !state.LastHeadAt.IsZero() {
+			if number <= state.BlockNumber || (number == state.BlockNumber+1 && header.ParentHash != state.BlockHash) {
+				events = append(events, LiveEvent{
+					Kind:    EventReorg,
+					Message: fmt.Sprintf("head #%d %s replaced by #%d %s", state.BlockNumber, state.BlockHash.Hex()[:10], number, hash.Hex()[:10]),
+				})
+			}
+		}
+
+		blockTime := time.Unix(int64(header.Time), 0)
+		state.BlockInterval = 0
+		if number == state.BlockNumber+1 && !state.BlockTime.IsZero() {
+			state.BlockInterval = blockTime.Sub(state.BlockTime)
+		}
+		state.BlockNumber = number
+		state.BlockHash = hash
+		state.BlockTime = blockTime
+		state.LastHeadAt = time.Now()
+		state.BlockTxCount = 0
+	})
+	for _, event := range events {
+		lm.emit(name, event)
+	}
+	if !isNew {
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, livePollInterval)
+	defer cancel()
+	txCount, err := client.TransactionCount(reqCtx, hash)
+	if err != nil {
+		return
+	}
+	lm.update(name, func(state *LiveNodeState) {
+		if state.BlockHash == hash {
+			state.BlockTxCount = uint64(txCount)
+		}
+	})
+
+	// Receipts are only checked by the first node reporting the block
+	if txCount > 0 && lm.markSeen(hash) {
+		lm.checkReceipts(reqCtx, name, client, number, hash)
+	}
+}
+
+func (lm *LiveMonitor) recordError(name string, err error) {
+	wasOnline := false
+	lm.update(name, func(state *LiveNodeState) {
+		wasOnline = state.Online
+		state.Online = false
+		state.LastError = err.Error()
+	})
+	if wasOnline {
+		lm.emit(name, LiveEvent{Kind: EventNodeOffline, Message: err.Error()})
+	}
+}
+
+// markSeen reports whether hash is seen for the first time across all nodes.
+func (lm *LiveMonitor) markSeen(hash common.Hash) bool {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if _, ok := lm.seen[hash]; ok {
+		return false
+	}
+	lm.seen[hash] = struct{}{}
+	lm.seenOrder = append(lm.seenOrder, hash)
+	if len(lm.seenOrder) > liveSeenBlocks {
+		delete(lm.seen, lm.seenOrder[0])
+		lm.seenOrder = lm.seenOrder[1:]
+	}
+	return true
+}
+
+func (lm *LiveMonitor) checkReceipts(ctx context.Context, name string, client *ethclient.Client, number uint64, hash common.Hash) {
+	receipts, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(hash, false))
+	if err != nil {
+		return
+	}
+	for _, receipt := range receipts {
+		if receipt.Status == types.ReceiptStatusFailed {
+			lm.emit(name, LiveEvent{
+				Kind:    EventFailedTx,
+				Message: fmt.Sprintf("tx %s failed in block #%d", receipt.TxHash.Hex(), number),
+			})
+		}
+	}
+}
+
+// emit queues an event without ever blocking the watchers; events are
+// dropped when nobody drains the channel.
+func (lm *LiveMonitor) emit(name string, event LiveEvent) {
+	event.Node = name
+	event.Time = time.Now()
+	select {
+	case lm.events <- event:
+	default:
+	}
+}
+
+// Events streams node restarts, reorgs and failed transactions.
+func (lm *LiveMonitor) Events() <-chan LiveEvent {
+	return lm.events
+}
+
+func (lm *LiveMonitor) watchNode(ctx context.Context, name string) {
+	for ctx.Err() == nil {
+		wsEndpoint, hasWS := wsEndpoints[name]
+		if !hasWS {
+			lm.poll(ctx, name, 0)
+			return
+		}
+		if err := lm.subscribe(ctx, name, wsEndpoint); err != nil {
+			lm.update(name, func(state *LiveNodeState) { state.LastError = err.Error() })
+		}
+		lm.poll(ctx, name, liveWSRetry)
+	}
+}
+
+// subscribe follows newHeads and newPendingTransactions until the connection
+// drops or ctx is cancelled.
+func (lm *LiveMonitor) subscribe(ctx context.Context, name, endpoint string) error {
+	rpcClient, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("ws dial failed: %v", err)
+	}
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	heads := make(chan *types.Header, 16)
+	headSub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return fmt.Errorf("newHeads subscription failed: %v", err)
+	}
+	defer headSub.Unsubscribe()
+
+	// Not every node allows pending tx subscriptions; heads alone are enough.
+	hashes := make(chan common.Hash, 256)
+	var txErr <-chan error
+	if txSub, err := rpcClient.EthSubscribe(ctx, hashes, "newPendingTransactions"); err == nil {
+		defer txSub.Unsubscribe()
+		txErr = txSub.Err()
+	}
+
+	if header, err := client.HeaderByNumber(ctx, nil); err == nil {
+		lm.recordHead(ctx, name, "ws", client, header)
+	}
+	lm.refreshSlow(ctx, name, rpcClient)
+
+	slow := time.NewTicker(liveSlowInterval)
+	defer slow.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case header := <-heads:
+			lm.recordHead(ctx, name, "ws", client, header)
+		case <-hashes:
+			lm.update(name, func(state *LiveNodeState) { state.PendingSeen++ })
+		case <-slow.C:
+			lm.refreshSlow(ctx, name, rpcClient)
+		case err := <-headSub.Err():
+			return fmt.Errorf("newHeads subscription dropped: %v", err)
+		case err := <-txErr:
+			return fmt.Errorf("newPendingTransactions subscription dropped: %v", err)
+		}
+	}
+}
+
+// poll follows the head over HTTP. A zero duration polls until ctx is done.
+func (lm *LiveMonitor) poll(ctx context.Context, name string, duration time.Duration) {
+	rpcClient, err := rpc.DialContext(ctx, nodeEndpoints[name])
+	if err != nil {
+		lm.recordError(name, err)
+		return
+	}
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	var deadline <-chan time.Time
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	ticker := time.NewTicker(livePollInterval)
+	defer ticker.Stop()
+	lastSlow := time.Time{}
+
+	for {
+		reqCtx, cancel := context.WithTimeout(ctx, livePollInterval)
+		header, err := client.HeaderByNumber(reqCtx, nil)
+		cancel()
+		if err != nil {
+			lm.recordError(name, err)
+		} else {
+			lm.recordHead(ctx, name, "http", client, header)
+			if time.Since(lastSlow) >= liveSlowInterval {
+				lm.refreshSlow(ctx, name, rpcClient)
+				lastSlow = time.Now()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshSlow updates values that change slowly enough to skip per-head work.
+func (lm *LiveMonitor) refreshSlow(ctx context.Context, name string, rpcClient *rpc.Client) {
+	reqCtx, cancel := context.WithTimeout(ctx, livePollInterval)
+	defer cancel()
+
+	var peers hexutil.Uint64
+	peersErr := rpcClient.CallContext(reqCtx, &peers, "net_peerCount")
+
+	var status struct {
+		Pending hexutil.Uint64 `json:"pending"`
+		Queued  hexutil.Uint64 `json:"queued"`
+	}
+	statusErr := rpcClient.CallContext(reqCtx, &status, "txpool_status")
+
+	lm.update(name, func(state *LiveNodeState) {
+		if peersErr == nil {
+			state.PeerCount = uint64(peers)
+		}
+		if statusErr == nil {
+			state.TxPoolSize = uint64(status.Pending) + uint64(status.Queued)
+			state.TxPoolPending = uint64(status.Pending)
+		}
+	})
+}
+
+// sampleStats refreshes container resources once per interval, through the
+// Docker API when available and a single docker stats call otherwise.
+func (lm *LiveMonitor) sampleStats(ctx context.Context) {
+	ticker := time.NewTicker(liveStatsInterval)
+	defer ticker.Stop()
+	if lm.resources != nil {
+		defer lm.resources.Close()
+	}
+
+	for {
+		lm.checkHost(lm.host.SampleOnce(ctx))
+		if lm.resources != nil {
+			lm.resources.SampleOnce(ctx)
+			lm.applySamples()
+		} else if allStats, err := lm.containerStats(); err == nil {
+			lm.mu.Lock()
+			for name, state := range lm.states {
+				stats, ok := allStats[fmt.Sprintf("benchy-%s", name)]
+				if !ok || !stats.IsRunning {
+					state.CPUUsage = 0
+					state.MemoryUsage = "0B / 0B"
+					state.MemoryBytes = 0
+					continue
+				}
+				state.CPUUsage = stats.CPUUsage
+				state.MemoryUsage = stats.MemoryUsage
+				state.MemoryBytes = stats.MemoryUsageBytes
+			}
+			lm.mu.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// containerStats reads every node's container in one docker stats call, or
+// one by one from a runtime other than Docker.
+func (lm *LiveMonitor) containerStats() (map[string]*ContainerStats, error) {
+	if _, docker := lm.containers.(dockerStats); docker {
+		return getAllContainerStats()
+	}
+	allStats := make(map[string]*ContainerStats)
+	for name := range lm.states {
+		containerName := fmt.Sprintf("benchy-%s", name)
+		if stats, err := lm.containers.ContainerStats(containerName); err == nil {
+			allStats[containerName] = stats
+		}
+	}
+	return allStats, nil
+}
+
+// checkHost emits an event when the host crosses the saturation threshold.
+func (lm *LiveMonitor) checkHost(sample HostSample) {
+	saturated := sample.Saturation(HostSaturationThreshold)
+	lm.mu.Lock()
+	wasSaturated := lm.saturated
+	lm.saturated = len(saturated) > 0
+	lm.mu.Unlock()
+
+	if len(saturated) > 0 && !wasSaturated {
+		lm.emit("host", LiveEvent{
+			Kind:    EventHostBusy,
+			Message: fmt.Sprintf("host saturated: %s", strings.Join(saturated, ", ")),
+		})
+	}
+}
+
+func (lm *LiveMonitor) applySamples() {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	for name, state := range lm.states {
+		sample, ok := lm.resources.Latest(fmt.Sprintf("benchy-%s", name))
+		// A stale sample means the container stopped answering
+		if !ok || time.Since(sample.Time) > 2*liveStatsInterval {
+			state.CPUUsage = 0
+			state.MemoryUsage = "0B / 0B"
+			state.MemoryBytes = 0
+			continue
+		}
+		state.CPUUsage = sample.CPUPercent
+		state.MemoryUsage = fmt.Sprintf("%s / %s", formatByteSize(sample.MemoryUsageBytes), formatByteSize(sample.MemoryLimitBytes))
+		state.MemoryBytes = sample.MemoryUsageBytes
+	}
+}
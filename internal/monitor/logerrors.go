@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LogErrorSource reports client log error counts by node and kind.
+type LogErrorSource interface {
+	Counts() map[string]map[string]int
+}
+
+// DisplayLogErrors prints the nodes whose logs matched error patterns.
+func DisplayLogErrors(counts map[string]map[string]int) {
+	var lines []string
+	for _, node := range nodeConfigs {
+		kinds := counts[node.Name]
+		if len(kinds) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(kinds))
+		for kind := range kinds {
+			names = append(names, kind)
+		}
+		sort.Strings(names)
+
+		parts := make([]string, len(names))
+		for i, kind := range names {
+			parts[i] = fmt.Sprintf("%s=%d", kind, kinds[kind])
+		}
+		lines = append(lines, fmt.Sprintf("   %-12s %s", node.DisplayName(), strings.Join(parts, " ")))
+	}
+
+	if len(lines) == 0 {
+		fmt.Println("\n📜 Client logs: no known error patterns")
+		return
+	}
+	fmt.Println("\n📜 Client log errors:")
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
@@ -2,145 +2,49 @@ package monitor
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"log/slog"
 	"math/big"
-	"os"
-	"os/exec"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"benchy/internal/state"
+
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/common"
 )
 
-// Network state persistence system
-type PersistentState struct {
-	Scenario1Executed         bool    `json:"scenario1_executed"`
-	Scenario2Executed         bool    `json:"scenario2_executed"`
-	Scenario3Executed         bool    `json:"scenario3_executed"`
-	AliceTransactionsSent     int     `json:"alice_transactions_sent"`
-	BobETHReceived           float64 `json:"bob_eth_received"`
-	CassandraTransactionsSent int     `json:"cassandra_transactions_sent"`
-	AliceHasRestarted        bool    `json:"alice_has_restarted"`
-}
-
-var stateFile = "benchy_state.json"
-
-func loadState() *PersistentState {
-	state := &PersistentState{}
-	
-	if data, err := ioutil.ReadFile(stateFile); err == nil {
-		json.Unmarshal(data, state)
+// loadState returns an empty state when the state file cannot be read, so
+// the displays degrade instead of failing.
+func loadState() *state.State {
+	current, err := state.Default().Load()
+	if err != nil {
+		slog.Warn("Failed to load persistent state", "error", err)
+		return &state.State{Version: state.SchemaVersion}
 	}
-	
-	return state
+	return current
 }
 
-func saveState(state *PersistentState) {
-	if data, err := json.MarshalIndent(state, "", "  "); err == nil {
-		ioutil.WriteFile(stateFile, data, 0644)
+// RecordEvents appends events to the persistent state.
+func RecordEvents(events ...state.Event) {
+	if err := state.Default().Append(events...); err != nil {
+		slog.Warn("Failed to save persistent state", "error", err)
 	}
 }
 
 func MarkScenarioExecuted(scenarioNumber int) {
-	state := loadState()
-	
-	switch scenarioNumber {
-	case 1:
-		if !state.Scenario1Executed {
-			state.Scenario1Executed = true
-			state.AliceTransactionsSent += 3
-			state.BobETHReceived += 0.3
-			fmt.Printf("🆕 Scenario 1 executed for the first time\n")
-		} else {
-			state.AliceTransactionsSent += 3
-			state.BobETHReceived += 0.3
-			fmt.Printf("🔄 Scenario 1 executed again (cumulative)\n")
-		}
-		
-	case 2:
-		if !state.Scenario2Executed {
-			state.Scenario2Executed = true
-			state.Scenario1Executed = true
-			state.CassandraTransactionsSent += 2
-			fmt.Printf("🆕 Scenario 2 executed for the first time\n")
-		} else {
-			state.CassandraTransactionsSent += 2
-			fmt.Printf("🔄 Scenario 2 executed again (cumulative)\n")
-		}
-		
-	case 3:
-		if !state.Scenario3Executed {
-			state.Scenario3Executed = true
-			state.Scenario2Executed = true
-			state.Scenario1Executed = true
-			state.CassandraTransactionsSent += 1
-			fmt.Printf("🆕 Scenario 3 executed for the first time\n")
-		} else {
-			state.CassandraTransactionsSent += 1
-			fmt.Printf("🔄 Scenario 3 executed again (cumulative)\n")
-		}
+	current := loadState()
+	if current.ScenarioCompleted(scenarioNumber) {
+		slog.Debug("Scenario executed again (cumulative)", "scenario", scenarioNumber)
+	} else {
+		slog.Debug("Scenario executed for the first time", "scenario", scenarioNumber)
 	}
-	
-	saveState(state)
-	
-	fmt.Printf("🔄 State updated: S1=%v, S2=%v, S3=%v\n", 
-		state.Scenario1Executed, state.Scenario2Executed, state.Scenario3Executed)
-	fmt.Printf("📊 Transaction history: Alice_tx=%d, Bob_ETH=%.1f, Cassandra_tx=%d\n", 
-		state.AliceTransactionsSent, state.BobETHReceived, state.CassandraTransactionsSent)
-	fmt.Printf("💾 State saved to %s\n", stateFile)
-}
 
-func MarkScenarioExecutedWithCount(scenarioNumber int, actualTransactions int) {
-	state := loadState()
-	
-	switch scenarioNumber {
-	case 1:
-		if !state.Scenario1Executed {
-			state.Scenario1Executed = true
-			fmt.Printf("🆕 Scenario 1 executed for the first time\n")
-		} else {
-			fmt.Printf("🔄 Scenario 1 executed again (cumulative)\n")
-		}
-		
-		state.AliceTransactionsSent += actualTransactions
-		state.BobETHReceived += float64(actualTransactions) * 0.1
-		
-	case 2:
-		if !state.Scenario2Executed {
-			state.Scenario2Executed = true
-			state.Scenario1Executed = true
-			state.CassandraTransactionsSent += 2
-			fmt.Printf("🆕 Scenario 2 executed for the first time\n")
-		} else {
-			state.CassandraTransactionsSent += 2
-			fmt.Printf("🔄 Scenario 2 executed again (cumulative)\n")
-		}
-		
-	case 3:
-		if !state.Scenario3Executed {
-			state.Scenario3Executed = true
-			state.Scenario2Executed = true
-			state.Scenario1Executed = true
-			state.CassandraTransactionsSent += 1
-			fmt.Printf("🆕 Scenario 3 executed for the first time\n")
-		} else {
-			state.CassandraTransactionsSent += 1
-			fmt.Printf("🔄 Scenario 3 executed again (cumulative)\n")
-		}
-	}
-	
-	saveState(state)
-	
-	fmt.Printf("🔄 State updated: S1=%v, S2=%v, S3=%v\n", 
-		state.Scenario1Executed, state.Scenario2Executed, state.Scenario3Executed)
-	fmt.Printf("📊 Actual transactions: Alice_tx=%d, Bob_ETH=%.1f, Cassandra_tx=%d\n", 
-		state.AliceTransactionsSent, state.BobETHReceived, state.CassandraTransactionsSent)
-	fmt.Printf("💾 State saved to %s\n", stateFile)
+	RecordEvents(state.ScenarioCompleted(scenarioNumber))
+
+	slog.Debug("Persistent state saved", "file", state.Default().Path(), "scenario", scenarioNumber,
+		"alice_tx", current.TxSent("alice"), "cassandra_tx", current.TxSent("cassandra"))
 }
 
 type NodeInfo struct {
@@ -159,93 +63,62 @@ type NodeInfo struct {
 }
 
 type NetworkMonitor struct {
-	nodes map[string]*NodeInfo
+	nodes      map[string]*NodeInfo
+	containers ContainerRuntime
 }
 
+// NewNetworkMonitor watches the nodes set by Configure.
 func NewNetworkMonitor() *NetworkMonitor {
-	nodes := map[string]*NodeInfo{
-		"alice": {
-			Name:     "Alice",
-			Client:   "Geth",
-			Endpoint: "http://localhost:8545",
-			Address:  "0x71562b71999873db5b286df957af199ec94617f7",
-		},
-		"bob": {
-			Name:     "Bob",
-			Client:   "Nethermind",
-			Endpoint: "http://localhost:8547",
-			Address:  "0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1",
-		},
-		"cassandra": {
-			Name:     "Cassandra",
-			Client:   "Geth",
-			Endpoint: "http://localhost:8549",
-			Address:  "0x71562b71999873db5b286df957af199ec94617f7",
-		},
-		"driss": {
-			Name:     "Driss",
-			Client:   "Nethermind",
-			Endpoint: "http://localhost:8551",
-			Address:  "0x9876543210fedcba9876543210fedcba98765431",
-		},
-		"elena": {
-			Name:     "Elena",
-			Client:   "Geth",
-			Endpoint: "http://localhost:8553",
-			Address:  "0x9876543210fedcba9876543210fedcba98765432",
-		},
-	}
-
-	return &NetworkMonitor{nodes: nodes}
+	return NewNetworkMonitorWithRuntime(dockerStats{})
 }
 
-func (nm *NetworkMonitor) getEndpoint(nodeName string) string {
-	endpoints := map[string]string{
-		"alice":     "http://localhost:8545",
-		"bob":       "http://localhost:8547",
-		"cassandra": "http://localhost:8549",
-		"driss":     "http://localhost:8551",
-		"elena":     "http://localhost:8553",
+// NewNetworkMonitorWithRuntime is NewNetworkMonitor reading container state
+// from containers instead of Docker.
+func NewNetworkMonitorWithRuntime(containers ContainerRuntime) *NetworkMonitor {
+	nodes := make(map[string]*NodeInfo, len(nodeConfigs))
+	for _, node := range nodeConfigs {
+		nodes[node.Name] = &NodeInfo{
+			Name:     node.DisplayName(),
+			Client:   node.Client,
+			Endpoint: node.Endpoint,
+			Address:  node.Address,
+		}
 	}
-	return endpoints[nodeName]
+
+	return &NetworkMonitor{nodes: nodes, containers: containers}
 }
 
-func (nm *NetworkMonitor) getRealBlockNumber(endpoint string) uint64 {
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`,
-		endpoint)
-	
-	output, err := cmd.Output()
+// dialNode connects to the RPC endpoint of nodeName. The caller closes the
+// client.
+func (nm *NetworkMonitor) dialNode(ctx context.Context, nodeName string) (*ethclient.Client, error) {
+	endpoint, exists := nodeEndpoints[nodeName]
+	if !exists {
+		return nil, fmt.Errorf("node %s not found", nodeName)
+	}
+	return ethclient.DialContext(ctx, endpoint)
+}
+
+func (nm *NetworkMonitor) getRealBlockNumber(nodeName string) uint64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	client, err := nm.dialNode(ctx, nodeName)
 	if err != nil {
 		return 0
 	}
+	defer client.Close()
 	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if result, ok := response["result"].(string); ok && len(result) > 2 {
-			if blockNum, err := strconv.ParseUint(result[2:], 16, 64); err == nil {
-				return blockNum
-			}
-		}
+	blockNum, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0
 	}
-	
-	return 0
+	return blockNum
 }
 
 func (nm *NetworkMonitor) getNetworkMaxBlock() uint64 {
 	maxBlock := uint64(0)
 	
-	endpoints := []string{
-		"http://localhost:8545", // Alice
-		"http://localhost:8547", // Bob  
-		"http://localhost:8549", // Cassandra
-		"http://localhost:8551", // Driss
-		"http://localhost:8553", // Elena
-	}
-	
-	for _, endpoint := range endpoints {
-		block := nm.getRealBlockNumber(endpoint)
+	for name := range nodeEndpoints {
+		block := nm.getRealBlockNumber(name)
 		if block > maxBlock {
 			maxBlock = block
 		}
@@ -275,16 +148,16 @@ func (nm *NetworkMonitor) getCurrentBlockNumber(nodeName string, isRunning bool)
 		return 0
 	}
 	
-	state := loadState()
+	current := loadState()
+	aliceRestarted := current.Restarted("alice")
 	
 	// Obtenir le vrai numéro de bloc via RPC
-	endpoint := nm.getEndpoint(nodeName)
-	realBlock := nm.getRealBlockNumber(endpoint)
+	realBlock := nm.getRealBlockNumber(nodeName)
 	
 	// Si le nœud répond correctement, utiliser la vraie valeur
 	if realBlock > 0 {
 		// Alice en retard après redémarrage
-		if nodeName == "alice" && state.AliceHasRestarted {
+		if nodeName == "alice" && aliceRestarted {
 			// Alice rattrape progressivement
 			maxBlock := nm.getNetworkMaxBlock()
 			if realBlock < maxBlock && maxBlock > 2 {
@@ -296,18 +169,18 @@ func (nm *NetworkMonitor) getCurrentBlockNumber(nodeName string, isRunning bool)
 	
 	// Fallback basé sur l'activité (pour audit sans vraies transactions)
 	baseBlocks := uint64(0)
-	if state.Scenario1Executed {
-		baseBlocks += uint64(state.AliceTransactionsSent) // Nombre réel de transactions
+	if current.ScenarioCompleted(1) {
+		baseBlocks += uint64(current.TxSent("alice")) // Nombre réel de transactions
 	}
-	if state.Scenario2Executed {
+	if current.ScenarioCompleted(2) {
 		baseBlocks += 2  
 	}
-	if state.Scenario3Executed {
+	if current.ScenarioCompleted(3) {
 		baseBlocks += 1
 	}
 	
 	// Alice en retard après panne
-	if nodeName == "alice" && state.AliceHasRestarted && baseBlocks > 2 {
+	if nodeName == "alice" && aliceRestarted && baseBlocks > 2 {
 		return baseBlocks - 2
 	}
 	
@@ -319,28 +192,21 @@ func (nm *NetworkMonitor) getMempoolTransactionCount(nodeName string, isRunning
 		return 0
 	}
 	
-	state := loadState()
+	current := loadState()
 	
 	// Pas de transactions dans le mempool si aucun scénario exécuté
-	if !state.Scenario1Executed && !state.Scenario2Executed && !state.Scenario3Executed {
+	if current.LastScenario() == 0 {
 		return 0
 	}
 	
 	now := time.Now().Unix()
 	cycle := now % 30
 	
-	baseTx := 0
-	if state.Scenario3Executed {
-		baseTx = 1
-	} else if state.Scenario2Executed {
-		baseTx = 1
-	} else if state.Scenario1Executed {
-		baseTx = 1
-	}
+	baseTx := 1
 	
 	switch nodeName {
 	case "alice":
-		if state.AliceHasRestarted {
+		if current.Restarted("alice") {
 			return 0
 		}
 		return baseTx + int(cycle%2)
@@ -362,120 +228,56 @@ func (nm *NetworkMonitor) getMempoolTransactionCount(nodeName string, isRunning
 	}
 }
 
-func (nm *NetworkMonitor) analyzeNetworkState() {
-	state := loadState()
-	
-	if state.Scenario3Executed {
-		return
-	}
-	
-	bobBalance := nm.getNodeBalance("http://localhost:8545", "0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1")
-	elenaBalance := nm.getNodeBalance("http://localhost:8549", "0x9876543210fedcba9876543210fedcba98765432")
-	
-	if bobBalance > 0.05 && !state.Scenario1Executed {
-		fmt.Println("🔍 Network analysis: Scenario 1 detected (Bob has ETH)")
-		state.Scenario1Executed = true
-		
-		if state.AliceTransactionsSent == 0 {
-			state.AliceTransactionsSent = int(bobBalance / 0.1) * 3
-		}
-		if state.BobETHReceived == 0.0 {
-			state.BobETHReceived = bobBalance
-		}
-		
-		saveState(state)
-	}
-	
-	if elenaBalance > 2.5 && !state.Scenario3Executed {
-		fmt.Println("🔍 Network analysis: Scenario 3 detected (Elena has > 2.5 ETH)")
-		state.Scenario3Executed = true
-		state.Scenario2Executed = true
-		state.Scenario1Executed = true
-		
-		if state.CassandraTransactionsSent < 3 {
-			state.CassandraTransactionsSent = 3
-		}
-		
-		saveState(state)
-	} else if elenaBalance > 1.5 && elenaBalance <= 2.5 && !state.Scenario2Executed {
-		fmt.Println("🔍 Network analysis: Scenario 2 detected (Elena has ~2 ETH from tokens)")
-		state.Scenario2Executed = true
-		state.Scenario1Executed = true
-		
-		if state.CassandraTransactionsSent < 2 {
-			state.CassandraTransactionsSent = 2
-		}
-		
-		saveState(state)
-	}
-}
-
 func (nm *NetworkMonitor) detectNodeRestart() {
-	state := loadState()
+	restarted := loadState().Restarted("alice")
 	
 	// Vérifier si Alice est vraiment en ligne via Docker
-	aliceOnline := nm.isNodeOnline("alice")
-	
-	if !aliceOnline {
+	if !nm.isNodeOnline("alice") {
 		// Alice est hors ligne
-		if !state.AliceHasRestarted {
-			state.AliceHasRestarted = true
-			saveState(state)
+		if !restarted {
+			recordRestart(state.NodeRestarted("alice"))
 		}
-	} else {
-		// Alice est en ligne - vérifier si elle a rattrapé
-		if state.AliceHasRestarted {
-			aliceBlock := nm.getRealBlockNumber("http://localhost:8545")
-			maxBlock := nm.getNetworkMaxBlock()
-			
-			// Si Alice a rattrapé (ou presque), marquer comme synchronisée
-			if aliceBlock >= maxBlock-1 || maxBlock == 0 {
-				state.AliceHasRestarted = false
-				saveState(state)
-			}
+		return
+	}
+	
+	// Alice est en ligne - vérifier si elle a rattrapé
+	if restarted {
+		aliceBlock := nm.getRealBlockNumber("alice")
+		maxBlock := nm.getNetworkMaxBlock()
+		
+		// Si Alice a rattrapé (ou presque), marquer comme synchronisée
+		if aliceBlock >= maxBlock-1 || maxBlock == 0 {
+			recordRestart(state.NodeSynced("alice"))
 		}
 	}
 }
 
-func (nm *NetworkMonitor) getNodeBalance(endpoint, address string) float64 {
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json", 
-		"--data", fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["%s","latest"],"id":1}`, address),
-		endpoint)
-	
-	output, err := cmd.Output()
+// recordRestart appends a restart or sync event unless the node is already
+// in that state: GetNodeInfo runs for every node at once and each of them
+// detects the same restart.
+func recordRestart(event state.Event) {
+	restarted := event.Type == state.EventNodeRestarted
+	err := state.Default().Update(func(current *state.State) error {
+		if current.Restarted(event.Node) != restarted {
+			current.Append(event)
+		}
+		return nil
+	})
 	if err != nil {
-		return 0.0
-	}
-	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if balance, ok := response["result"].(string); ok && len(balance) > 2 {
-			if balanceInt, err := strconv.ParseInt(balance[2:], 16, 64); err == nil {
-				return float64(balanceInt) / 1e18
-			}
-		}
+		slog.Warn("Failed to save persistent state", "error", err)
 	}
-	
-	return 0.0
 }
 
 func (nm *NetworkMonitor) hasScenario1BeenExecuted() bool {
-	nm.analyzeNetworkState()
-	state := loadState()
-	return state.Scenario1Executed
+	return loadState().ScenarioCompleted(1)
 }
 
 func (nm *NetworkMonitor) hasScenario2BeenExecuted() bool {
-	nm.analyzeNetworkState()
-	state := loadState()
-	return state.Scenario2Executed
+	return loadState().ScenarioCompleted(2)
 }
 
 func (nm *NetworkMonitor) hasScenario3BeenExecuted() bool {
-	nm.analyzeNetworkState()
-	state := loadState()
-	return state.Scenario3Executed
+	return loadState().ScenarioCompleted(3)
 }
 
 func (nm *NetworkMonitor) GetNodeInfo(nodeName string) (*NodeInfo, error) {
@@ -487,7 +289,7 @@ func (nm *NetworkMonitor) GetNodeInfo(nodeName string) (*NodeInfo, error) {
 	nm.detectNodeRestart()
 
 	containerName := fmt.Sprintf("benchy-%s", nodeName)
-	stats, err := GetContainerStats(containerName)
+	stats, err := nm.containers.ContainerStats(containerName)
 	
 	if err != nil || !stats.IsRunning || stats.MemoryUsage == "0B / 0B" {
 		node.IsRunning = false
@@ -520,7 +322,7 @@ func (nm *NetworkMonitor) GetNodeInfo(nodeName string) (*NodeInfo, error) {
 	node.BlockNumber = nm.getCurrentBlockNumber(nodeName, true)
 
 	if nodeName == "alice" {
-		node.TxCount = nm.getTransactionCount(node.Endpoint, node.Address)
+		node.TxCount = nm.getTransactionCount(nodeName, node.Address)
 	}
 
 	node.MempoolTxs = nm.getMempoolTransactionCount(nodeName, true)
@@ -529,12 +331,12 @@ func (nm *NetworkMonitor) GetNodeInfo(nodeName string) (*NodeInfo, error) {
 	
 	if node.Address == "0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1" {
 		if nm.isNodeOnline("alice") {
-			balanceEndpoint = "http://localhost:8545"
+			balanceEndpoint = nodeEndpoints["alice"]
 		}
 	} else if node.Address == "0x9876543210fedcba9876543210fedcba98765431" {
-		balanceEndpoint = "http://localhost:8549"
+		balanceEndpoint = nodeEndpoints["cassandra"]
 	} else if node.Address == "0x9876543210fedcba9876543210fedcba98765432" {
-		balanceEndpoint = "http://localhost:8549"
+		balanceEndpoint = nodeEndpoints["cassandra"]
 	} else {
 		balanceEndpoint = node.Endpoint
 	}
@@ -556,42 +358,33 @@ func (nm *NetworkMonitor) GetNodeInfo(nodeName string) (*NodeInfo, error) {
 
 func (nm *NetworkMonitor) isNodeOnline(nodeName string) bool {
 	containerName := fmt.Sprintf("benchy-%s", nodeName)
-	stats, err := GetContainerStats(containerName)
+	stats, err := nm.containers.ContainerStats(containerName)
 	return err == nil && stats.IsRunning
 }
 
-func (nm *NetworkMonitor) getTransactionCount(endpoint, address string) uint64 {
-	data := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getTransactionCount","params":["%s","latest"],"id":1}`, address)
-	
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", data,
-		endpoint)
-	
-	output, err := cmd.Output()
+func (nm *NetworkMonitor) getTransactionCount(nodeName, address string) uint64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	client, err := nm.dialNode(ctx, nodeName)
 	if err != nil {
 		return 0
 	}
+	defer client.Close()
 	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if result, ok := response["result"].(string); ok {
-			if txCount, err := strconv.ParseUint(result[2:], 16, 64); err == nil {
-				return txCount
-			}
-		}
+	txCount, err := client.NonceAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return 0
 	}
-	
-	return 0
+	return txCount
 }
 
 func (nm *NetworkMonitor) formatBalanceDisplay(name string, balance *big.Int, scenario2Executed, scenario3Executed bool) string {
-	state := loadState()
+	current := loadState()
 	
 	switch name {
 	case "alice":
-		if state.Scenario1Executed {
-			expectedBalance := 100.0 - (float64(state.AliceTransactionsSent) * 0.1)
+		if current.ScenarioCompleted(1) {
+			expectedBalance := 100.0 - (float64(current.TxSent("alice")) * 0.1)
 			if expectedBalance < 0 {
 				expectedBalance = 0
 			}
@@ -600,15 +393,15 @@ func (nm *NetworkMonitor) formatBalanceDisplay(name string, balance *big.Int, sc
 		return "100.0000 ETH"
 		
 	case "bob":
-		if state.Scenario1Executed && state.BobETHReceived > 0 {
-			expectedBalance := 100.0 + state.BobETHReceived
+		if bobReceived := state.WeiToETH(current.Received("bob")); current.ScenarioCompleted(1) && bobReceived > 0 {
+			expectedBalance := 100.0 + bobReceived
 			return fmt.Sprintf("%.4f ETH", expectedBalance)
 		}
 		return "100.0000 ETH"
 		
 	case "cassandra":
-		if state.Scenario2Executed || state.Scenario3Executed {
-			gasFees := float64(state.CassandraTransactionsSent) * 0.05
+		if current.ScenarioCompleted(2) || current.ScenarioCompleted(3) {
+			gasFees := float64(current.TxSent("cassandra")) * 0.05
 			expectedBalance := 100.0 - gasFees
 			if expectedBalance < 0 {
 				expectedBalance = 0
@@ -643,47 +436,45 @@ func (nm *NetworkMonitor) formatBalanceDisplay(name string, balance *big.Int, sc
 }
 
 func (nm *NetworkMonitor) calculateExpectedBalance(name string) string {
-	state := loadState()
+	current := loadState()
 	
 	switch name {
 	case "alice":
-		if state.Scenario1Executed {
-			expectedBalance := 100.0 - (float64(state.AliceTransactionsSent) * 0.1)
+		if current.ScenarioCompleted(1) {
+			expectedBalance := 100.0 - (float64(current.TxSent("alice")) * 0.1)
 			return fmt.Sprintf("%.4f ETH", expectedBalance)
 		}
 		return "100.0000 ETH"
 		
 	case "bob":
-		if state.Scenario1Executed && state.BobETHReceived > 0 {
-			expectedBalance := 100.0 + state.BobETHReceived
+		if bobReceived := state.WeiToETH(current.Received("bob")); current.ScenarioCompleted(1) && bobReceived > 0 {
+			expectedBalance := 100.0 + bobReceived
 			return fmt.Sprintf("%.4f ETH", expectedBalance)
 		}
 		return "100.0000 ETH"
 		
 	case "cassandra":
-		if state.Scenario2Executed || state.Scenario3Executed {
-			gasFees := float64(state.CassandraTransactionsSent) * 0.05
+		if current.ScenarioCompleted(2) || current.ScenarioCompleted(3) {
+			gasFees := float64(current.TxSent("cassandra")) * 0.05
 			expectedBalance := 100.0 - gasFees
 			return fmt.Sprintf("%.4f ETH", expectedBalance)
 		}
 		return "100.0000 ETH"
 		
 	case "driss":
-		if state.Scenario2Executed {
+		if current.ScenarioCompleted(2) {
 			return "1000 BY tokens"
 		}
 		return "0.0000 ETH"
 		
 	case "elena":
-		if state.Scenario3Executed {
-			scenario3Count := state.CassandraTransactionsSent - 2
-			if scenario3Count > 0 {
-				ethFromScenario3 := float64(scenario3Count) * 1.0
+		if current.ScenarioCompleted(3) {
+			if ethFromScenario3 := state.WeiToETH(current.Received("elena")); ethFromScenario3 > 0 {
 				return fmt.Sprintf("1000 BY tokens + %.1f ETH", ethFromScenario3)
 			} else {
 				return "1000 BY tokens"
 			}
-		} else if state.Scenario2Executed {
+		} else if current.ScenarioCompleted(2) {
 			return "1000 BY tokens"
 		}
 		return "0.0000 ETH"
@@ -693,30 +484,18 @@ func (nm *NetworkMonitor) calculateExpectedBalance(name string) string {
 	}
 }
 
-// Version optimisée avec parallélisation
-func (nm *NetworkMonitor) DisplayNetworkInfoFast() error {
-	nm.analyzeNetworkState()
-	
-	fmt.Println("📊 REAL Network Information:")
-	fmt.Println("=" + strings.Repeat("=", 140))
-	
-	fmt.Printf("%-12s %-11s %-8s %-8s %-6s %-6s %-15s %-42s %-18s %-10s\n", 
-		"Node", "Client", "Status", "Block", "Peers", "CPU%", "Memory", "Address", "Balance", "Mempool")
-	fmt.Println("-" + strings.Repeat("-", 140))
-
-	scenario2Executed := nm.hasScenario2BeenExecuted()
-	scenario3Executed := nm.hasScenario3BeenExecuted()
-
-	// Utiliser des goroutines pour obtenir les infos de tous les nœuds en parallèle
+// CollectNodeInfo queries every node in parallel. Nodes that could not be
+// queried are reported in the error map instead.
+func (nm *NetworkMonitor) CollectNodeInfo() (map[string]*NodeInfo, map[string]error) {
 	type nodeResult struct {
 		name string
 		info *NodeInfo
 		err  error
 	}
-	
+
 	results := make(chan nodeResult, len(nm.nodes))
 	var wg sync.WaitGroup
-	
+
 	for name := range nm.nodes {
 		wg.Add(1)
 		go func(nodeName string) {
@@ -725,27 +504,44 @@ func (nm *NetworkMonitor) DisplayNetworkInfoFast() error {
 			results <- nodeResult{nodeName, info, err}
 		}(name)
 	}
-	
-	// Attendre que toutes les goroutines se terminent
+
 	go func() {
 		wg.Wait()
 		close(results)
 	}()
-	
-	// Collecter les résultats
+
 	nodeInfos := make(map[string]*NodeInfo)
+	nodeErrors := make(map[string]error)
 	for result := range results {
 		if result.err != nil {
-			fmt.Printf("%-12s %-11s ❌ ERROR - %v\n", result.name, "", result.err)
+			nodeErrors[result.name] = result.err
 			continue
 		}
 		nodeInfos[result.name] = result.info
 	}
+
+	return nodeInfos, nodeErrors
+}
+
+// Version optimisée avec parallélisation
+func (nm *NetworkMonitor) DisplayNetworkInfoFast() error {
+	fmt.Println("📊 REAL Network Information:")
+	fmt.Println("=" + strings.Repeat("=", 140))
 	
-	// Afficher dans l'ordre préféré
-	nodeOrder := []string{"alice", "bob", "cassandra", "driss", "elena"}
+	fmt.Printf("%-12s %-11s %-8s %-8s %-6s %-6s %-15s %-42s %-18s %-10s\n", 
+		"Node", "Client", "Status", "Block", "Peers", "CPU%", "Memory", "Address", "Balance", "Mempool")
+	fmt.Println("-" + strings.Repeat("-", 140))
+
+	scenario2Executed := nm.hasScenario2BeenExecuted()
+	scenario3Executed := nm.hasScenario3BeenExecuted()
+
+	nodeInfos, nodeErrors := nm.CollectNodeInfo()
+	for name, err := range nodeErrors {
+		fmt.Printf("%-12s %-11s ❌ ERROR - %v\n", name, "", err)
+	}
 	
-	for _, name := range nodeOrder {
+	// Afficher dans l'ordre préféré
+	for _, name := range NodeOrder {
 		info, exists := nodeInfos[name]
 		if !exists {
 			continue
@@ -783,10 +579,10 @@ func (nm *NetworkMonitor) DisplayNetworkInfoFast() error {
 	fmt.Println("=" + strings.Repeat("=", 140))
 	fmt.Println("🔗 Consensus: Clique PoA | Network ID: 1337 | Validators: Alice, Bob, Cassandra")
 	
-	state := loadState()
-	if state.Scenario1Executed || state.Scenario2Executed || state.Scenario3Executed {
+	current := loadState()
+	if current.LastScenario() > 0 {
 		fmt.Printf("💾 Persistent state: Alice_tx=%d, Bob_ETH=%.1f, Cassandra_tx=%d (file: %s)\n", 
-			state.AliceTransactionsSent, state.BobETHReceived, state.CassandraTransactionsSent, stateFile)
+			current.TxSent("alice"), state.WeiToETH(current.Received("bob")), current.TxSent("cassandra"), state.Default().Path())
 	}
 	
 	return nil
@@ -798,22 +594,23 @@ func (nm *NetworkMonitor) DisplayNetworkInfo() error {
 }
 
 func ResetPersistentState() error {
-	if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove state file: %v", err)
+	if err := state.Default().Reset(); err != nil {
+		return err
 	}
 	
-	fmt.Printf("🧹 Persistent state reset (file %s removed)\n", stateFile)
+	slog.Info("🧹 Persistent state reset", "file", state.Default().Path())
 	return nil
 }
 
 func ShowPersistentState() {
-	state := loadState()
+	current := loadState()
 	fmt.Printf("📊 Current persistent state:\n")
 	fmt.Printf("   Scenarios: S1=%v, S2=%v, S3=%v\n", 
-		state.Scenario1Executed, state.Scenario2Executed, state.Scenario3Executed)
+		current.ScenarioCompleted(1), current.ScenarioCompleted(2), current.ScenarioCompleted(3))
 	fmt.Printf("   Transactions: Alice=%d, Cassandra=%d\n", 
-		state.AliceTransactionsSent, state.CassandraTransactionsSent)
-	fmt.Printf("   Bob ETH received: %.1f\n", state.BobETHReceived)
-	fmt.Printf("   Alice restarted: %v\n", state.AliceHasRestarted)
-	fmt.Printf("   File: %s\n", stateFile)
-}
\ No newline at end of file
+		current.TxSent("alice"), current.TxSent("cassandra"))
+	fmt.Printf("   Bob ETH received: %.1f\n", state.WeiToETH(current.Received("bob")))
+	fmt.Printf("   Alice restarted: %v\n", current.Restarted("alice"))
+	fmt.Printf("   Events: %d (schema v%d)\n", len(current.Events), current.Version)
+	fmt.Printf("   File: %s\n", state.Default().Path())
+}
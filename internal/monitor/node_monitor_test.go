@@ -0,0 +1,157 @@
+package monitor_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"benchy/internal/clique"
+	"benchy/internal/config"
+	"benchy/internal/fake"
+	"benchy/internal/monitor"
+)
+
+// newTestNetwork fakes the default nodes and points the monitors at them.
+// The persistent state is written to a temporary working directory.
+func newTestNetwork(t *testing.T, cfg *config.Config) *fake.Network {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	network, err := fake.NewNetwork(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(network.Close)
+	monitor.Configure(network.Config)
+	t.Cleanup(func() { monitor.Configure(config.Default()) })
+	return network
+}
+
+// stopNode stops the container of a node, taking the fake node offline.
+func stopNode(t *testing.T, network *fake.Network, name string) {
+	t.Helper()
+	err := network.Runtime.Compose(context.Background(), "", []string{"stop", name}, nil, io.Discard, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollectNodeInfo(t *testing.T) {
+	network := newTestNetwork(t, config.Default())
+	network.Chain.Mine(3)
+	nm := monitor.NewNetworkMonitorWithRuntime(network.Runtime)
+
+	infos, errs := nm.CollectNodeInfo()
+	if len(errs) > 0 {
+		t.Fatalf("got errors %v", errs)
+	}
+	head := network.Chain.BlockNumber()
+	for _, name := range network.Config.NodeNames() {
+		info := infos[name]
+		if info == nil || !info.IsRunning {
+			t.Fatalf("%s: got %+v, want it running", name, info)
+		}
+		if info.BlockNumber != head || info.PeerCount != 4 {
+			t.Errorf("%s: got block #%d and %d peers, want #%d and 4", name, info.BlockNumber, info.PeerCount, head)
+		}
+		if info.CPUUsage != 1.5 {
+			t.Errorf("%s: got %.1f%% CPU, want the runtime's stats", name, info.CPUUsage)
+		}
+	}
+	if balance := infos["alice"].Balance; balance == nil || balance.Cmp(fake.AccountBalance) != 0 {
+		t.Errorf("got alice's balance %v, want %v", balance, fake.AccountBalance)
+	}
+
+	stopNode(t, network, "bob")
+	infos, _ = nm.CollectNodeInfo()
+	if infos["bob"].IsRunning || infos["bob"].BlockNumber != 0 {
+		t.Errorf("got bob %+v, want it stopped", infos["bob"])
+	}
+	if peers := infos["alice"].PeerCount; peers != 3 {
+		t.Errorf("got %d peers for alice, want 3 once bob stopped", peers)
+	}
+}
+
+func TestNodeEndpoint(t *testing.T) {
+	network := newTestNetwork(t, config.Default())
+
+	endpoint, ok := monitor.NodeEndpoint("cassandra")
+	if !ok || endpoint != network.Nodes["cassandra"].URL {
+		t.Errorf("got %q, want the fake node %q", endpoint, network.Nodes["cassandra"].URL)
+	}
+	if _, ok := monitor.NodeEndpoint("frank"); ok {
+		t.Error("an unknown node has an endpoint")
+	}
+}
+
+// TestTrackBlockPropagation polls the nodes over HTTP, whose first poll
+// reports the head the network had before tracking started.
+func TestTrackBlockPropagation(t *testing.T) {
+	network := newTestNetwork(t, config.Default())
+	for i := range network.Config.Nodes {
+		network.Config.Nodes[i].WSURL = ""
+	}
+	monitor.Configure(network.Config)
+	network.Chain.Mine(2)
+	nm := monitor.NewNetworkMonitorWithRuntime(network.Runtime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	bp := nm.TrackBlockPropagation(ctx)
+	defer bp.Stop()
+
+	const mined = 3
+	for i := 0; i < mined; i++ {
+		time.Sleep(300 * time.Millisecond)
+		network.Chain.Mine(1)
+	}
+	for !everyNodeSaw(bp.Report(), mined) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("got report %+v, want every node to see %d blocks", bp.Report(), mined)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	bp.Stop()
+
+	report := bp.Report()
+	// The fake blocks carry no Clique seal, so no node is their sealer
+	if report.Blocks != mined || report.Unattributed != mined {
+		t.Errorf("got %d blocks, %d unattributed, want %d of each", report.Blocks, report.Unattributed, mined)
+	}
+	if len(report.Nodes) != len(network.Config.Nodes) || len(report.Clients) != 2 {
+		t.Errorf("got %d nodes and %d clients", len(report.Nodes), len(report.Clients))
+	}
+}
+
+func TestValidatorsNotClique(t *testing.T) {
+	network := newTestNetwork(t, config.Default())
+	if client, err := monitor.DialValidator(); err != nil {
+		t.Fatal(err)
+	} else {
+		client.Close()
+	}
+
+	// The nodes answer like geth --dev
+	network.Chain.SetClique(false)
+	if _, err := monitor.DialValidator(); !errors.Is(err, clique.ErrNotClique) {
+		t.Errorf("got %v dialing a validator, want ErrNotClique", err)
+	}
+	nm := monitor.NewNetworkMonitorWithRuntime(network.Runtime)
+	if _, err := nm.AnalyzeValidators(10); !errors.Is(err, clique.ErrNotClique) {
+		t.Errorf("got %v analyzing the validators, want ErrNotClique", err)
+	}
+}
+
+func everyNodeSaw(report monitor.BlockPropagationReport, blocks int) bool {
+	if report.Blocks < blocks || len(report.Nodes) == 0 {
+		return false
+	}
+	for _, node := range report.Nodes {
+		if node.Delays.Samples < blocks {
+			return false
+		}
+	}
+	return true
+}
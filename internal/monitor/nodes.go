@@ -0,0 +1,41 @@
+package monitor
+
+import "benchy/internal/config"
+
+// Node lookups shared by the monitors, set from the configuration.
+var (
+	nodeConfigs   []config.Node
+	nodeEndpoints map[string]string
+	// WebSocket endpoints; nodes missing here are polled over HTTP
+	wsEndpoints map[string]string
+	genesisFile string
+
+	// NodeOrder is the display order used by the live views.
+	NodeOrder []string
+	// ValidatorNodes lists the nodes expected to seal blocks.
+	ValidatorNodes []string
+)
+
+func init() {
+	Configure(config.Default())
+}
+
+// Configure points the package at the nodes and genesis of cfg. Call it
+// before creating monitors.
+func Configure(cfg *config.Config) {
+	nodeConfigs = cfg.Nodes
+	nodeEndpoints = cfg.Endpoints()
+	wsEndpoints = make(map[string]string)
+	genesisFile = cfg.Genesis
+	NodeOrder = cfg.NodeNames()
+	ValidatorNodes = nil
+
+	for _, node := range cfg.Nodes {
+		if node.WSURL != "" {
+			wsEndpoints[node.Name] = node.WSURL
+		}
+		if node.Validator {
+			ValidatorNodes = append(ValidatorNodes, node.Name)
+		}
+	}
+}
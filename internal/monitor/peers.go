@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// WaitForNode polls endpoint until it answers eth_blockNumber or ctx ends.
+func WaitForNode(ctx context.Context, endpoint string) error {
+	client, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for {
+		reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		_, err := client.BlockNumber(reqCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s did not answer: %v", endpoint, err)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// ContainerEnode returns the enode URL of the node at endpoint, addressed by
+// container name so other containers on the compose network can dial it.
+func ContainerEnode(ctx context.Context, endpoint, container string) (string, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	var info struct {
+		Enode string `json:"enode"`
+	}
+	if err := client.CallContext(ctx, &info, "admin_nodeInfo"); err != nil {
+		return "", fmt.Errorf("admin_nodeInfo failed: %v", err)
+	}
+
+	parsed, err := url.Parse(info.Enode)
+	if err != nil {
+		return "", fmt.Errorf("invalid enode %q: %v", info.Enode, err)
+	}
+	parsed.Host = fmt.Sprintf("%s:%s", container, parsed.Port())
+	return parsed.String(), nil
+}
+
+// AddPeer asks the node at endpoint to connect to enode.
+func AddPeer(ctx context.Context, endpoint, enode string) error {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var added bool
+	if err := client.CallContext(ctx, &added, "admin_addPeer", enode); err != nil {
+		return fmt.Errorf("admin_addPeer failed: %v", err)
+	}
+	if !added {
+		return fmt.Errorf("peer %s was rejected", enode)
+	}
+	return nil
+}
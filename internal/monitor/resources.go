@@ -0,0 +1,342 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+const resourceHistorySize = 720 // one hour at the default 5s interval
+
+// ResourceSample is one reading of a container's resource usage. Network,
+// block I/O and throttling values are cumulative counters.
+type ResourceSample struct {
+	Time             time.Time
+	CPUPercent       float64
+	MemoryUsageBytes uint64
+	MemoryLimitBytes uint64
+	NetRxBytes       uint64
+	NetTxBytes       uint64
+	BlockReadBytes   uint64
+	BlockWriteBytes  uint64
+	PIDs             uint64
+	ThrottledPeriods uint64
+	ThrottledTimeNs  uint64
+}
+
+// MetricSummary aggregates one metric over the sampled window.
+type MetricSummary struct {
+	Min float64 `json:"min" yaml:"min"`
+	Avg float64 `json:"avg" yaml:"avg"`
+	Max float64 `json:"max" yaml:"max"`
+	P95 float64 `json:"p95" yaml:"p95"`
+}
+
+// ResourceSummary describes a container over the run. Rates are per second
+// between consecutive samples.
+type ResourceSummary struct {
+	Container        string        `json:"container" yaml:"container"`
+	Samples          int           `json:"samples" yaml:"samples"`
+	CPUPercent       MetricSummary `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryBytes      MetricSummary `json:"memory_bytes" yaml:"memory_bytes"`
+	MemoryLimitBytes uint64        `json:"memory_limit_bytes" yaml:"memory_limit_bytes"`
+	NetRxRate        MetricSummary `json:"net_rx_bytes_per_sec" yaml:"net_rx_bytes_per_sec"`
+	NetTxRate        MetricSummary `json:"net_tx_bytes_per_sec" yaml:"net_tx_bytes_per_sec"`
+	BlockReadRate    MetricSummary `json:"block_read_bytes_per_sec" yaml:"block_read_bytes_per_sec"`
+	BlockWriteRate   MetricSummary `json:"block_write_bytes_per_sec" yaml:"block_write_bytes_per_sec"`
+	PIDs             MetricSummary `json:"pids" yaml:"pids"`
+	ThrottledPeriods uint64        `json:"throttled_periods" yaml:"throttled_periods"`
+	ThrottledTimeNs  uint64        `json:"throttled_time_ns" yaml:"throttled_time_ns"`
+}
+
+// sampleRing is a fixed size history of samples, oldest first.
+type sampleRing struct {
+	samples []ResourceSample
+	next    int
+	full    bool
+}
+
+func newSampleRing(size int) *sampleRing {
+	return &sampleRing{samples: make([]ResourceSample, size)}
+}
+
+func (r *sampleRing) add(sample ResourceSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *sampleRing) ordered() []ResourceSample {
+	if !r.full {
+		return append([]ResourceSample(nil), r.samples[:r.next]...)
+	}
+	return append(append([]ResourceSample(nil), r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// ResourceSampler polls the Docker Engine API for every benchy container and
+// keeps a bounded history per container.
+type ResourceSampler struct {
+	docker     *client.Client
+	containers []string
+	mu         sync.RWMutex
+	history    map[string]*sampleRing
+}
+
+func NewResourceSampler(containers []string) (*ResourceSampler, error) {
+	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %v", err)
+	}
+
+	history := make(map[string]*sampleRing)
+	for _, name := range containers {
+		history[name] = newSampleRing(resourceHistorySize)
+	}
+	return &ResourceSampler{docker: docker, containers: containers, history: history}, nil
+}
+
+func (rs *ResourceSampler) Close() error {
+	return rs.docker.Close()
+}
+
+// Start samples every container each interval until ctx is cancelled.
+func (rs *ResourceSampler) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		defer rs.Close()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			rs.SampleOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SampleOnce reads all containers in parallel. Stopped containers are skipped.
+func (rs *ResourceSampler) SampleOnce(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, name := range rs.containers {
+		wg.Add(1)
+		go func(containerName string) {
+			defer wg.Done()
+			sample, err := rs.sample(ctx, containerName)
+			if err != nil {
+				return
+			}
+			rs.mu.Lock()
+			rs.history[containerName].add(sample)
+			rs.mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+}
+
+func (rs *ResourceSampler) sample(ctx context.Context, containerName string) (ResourceSample, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	response, err := rs.docker.ContainerStats(reqCtx, containerName, false)
+	if err != nil {
+		return ResourceSample{}, err
+	}
+	defer response.Body.Close()
+
+	var stats types.StatsJSON
+	if err := json.NewDecoder(response.Body).Decode(&stats); err != nil {
+		return ResourceSample{}, err
+	}
+	if stats.PidsStats.Current == 0 && stats.MemoryStats.Usage == 0 {
+		return ResourceSample{}, fmt.Errorf("container %s is not running", containerName)
+	}
+
+	return sampleFromStats(&stats), nil
+}
+
+func sampleFromStats(stats *types.StatsJSON) ResourceSample {
+	sample := ResourceSample{
+		Time:             stats.Read,
+		CPUPercent:       cpuPercent(stats),
+		MemoryUsageBytes: memoryUsage(stats),
+		MemoryLimitBytes: stats.MemoryStats.Limit,
+		PIDs:             stats.PidsStats.Current,
+		ThrottledPeriods: stats.CPUStats.ThrottlingData.ThrottledPeriods,
+		ThrottledTimeNs:  stats.CPUStats.ThrottlingData.ThrottledTime,
+	}
+	for _, network := range stats.Networks {
+		sample.NetRxBytes += network.RxBytes
+		sample.NetTxBytes += network.TxBytes
+	}
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			sample.BlockReadBytes += entry.Value
+		case "write":
+			sample.BlockWriteBytes += entry.Value
+		}
+	}
+	return sample
+}
+
+// cpuPercent follows the docker CLI computation over the pre-read sample.
+func cpuPercent(stats *types.StatsJSON) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage excludes the page cache like `docker stats` does.
+func memoryUsage(stats *types.StatsJSON) uint64 {
+	usage := stats.MemoryStats.Usage
+	cache := stats.MemoryStats.Stats["inactive_file"] // cgroup v2
+	if cache == 0 {
+		cache = stats.MemoryStats.Stats["total_inactive_file"] // cgroup v1
+	}
+	if cache < usage {
+		return usage - cache
+	}
+	return usage
+}
+
+// Latest returns the most recent sample of a container.
+func (rs *ResourceSampler) Latest(containerName string) (ResourceSample, bool) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	ring, ok := rs.history[containerName]
+	if !ok {
+		return ResourceSample{}, false
+	}
+	samples := ring.ordered()
+	if len(samples) == 0 {
+		return ResourceSample{}, false
+	}
+	return samples[len(samples)-1], true
+}
+
+// Summaries aggregates the history of every container that has samples.
+func (rs *ResourceSampler) Summaries() []ResourceSummary {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	var result []ResourceSummary
+	for _, name := range rs.containers {
+		samples := rs.history[name].ordered()
+		if len(samples) == 0 {
+			continue
+		}
+		result = append(result, summarizeSamples(name, samples))
+	}
+	return result
+}
+
+func summarizeSamples(containerName string, samples []ResourceSample) ResourceSummary {
+	var cpu, memory, pids []float64
+	var rx, tx, reads, writes []float64
+
+	for i, sample := range samples {
+		cpu = append(cpu, sample.CPUPercent)
+		memory = append(memory, float64(sample.MemoryUsageBytes))
+		pids = append(pids, float64(sample.PIDs))
+
+		if i == 0 {
+			continue
+		}
+		previous := samples[i-1]
+		elapsed := sample.Time.Sub(previous.Time).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+		rx = append(rx, counterRate(previous.NetRxBytes, sample.NetRxBytes, elapsed))
+		tx = append(tx, counterRate(previous.NetTxBytes, sample.NetTxBytes, elapsed))
+		reads = append(reads, counterRate(previous.BlockReadBytes, sample.BlockReadBytes, elapsed))
+		writes = append(writes, counterRate(previous.BlockWriteBytes, sample.BlockWriteBytes, elapsed))
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	return ResourceSummary{
+		Container:        containerName,
+		Samples:          len(samples),
+		CPUPercent:       summarize(cpu),
+		MemoryBytes:      summarize(memory),
+		MemoryLimitBytes: last.MemoryLimitBytes,
+		NetRxRate:        summarize(rx),
+		NetTxRate:        summarize(tx),
+		BlockReadRate:    summarize(reads),
+		BlockWriteRate:   summarize(writes),
+		PIDs:             summarize(pids),
+		ThrottledPeriods: last.ThrottledPeriods - min(first.ThrottledPeriods, last.ThrottledPeriods),
+		ThrottledTimeNs:  last.ThrottledTimeNs - min(first.ThrottledTimeNs, last.ThrottledTimeNs),
+	}
+}
+
+// counterRate tolerates counter resets caused by container restarts.
+func counterRate(previous, current uint64, seconds float64) float64 {
+	if current < previous {
+		return float64(current) / seconds
+	}
+	return float64(current-previous) / seconds
+}
+
+func summarize(values []float64) MetricSummary {
+	if len(values) == 0 {
+		return MetricSummary{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, v := range sorted {
+		total += v
+	}
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return MetricSummary{
+		Min: sorted[0],
+		Avg: total / float64(len(sorted)),
+		Max: sorted[len(sorted)-1],
+		P95: sorted[rank],
+	}
+}
+
+// DisplayResourceSummaries prints min/avg/max/p95 per container.
+func DisplayResourceSummaries(summaries []ResourceSummary) {
+	if len(summaries) == 0 {
+		return
+	}
+	fmt.Println("\n📈 Container resources over the run (min / avg / max / p95):")
+	fmt.Printf("%-18s %-7s %-30s %-34s %-22s %-22s %-10s\n",
+		"Container", "Samples", "CPU%", "Memory MiB", "Net RX/TX KiB/s", "Disk R/W KiB/s", "Throttled")
+	fmt.Println("-" + strings.Repeat("-", 150))
+	for _, s := range summaries {
+		fmt.Printf("%-18s %-7d %-30s %-34s %-22s %-22s %-10s\n",
+			s.Container,
+			s.Samples,
+			fmt.Sprintf("%.1f / %.1f / %.1f / %.1f", s.CPUPercent.Min, s.CPUPercent.Avg, s.CPUPercent.Max, s.CPUPercent.P95),
+			fmt.Sprintf("%.0f / %.0f / %.0f / %.0f", s.MemoryBytes.Min/(1<<20), s.MemoryBytes.Avg/(1<<20), s.MemoryBytes.Max/(1<<20), s.MemoryBytes.P95/(1<<20)),
+			fmt.Sprintf("%.1f / %.1f", s.NetRxRate.Avg/1024, s.NetTxRate.Avg/1024),
+			fmt.Sprintf("%.1f / %.1f", s.BlockReadRate.Avg/1024, s.BlockWriteRate.Avg/1024),
+			time.Duration(s.ThrottledTimeNs).Round(time.Millisecond).String(),
+		)
+	}
+}
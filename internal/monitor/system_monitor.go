@@ -1,13 +1,15 @@
 package monitor
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type ContainerStats struct {
@@ -15,6 +17,44 @@ type ContainerStats struct {
 	MemoryUsage string
 	MemoryLimit string
 	IsRunning   bool
+
+	// Parsed values, in bytes where applicable
+	MemoryUsageBytes uint64
+	MemoryLimitBytes uint64
+	NetRxBytes       uint64
+	NetTxBytes       uint64
+	BlockReadBytes   uint64
+	BlockWriteBytes  uint64
+	PIDs             uint64
+}
+
+// statsFormat is the docker stats template shared by the bulk and individual
+// readers; parseStatsColumns expects the same column order.
+const statsFormat = "{{.CPUPerc}}\t{{.MemUsage}}\t{{.NetIO}}\t{{.BlockIO}}\t{{.PIDs}}"
+
+func parseStatsColumns(parts []string) *ContainerStats {
+	cpuStr := strings.TrimSuffix(parts[0], "%")
+	cpu, _ := strconv.ParseFloat(cpuStr, 64)
+
+	stats := &ContainerStats{
+		CPUUsage:    cpu,
+		MemoryUsage: parts[1],
+		IsRunning:   true,
+	}
+	stats.MemoryUsageBytes, stats.MemoryLimitBytes = parseMemoryUsage(parts[1])
+	if memoryParts := strings.SplitN(parts[1], "/", 2); len(memoryParts) == 2 {
+		stats.MemoryLimit = strings.TrimSpace(memoryParts[1])
+	}
+	if len(parts) > 2 {
+		stats.NetRxBytes, stats.NetTxBytes = parseMemoryUsage(parts[2])
+	}
+	if len(parts) > 3 {
+		stats.BlockReadBytes, stats.BlockWriteBytes = parseMemoryUsage(parts[3])
+	}
+	if len(parts) > 4 {
+		stats.PIDs, _ = strconv.ParseUint(strings.TrimSpace(parts[4]), 10, 64)
+	}
+	return stats
 }
 
 // Cache global pour éviter les appels répétés
@@ -51,7 +91,7 @@ func getAllContainerStats() (map[string]*ContainerStats, error) {
 	
 	// Obtenir les stats de tous les conteneurs benchy en une commande
 	cmd := exec.Command("docker", "stats", "--no-stream", "--format",
-		"{{.Name}}\t{{.CPUPerc}}\t{{.MemUsage}}", 
+		"{{.Name}}\t"+statsFormat, 
 		"benchy-alice", "benchy-bob", "benchy-cassandra", "benchy-driss", "benchy-elena")
 	
 	output, err := cmd.Output()
@@ -74,15 +114,7 @@ func getAllContainerStats() (map[string]*ContainerStats, error) {
 		}
 		
 		containerName := parts[0]
-		cpuStr := strings.TrimSuffix(parts[1], "%")
-		cpu, _ := strconv.ParseFloat(cpuStr, 64)
-		memoryUsage := parts[2]
-		
-		result[containerName] = &ContainerStats{
-			CPUUsage:    cpu,
-			MemoryUsage: memoryUsage,
-			IsRunning:   true,
-		}
+		result[containerName] = parseStatsColumns(parts[1:])
 	}
 	
 	// Mettre à jour le cache
@@ -107,7 +139,7 @@ func getIndividualStats() map[string]*ContainerStats {
 			defer wg.Done()
 			
 			cmd := exec.Command("docker", "stats", "--no-stream", "--format",
-				"{{.CPUPerc}}\t{{.MemUsage}}", containerName)
+				statsFormat, containerName)
 			
 			output, err := cmd.Output()
 			if err != nil {
@@ -125,16 +157,8 @@ func getIndividualStats() map[string]*ContainerStats {
 				return
 			}
 			
-			cpuStr := strings.TrimSuffix(parts[0], "%")
-			cpu, _ := strconv.ParseFloat(cpuStr, 64)
-			memoryUsage := parts[1]
-			
 			mu.Lock()
-			result[containerName] = &ContainerStats{
-				CPUUsage:    cpu,
-				MemoryUsage: memoryUsage,
-				IsRunning:   true,
-			}
+			result[containerName] = parseStatsColumns(parts)
 			mu.Unlock()
 		}(container)
 	}
@@ -143,6 +167,19 @@ func getIndividualStats() map[string]*ContainerStats {
 	return result
 }
 
+// ContainerRuntime reports the state of node containers to a
+// NetworkMonitor. The default asks the docker CLI; internal/fake provides
+// one without Docker.
+type ContainerRuntime interface {
+	ContainerStats(containerName string) (*ContainerStats, error)
+}
+
+type dockerStats struct{}
+
+func (dockerStats) ContainerStats(containerName string) (*ContainerStats, error) {
+	return GetContainerStats(containerName)
+}
+
 func GetContainerStats(containerName string) (*ContainerStats, error) {
 	// Vérifier d'abord si le conteneur existe vraiment (sans cache)
 	if !isContainerRunning(containerName) {
@@ -167,6 +204,57 @@ func GetContainerStats(containerName string) (*ContainerStats, error) {
 	return &ContainerStats{IsRunning: false}, nil
 }
 
+// parseMemoryUsage splits a docker stats value such as "123.4MiB / 7.7GiB"
+// into usage and limit in bytes.
+func parseMemoryUsage(value string) (uint64, uint64) {
+	parts := strings.Split(value, "/")
+	usage := parseByteSize(parts[0])
+	var limit uint64
+	if len(parts) > 1 {
+		limit = parseByteSize(parts[1])
+	}
+	return usage, limit
+}
+
+// parseByteSize converts docker's human readable sizes (B, kB, MiB, GB...)
+// to bytes. Unparseable values yield 0.
+func parseByteSize(value string) uint64 {
+	value = strings.TrimSpace(value)
+	units := []struct {
+		suffix     string
+		multiplier float64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+		{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+		{"B", 1},
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			number, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, unit.suffix)), 64)
+			if err != nil {
+				return 0
+			}
+			return uint64(number * unit.multiplier)
+		}
+	}
+	return 0
+}
+
+// formatByteSize renders bytes with binary units, like docker stats.
+func formatByteSize(bytes uint64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(bytes)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	return fmt.Sprintf("%.2f%s", value, units[unit])
+}
+
 // Optimiser aussi cette fonction avec goroutines
 func GetDetailedNodeInfo(nodeName string) (map[string]interface{}, error) {
 	node, exists := nodeEndpoints[nodeName]
@@ -192,27 +280,17 @@ func GetDetailedNodeInfo(nodeName string) (map[string]interface{}, error) {
 	
 	// Goroutine pour obtenir le numéro de bloc
 	go func() {
-		cmd := exec.Command("curl", "-s", "-X", "POST",
-			"-H", "Content-Type: application/json",
-			"--data", `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`,
-			node)
-		
-		output, err := cmd.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		client, err := ethclient.DialContext(ctx, node)
 		if err != nil {
 			blockChan <- blockResult{0, err}
 			return
 		}
+		defer client.Close()
 		
-		var response map[string]interface{}
-		if json.Unmarshal(output, &response) == nil {
-			if blockHex, ok := response["result"].(string); ok {
-				if blockNum, err := strconv.ParseInt(blockHex[2:], 16, 64); err == nil {
-					blockChan <- blockResult{blockNum, nil}
-					return
-				}
-			}
-		}
-		blockChan <- blockResult{0, fmt.Errorf("failed to parse block")}
+		blockNum, err := client.BlockNumber(ctx)
+		blockChan <- blockResult{int64(blockNum), err}
 	}()
 	
 	// Goroutine pour obtenir les stats du conteneur
@@ -243,10 +321,8 @@ func GetDetailedNodeInfo(nodeName string) (map[string]interface{}, error) {
 	return result, nil
 }
 
-var nodeEndpoints = map[string]string{
-	"alice":     "http://localhost:8545",
-	"bob":       "http://localhost:8547",
-	"cassandra": "http://localhost:8549",
-	"driss":     "http://localhost:8551",
-	"elena":     "http://localhost:8553",
-}
\ No newline at end of file
+// NodeEndpoint returns the JSON-RPC endpoint of a node by name.
+func NodeEndpoint(nodeName string) (string, bool) {
+	endpoint, exists := nodeEndpoints[nodeName]
+	return endpoint, exists
+}
@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PendingTx is a transaction waiting in a node's txpool.
+type PendingTx struct {
+	Hash     common.Hash
+	From     common.Address
+	To       *common.Address
+	Nonce    uint64
+	Value    *big.Int
+	GasPrice *big.Int
+	Queued   bool
+}
+
+type rpcPoolTx struct {
+	Hash         common.Hash     `json:"hash"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	Value        *hexutil.Big    `json:"value"`
+	GasPrice     *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas *hexutil.Big    `json:"maxFeePerGas"`
+}
+
+// GetTxPoolContent lists pending and queued transactions of a node, ordered
+// by sender and nonce.
+func GetTxPoolContent(ctx context.Context, nodeName string) ([]PendingTx, error) {
+	endpoint, exists := nodeEndpoints[nodeName]
+	if !exists {
+		return nil, fmt.Errorf("node %s not found", nodeName)
+	}
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %v", nodeName, err)
+	}
+	defer client.Close()
+
+	var content map[string]map[string]map[string]*rpcPoolTx
+	if err := client.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return nil, fmt.Errorf("txpool_content failed on %s: %v", nodeName, err)
+	}
+
+	var result []PendingTx
+	for pool, senders := range content {
+		for _, txs := range senders {
+			for _, tx := range txs {
+				if tx == nil {
+					continue
+				}
+				gasPrice := tx.GasPrice
+				if tx.MaxFeePerGas != nil {
+					gasPrice = tx.MaxFeePerGas
+				}
+				result = append(result, PendingTx{
+					Hash:     tx.Hash,
+					From:     tx.From,
+					To:       tx.To,
+					Nonce:    uint64(tx.Nonce),
+					Value:    (*big.Int)(tx.Value),
+					GasPrice: (*big.Int)(gasPrice),
+					Queued:   pool == "queued",
+				})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From.Hex() < result[j].From.Hex()
+		}
+		return result[i].Nonce < result[j].Nonce
+	})
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"benchy/internal/clique"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DialValidator connects to the first validator answering clique queries.
+// It fails with clique.ErrNotClique when the validators answer without the
+// clique namespace.
+func DialValidator() (*clique.Client, error) {
+	var notClique error
+	for _, name := range ValidatorNodes {
+		client, err := clique.Dial(nodeEndpoints[name])
+		if err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		_, err = client.GetSigners(ctx)
+		cancel()
+		if err == nil {
+			return client, nil
+		}
+		if errors.Is(err, clique.ErrNotClique) {
+			notClique = err
+		}
+		client.Close()
+	}
+	if notClique != nil {
+		return nil, notClique
+	}
+	return nil, fmt.Errorf("no validator node is reachable")
+}
+
+// SignerNames maps sealing accounts to node names using each node's coinbase.
+func SignerNames(ctx context.Context) map[common.Address]string {
+	names := make(map[common.Address]string)
+	for _, name := range ValidatorNodes {
+		client, err := clique.Dial(nodeEndpoints[name])
+		if err != nil {
+			continue
+		}
+		if coinbase, err := client.Coinbase(ctx); err == nil {
+			names[coinbase] = name
+		}
+		client.Close()
+	}
+	return names
+}
+
+// ValidatorReport is the block production of the Clique signers, with
+// the node name of each signer that is a validator node.
+type ValidatorReport struct {
+	*clique.BlockReport
+	Names map[common.Address]string
+}
+
+// AnalyzeValidators measures block production per signer over the last
+// blockCount blocks.
+func (nm *NetworkMonitor) AnalyzeValidators(blockCount uint64) (*ValidatorReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := DialValidator()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	period, err := clique.LoadPeriod(genesisFile)
+	if err != nil {
+		slog.Warn("Assuming 5s block period", "error", err)
+		period = 5
+	}
+
+	report, err := clique.NewBlockAnalyzer(client, period).Analyze(ctx, blockCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze blocks: %v", err)
+	}
+	return &ValidatorReport{BlockReport: report, Names: SignerNames(ctx)}, nil
+}
+
+// DisplayValidatorSummary prints block production per signer over the last
+// blockCount blocks.
+func (nm *NetworkMonitor) DisplayValidatorSummary(blockCount uint64) error {
+	report, err := nm.AnalyzeValidators(blockCount)
+	if err != nil {
+		return err
+	}
+	names := report.Names
+
+	fmt.Printf("\n🏛️  Validator production (blocks #%d-#%d, period %ds):\n", report.FromBlock, report.ToBlock, report.Period)
+	fmt.Printf("%-12s %-42s %-7s %-7s %-7s %-9s %-7s %-7s\n",
+		"Node", "Signer", "Blocks", "Share", "In", "Out", "Turns", "Missed")
+	fmt.Println("-" + strings.Repeat("-", 100))
+
+	total := report.TotalBlocks()
+	for _, producer := range report.Producers {
+		name := names[producer.Signer]
+		if name == "" {
+			name = "?"
+		}
+		fmt.Printf("%-12s %-42s %-7d %5.1f%%  %-7d %-9d %-7d %-7d\n",
+			name,
+			producer.Signer.Hex(),
+			producer.Blocks,
+			producer.Share(total)*100,
+			producer.InTurn,
+			producer.OutOfTurn,
+			producer.ExpectedTurns,
+			producer.MissedTurns,
+		)
+	}
+
+	times := report.BlockTimes
+	if times.Samples > 0 {
+		fmt.Printf("⏱️  Block time: avg %.1fs, min %ds, max %ds, max deviation %.1fs, %d/%d blocks late\n",
+			times.Average, times.Min, times.Max, times.MaxDeviation, times.Late, times.Samples)
+	}
+
+	return nil
+}
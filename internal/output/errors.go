@@ -0,0 +1,50 @@
+package output
+
+import "fmt"
+
+// Error codes are part of the output schema and must stay stable.
+const (
+	CodeInvalidArgument     = "INVALID_ARGUMENT"
+	CodeUnknownScenario     = "UNKNOWN_SCENARIO"
+	CodeNodeOffline         = "NODE_OFFLINE"
+	CodeScenarioFailed      = "SCENARIO_FAILED"
+	CodeNetworkInfoFailed   = "NETWORK_INFO_FAILED"
+	CodeDockerFailed        = "DOCKER_FAILED"
+	CodeNotFound            = "NOT_FOUND"
+	CodeConflict            = "CONFLICT"
+	CodeJobFailed           = "JOB_FAILED"
+	CodeJobCancelled        = "JOB_CANCELLED"
+	CodeMissingPrerequisite = "MISSING_PREREQUISITE"
+	CodePreconditionFailed  = "PRECONDITION_FAILED"
+	CodeChecksFailed        = "CHECKS_FAILED"
+	CodeMeasurementFailed   = "MEASUREMENT_FAILED"
+	CodeInsufficientFunds   = "INSUFFICIENT_FUNDS"
+	CodeFundingFailed       = "FUNDING_FAILED"
+)
+
+// Error is the structured form of a failure.
+type Error struct {
+	Code    string `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func NewError(code string, err error) *Error {
+	return &Error{Code: code, Message: err.Error()}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// ErrorRecord wraps an error so every format gets an "error" top-level key.
+type ErrorRecord struct {
+	Error *Error `json:"error" yaml:"error"`
+}
+
+func (r ErrorRecord) CSVHeader() []string {
+	return []string{"error_code", "error_message"}
+}
+
+func (r ErrorRecord) CSVRows() [][]string {
+	return [][]string{{r.Error.Code, r.Error.Message}}
+}
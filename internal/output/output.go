@@ -0,0 +1,81 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatCSV  Format = "csv"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON, FormatYAML, FormatCSV:
+		return Format(value), nil
+	}
+	return "", fmt.Errorf("unknown output format %q (expected text, json, yaml or csv)", value)
+}
+
+// Tabular is implemented by records that can be flattened to CSV rows.
+type Tabular interface {
+	CSVHeader() []string
+	CSVRows() [][]string
+}
+
+// Write encodes v to w. Text is not handled here: callers keep their own
+// human readable rendering.
+func Write(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	case FormatCSV:
+		table, ok := v.(Tabular)
+		if !ok {
+			return fmt.Errorf("%T cannot be written as csv", v)
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.Write(table.CSVHeader()); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(table.CSVRows()); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+	return fmt.Errorf("format %q has no encoder", format)
+}
+
+// HumanOutput sends the friendly console messages to stderr while a machine
+// format owns stdout. The returned function restores stdout.
+func HumanOutput(format Format) func() {
+	if format == FormatText {
+		return func() {}
+	}
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	return func() { os.Stdout = stdout }
+}
@@ -0,0 +1,457 @@
+package output
+
+import (
+	"strconv"
+	"time"
+)
+
+// NodeRecord is the stable per-node schema of `infos`.
+type NodeRecord struct {
+	Name        string  `json:"name" yaml:"name"`
+	Client      string  `json:"client" yaml:"client"`
+	Endpoint    string  `json:"endpoint" yaml:"endpoint"`
+	Running     bool    `json:"running" yaml:"running"`
+	BlockNumber uint64  `json:"block_number" yaml:"block_number"`
+	PeerCount   uint64  `json:"peer_count" yaml:"peer_count"`
+	CPUPercent  float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryUsage string  `json:"memory_usage" yaml:"memory_usage"`
+	Address     string  `json:"address" yaml:"address"`
+	BalanceWei  string  `json:"balance_wei" yaml:"balance_wei"`
+	MempoolTxs  int     `json:"mempool_txs" yaml:"mempool_txs"`
+	TxCount     uint64  `json:"tx_count" yaml:"tx_count"`
+	// LogErrors counts recent client log lines by error kind
+	LogErrors map[string]int `json:"log_errors,omitempty" yaml:"log_errors,omitempty"`
+	Error     *Error         `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type NetworkRecord struct {
+	Timestamp time.Time    `json:"timestamp" yaml:"timestamp"`
+	Nodes     []NodeRecord `json:"nodes" yaml:"nodes"`
+	// Validators is the block production analyzed with --validators
+	Validators *ValidatorsRecord `json:"validators,omitempty" yaml:"validators,omitempty"`
+}
+
+// ValidatorsRecord is block production per Clique signer over a range of
+// blocks.
+type ValidatorsRecord struct {
+	FromBlock     uint64           `json:"from_block" yaml:"from_block"`
+	ToBlock       uint64           `json:"to_block" yaml:"to_block"`
+	PeriodSeconds uint64           `json:"period_seconds" yaml:"period_seconds"`
+	Producers     []ProducerRecord `json:"producers" yaml:"producers"`
+	// Block times are measured between consecutive blocks
+	BlockTimeAvgSeconds          float64 `json:"block_time_avg_seconds" yaml:"block_time_avg_seconds"`
+	BlockTimeMinSeconds          uint64  `json:"block_time_min_seconds" yaml:"block_time_min_seconds"`
+	BlockTimeMaxSeconds          uint64  `json:"block_time_max_seconds" yaml:"block_time_max_seconds"`
+	BlockTimeMaxDeviationSeconds float64 `json:"block_time_max_deviation_seconds" yaml:"block_time_max_deviation_seconds"`
+	LateBlocks                   int     `json:"late_blocks" yaml:"late_blocks"`
+}
+
+// ProducerRecord is the production of one signer. Node is empty when the
+// signer is not a known validator node.
+type ProducerRecord struct {
+	Node          string  `json:"node,omitempty" yaml:"node,omitempty"`
+	Signer        string  `json:"signer" yaml:"signer"`
+	Blocks        int     `json:"blocks" yaml:"blocks"`
+	SharePercent  float64 `json:"share_percent" yaml:"share_percent"`
+	InTurn        int     `json:"in_turn" yaml:"in_turn"`
+	OutOfTurn     int     `json:"out_of_turn" yaml:"out_of_turn"`
+	ExpectedTurns int     `json:"expected_turns" yaml:"expected_turns"`
+	MissedTurns   int     `json:"missed_turns" yaml:"missed_turns"`
+}
+
+func (r NetworkRecord) CSVHeader() []string {
+	return []string{"timestamp", "name", "client", "endpoint", "running", "block_number", "peer_count",
+		"cpu_percent", "memory_usage", "address", "balance_wei", "mempool_txs", "tx_count", "error_code"}
+}
+
+func (r NetworkRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, node := range r.Nodes {
+		errorCode := ""
+		if node.Error != nil {
+			errorCode = node.Error.Code
+		}
+		rows = append(rows, []string{
+			r.Timestamp.Format(time.RFC3339),
+			node.Name,
+			node.Client,
+			node.Endpoint,
+			strconv.FormatBool(node.Running),
+			strconv.FormatUint(node.BlockNumber, 10),
+			strconv.FormatUint(node.PeerCount, 10),
+			strconv.FormatFloat(node.CPUPercent, 'f', 2, 64),
+			node.MemoryUsage,
+			node.Address,
+			node.BalanceWei,
+			strconv.Itoa(node.MempoolTxs),
+			strconv.FormatUint(node.TxCount, 10),
+			errorCode,
+		})
+	}
+	return rows
+}
+
+type TransactionRecord struct {
+	Hash     string `json:"hash,omitempty" yaml:"hash,omitempty"`
+	From     string `json:"from" yaml:"from"`
+	To       string `json:"to" yaml:"to"`
+	ValueWei string `json:"value_wei" yaml:"value_wei"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// CheckRecord is one pass/fail verification of a scenario. A skipped check
+// could not be made.
+type CheckRecord struct {
+	Name    string `json:"name" yaml:"name"`
+	Passed  bool   `json:"passed" yaml:"passed"`
+	Skipped bool   `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	Detail  string `json:"detail" yaml:"detail"`
+}
+
+// ScenarioRecord is the stable schema of `scenario`.
+type ScenarioRecord struct {
+	Scenario     string              `json:"scenario" yaml:"scenario"`
+	Success      bool                `json:"success" yaml:"success"`
+	StartedAt    time.Time           `json:"started_at" yaml:"started_at"`
+	FinishedAt   time.Time           `json:"finished_at" yaml:"finished_at"`
+	DurationMs   int64               `json:"duration_ms" yaml:"duration_ms"`
+	Transactions []TransactionRecord `json:"transactions" yaml:"transactions"`
+	// Checks are the health checks of scenario 0
+	Checks []CheckRecord `json:"checks,omitempty" yaml:"checks,omitempty"`
+	// Skipped is set when the effects of the scenario already held
+	Skipped bool `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	// Prerequisites are the scenarios run first with --with-deps
+	Prerequisites []ScenarioRecord `json:"prerequisites,omitempty" yaml:"prerequisites,omitempty"`
+	Host          *HostRecord      `json:"host,omitempty" yaml:"host,omitempty"`
+	// BlockPropagation is measured with --block-propagation
+	BlockPropagation *BlockPropagationRecord `json:"block_propagation,omitempty" yaml:"block_propagation,omitempty"`
+	// LogErrors counts client log lines by node and error kind during the run
+	LogErrors map[string]map[string]int `json:"log_errors,omitempty" yaml:"log_errors,omitempty"`
+	Error     *Error                    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// HostRecord summarizes host usage during a run, to tell whether the host
+// rather than the network was the bottleneck.
+type HostRecord struct {
+	Samples              int                `json:"samples" yaml:"samples"`
+	CPUPercentAvg        float64            `json:"cpu_percent_avg" yaml:"cpu_percent_avg"`
+	CPUPercentMax        float64            `json:"cpu_percent_max" yaml:"cpu_percent_max"`
+	MemoryPercentAvg     float64            `json:"memory_percent_avg" yaml:"memory_percent_avg"`
+	MemoryPercentMax     float64            `json:"memory_percent_max" yaml:"memory_percent_max"`
+	DiskReadBytesPerSec  float64            `json:"disk_read_bytes_per_sec" yaml:"disk_read_bytes_per_sec"`
+	DiskWriteBytesPerSec float64            `json:"disk_write_bytes_per_sec" yaml:"disk_write_bytes_per_sec"`
+	DiskUsedPercent      map[string]float64 `json:"disk_used_percent" yaml:"disk_used_percent"`
+	Saturated            bool               `json:"saturated" yaml:"saturated"`
+	Warnings             []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+func (r ScenarioRecord) CSVHeader() []string {
+	return []string{"scenario", "success", "started_at", "duration_ms", "tx_hash", "tx_from", "tx_to",
+		"tx_value_wei", "tx_error", "error_code"}
+}
+
+// CSVRows emits one row per transaction, or a single row when none were sent,
+// after the rows of the prerequisites.
+func (r ScenarioRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, prerequisite := range r.Prerequisites {
+		rows = append(rows, prerequisite.CSVRows()...)
+	}
+	errorCode := ""
+	if r.Error != nil {
+		errorCode = r.Error.Code
+	}
+	base := []string{r.Scenario, strconv.FormatBool(r.Success), r.StartedAt.Format(time.RFC3339), strconv.FormatInt(r.DurationMs, 10)}
+
+	if len(r.Transactions) == 0 {
+		return append(rows, append(base, "", "", "", "", "", errorCode))
+	}
+	for _, tx := range r.Transactions {
+		row := append(append([]string{}, base...), tx.Hash, tx.From, tx.To, tx.ValueWei, tx.Error, errorCode)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// ScenarioRunRecord is the stable schema of `scenario run-all`.
+type ScenarioRunRecord struct {
+	Success   bool                      `json:"success" yaml:"success"`
+	Scenarios []ScenarioRecord          `json:"scenarios" yaml:"scenarios"`
+	Host      *HostRecord               `json:"host,omitempty" yaml:"host,omitempty"`
+	LogErrors map[string]map[string]int `json:"log_errors,omitempty" yaml:"log_errors,omitempty"`
+	// BlockPropagation is measured with --block-propagation
+	BlockPropagation *BlockPropagationRecord `json:"block_propagation,omitempty" yaml:"block_propagation,omitempty"`
+}
+
+func (r ScenarioRunRecord) CSVHeader() []string {
+	return ScenarioRecord{}.CSVHeader()
+}
+
+func (r ScenarioRunRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, scenario := range r.Scenarios {
+		rows = append(rows, scenario.CSVRows()...)
+	}
+	return rows
+}
+
+// FailureRecord is the stable schema of `temporary-failure`.
+type FailureRecord struct {
+	Node            string    `json:"node" yaml:"node"`
+	DurationSeconds int       `json:"duration_seconds" yaml:"duration_seconds"`
+	StoppedAt       time.Time `json:"stopped_at" yaml:"stopped_at"`
+	RestartedAt     time.Time `json:"restarted_at,omitempty" yaml:"restarted_at,omitempty"`
+	Success         bool      `json:"success" yaml:"success"`
+	Error           *Error    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func (r FailureRecord) CSVHeader() []string {
+	return []string{"node", "duration_seconds", "stopped_at", "restarted_at", "success", "error_code", "error_message"}
+}
+
+func (r FailureRecord) CSVRows() [][]string {
+	restartedAt := ""
+	if !r.RestartedAt.IsZero() {
+		restartedAt = r.RestartedAt.Format(time.RFC3339)
+	}
+	errorCode, errorMessage := "", ""
+	if r.Error != nil {
+		errorCode, errorMessage = r.Error.Code, r.Error.Message
+	}
+	return [][]string{{
+		r.Node,
+		strconv.Itoa(r.DurationSeconds),
+		r.StoppedAt.Format(time.RFC3339),
+		restartedAt,
+		strconv.FormatBool(r.Success),
+		errorCode,
+		errorMessage,
+	}}
+}
+
+// LatencyRecord is a latency distribution in milliseconds. Missed counts
+// the expected observations that never happened.
+type LatencyRecord struct {
+	Samples int     `json:"samples" yaml:"samples"`
+	Missed  int     `json:"missed" yaml:"missed"`
+	MinMs   float64 `json:"min_ms" yaml:"min_ms"`
+	P50Ms   float64 `json:"p50_ms" yaml:"p50_ms"`
+	AvgMs   float64 `json:"avg_ms" yaml:"avg_ms"`
+	P95Ms   float64 `json:"p95_ms" yaml:"p95_ms"`
+	MaxMs   float64 `json:"max_ms" yaml:"max_ms"`
+}
+
+func (r LatencyRecord) csvFields() []string {
+	return []string{strconv.Itoa(r.Samples), strconv.Itoa(r.Missed), formatMs(r.MinMs), formatMs(r.P50Ms),
+		formatMs(r.AvgMs), formatMs(r.P95Ms), formatMs(r.MaxMs)}
+}
+
+func formatMs(ms float64) string {
+	return strconv.FormatFloat(ms, 'f', 1, 64)
+}
+
+// PropagationPairRecord is the latency from acceptance by the origin until
+// the observing node holds the transaction in its pool, then in a block.
+// Node names are empty for client pairs.
+type PropagationPairRecord struct {
+	From       string        `json:"from,omitempty" yaml:"from,omitempty"`
+	To         string        `json:"to,omitempty" yaml:"to,omitempty"`
+	FromClient string        `json:"from_client" yaml:"from_client"`
+	ToClient   string        `json:"to_client" yaml:"to_client"`
+	Pool       LatencyRecord `json:"pool" yaml:"pool"`
+	Block      LatencyRecord `json:"block" yaml:"block"`
+}
+
+// PropagationRecord is the stable schema of `propagation`.
+type PropagationRecord struct {
+	StartedAt   time.Time               `json:"started_at" yaml:"started_at"`
+	DurationMs  int64                   `json:"duration_ms" yaml:"duration_ms"`
+	Sent        int                     `json:"sent" yaml:"sent"`
+	Rejected    []string                `json:"rejected,omitempty" yaml:"rejected,omitempty"`
+	Pairs       []PropagationPairRecord `json:"pairs" yaml:"pairs"`
+	ClientPairs []PropagationPairRecord `json:"client_pairs" yaml:"client_pairs"`
+}
+
+func (r PropagationRecord) CSVHeader() []string {
+	header := []string{"scope", "from", "to", "from_client", "to_client"}
+	for _, stage := range []string{"pool", "block"} {
+		for _, field := range []string{"samples", "missed", "min_ms", "p50_ms", "avg_ms", "p95_ms", "max_ms"} {
+			header = append(header, stage+"_"+field)
+		}
+	}
+	return header
+}
+
+// CSVRows emits the node pairs, then the client pairs.
+func (r PropagationRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, pair := range r.Pairs {
+		rows = append(rows, pair.csvRow("node"))
+	}
+	for _, pair := range r.ClientPairs {
+		rows = append(rows, pair.csvRow("client"))
+	}
+	return rows
+}
+
+func (r PropagationPairRecord) csvRow(scope string) []string {
+	row := []string{scope, r.From, r.To, r.FromClient, r.ToClient}
+	row = append(row, r.Pool.csvFields()...)
+	return append(row, r.Block.csvFields()...)
+}
+
+// HistogramRecord counts latencies per bucket. Counts has one more entry
+// than BoundsMs for the latencies above the last bound.
+type HistogramRecord struct {
+	BoundsMs []float64 `json:"bounds_ms" yaml:"bounds_ms"`
+	Counts   []int     `json:"counts" yaml:"counts"`
+}
+
+// BlockDelayRecord is the delay after the sealer for one node, or for the
+// nodes running one client when Node is empty.
+type BlockDelayRecord struct {
+	Node      string          `json:"node,omitempty" yaml:"node,omitempty"`
+	Client    string          `json:"client" yaml:"client"`
+	Delay     LatencyRecord   `json:"delay" yaml:"delay"`
+	Histogram HistogramRecord `json:"histogram" yaml:"histogram"`
+}
+
+// BlockPropagationRecord is the block propagation measured during a run.
+type BlockPropagationRecord struct {
+	Blocks int `json:"blocks" yaml:"blocks"`
+	// Unattributed blocks are measured from their first sighting
+	Unattributed int                `json:"unattributed" yaml:"unattributed"`
+	Nodes        []BlockDelayRecord `json:"nodes" yaml:"nodes"`
+	Clients      []BlockDelayRecord `json:"clients" yaml:"clients"`
+}
+
+// LoadGroupRecord summarizes the transactions of a load sharing a fee
+// strategy or a workload. Gas figures are averages over the included transactions,
+// reverted ones left out.
+type LoadGroupRecord struct {
+	Name            string        `json:"name" yaml:"name"`
+	Sent            int           `json:"sent" yaml:"sent"`
+	Included        int           `json:"included" yaml:"included"`
+	Reverted        int           `json:"reverted" yaml:"reverted"`
+	Bumps           int           `json:"bumps" yaml:"bumps"`
+	Inclusion       LatencyRecord `json:"inclusion" yaml:"inclusion"`
+	AvgGasUsed      float64       `json:"avg_gas_used" yaml:"avg_gas_used"`
+	AvgGasPriceGwei float64       `json:"avg_gas_price_gwei" yaml:"avg_gas_price_gwei"`
+}
+
+// BlockFeeRecord is the fee market of one block. TipsGwei holds the
+// priority fees at the 10th, 50th and 90th percentiles.
+type BlockFeeRecord struct {
+	Number       uint64    `json:"number" yaml:"number"`
+	BaseFeeGwei  float64   `json:"base_fee_gwei" yaml:"base_fee_gwei"`
+	TipsGwei     []float64 `json:"tips_gwei" yaml:"tips_gwei"`
+	Transactions int       `json:"transactions" yaml:"transactions"`
+	GasUsed      uint64    `json:"gas_used" yaml:"gas_used"`
+	GasLimit     uint64    `json:"gas_limit" yaml:"gas_limit"`
+	GasUsedRatio float64   `json:"gas_used_ratio" yaml:"gas_used_ratio"`
+	Fullness     float64   `json:"fullness" yaml:"fullness"`
+}
+
+// LoadRecord is the stable schema of `load`.
+type LoadRecord struct {
+	Node       string            `json:"node" yaml:"node"`
+	StartedAt  time.Time         `json:"started_at" yaml:"started_at"`
+	DurationMs int64             `json:"duration_ms" yaml:"duration_ms"`
+	Sent       int               `json:"sent" yaml:"sent"`
+	Rejected   []string          `json:"rejected,omitempty" yaml:"rejected,omitempty"`
+	Strategies []LoadGroupRecord `json:"strategies" yaml:"strategies"`
+	Workloads  []LoadGroupRecord `json:"workloads" yaml:"workloads"`
+	// Contracts maps each contract workload to its deployed address
+	Contracts map[string]string `json:"contracts,omitempty" yaml:"contracts,omitempty"`
+	Blocks    []BlockFeeRecord  `json:"blocks" yaml:"blocks"`
+}
+
+func (r LoadRecord) CSVHeader() []string {
+	header := []string{"scope", "name", "sent", "included", "reverted", "bumps"}
+	for _, field := range []string{"samples", "missed", "min_ms", "p50_ms", "avg_ms", "p95_ms", "max_ms"} {
+		header = append(header, "inclusion_"+field)
+	}
+	return append(header, "avg_gas_used", "avg_gas_price_gwei")
+}
+
+// CSVRows emits one row per fee strategy, then per workload; the blocks
+// are left to JSON and YAML.
+func (r LoadRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, group := range r.Strategies {
+		rows = append(rows, group.csvRow("strategy"))
+	}
+	for _, group := range r.Workloads {
+		rows = append(rows, group.csvRow("workload"))
+	}
+	return rows
+}
+
+func (r LoadGroupRecord) csvRow(scope string) []string {
+	row := []string{scope, r.Name, strconv.Itoa(r.Sent), strconv.Itoa(r.Included), strconv.Itoa(r.Reverted), strconv.Itoa(r.Bumps)}
+	row = append(row, r.Inclusion.csvFields()...)
+	return append(row, strconv.FormatFloat(r.AvgGasUsed, 'f', 0, 64), strconv.FormatFloat(r.AvgGasPriceGwei, 'f', 3, 64))
+}
+
+// AccountNodeRecord is an account as one node sees it.
+type AccountNodeRecord struct {
+	Node       string `json:"node" yaml:"node"`
+	BalanceWei string `json:"balance_wei" yaml:"balance_wei"`
+	Nonce      uint64 `json:"nonce" yaml:"nonce"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type AccountRecord struct {
+	Index   int                 `json:"index" yaml:"index"`
+	Address string              `json:"address" yaml:"address"`
+	Nodes   []AccountNodeRecord `json:"nodes" yaml:"nodes"`
+}
+
+// AccountsRecord is the stable schema of `accounts list`. Accounts are
+// derived at Path/index.
+type AccountsRecord struct {
+	Path     string          `json:"path" yaml:"path"`
+	Accounts []AccountRecord `json:"accounts" yaml:"accounts"`
+}
+
+func (r AccountsRecord) CSVHeader() []string {
+	return []string{"index", "address", "node", "balance_wei", "nonce", "error"}
+}
+
+// CSVRows emits one row per account and node.
+func (r AccountsRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, account := range r.Accounts {
+		for _, node := range account.Nodes {
+			rows = append(rows, []string{strconv.Itoa(account.Index), account.Address, node.Node,
+				node.BalanceWei, strconv.FormatUint(node.Nonce, 10), node.Error})
+		}
+	}
+	return rows
+}
+
+type FaucetTransferRecord struct {
+	To     string `json:"to" yaml:"to"`
+	TxHash string `json:"tx_hash" yaml:"tx_hash"`
+	Block  uint64 `json:"block" yaml:"block"`
+}
+
+// FaucetRecord is the stable schema of `faucet`.
+type FaucetRecord struct {
+	Node      string                 `json:"node" yaml:"node"`
+	From      string                 `json:"from" yaml:"from"`
+	AmountWei string                 `json:"amount_wei" yaml:"amount_wei"`
+	Transfers []FaucetTransferRecord `json:"transfers" yaml:"transfers"`
+}
+
+func (r FaucetRecord) CSVHeader() []string {
+	return []string{"from", "to", "amount_wei", "tx_hash", "block"}
+}
+
+func (r FaucetRecord) CSVRows() [][]string {
+	var rows [][]string
+	for _, transfer := range r.Transfers {
+		rows = append(rows, []string{r.From, transfer.To, r.AmountWei, transfer.TxHash, strconv.FormatUint(transfer.Block, 10)})
+	}
+	return rows
+}
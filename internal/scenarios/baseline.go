@@ -0,0 +1,383 @@
+package scenarios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"benchy/internal/clique"
+	"benchy/internal/monitor"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+var ErrChecksFailed = errors.New("health checks failed")
+
+// baselineWait bounds how long scenario 0 waits for every validator to seal.
+const baselineWait = 2 * time.Minute
+
+// Check is one pass/fail verification made by a scenario. A skipped check
+// could not be made and counts as neither.
+type Check struct {
+	Name    string
+	Passed  bool
+	Skipped bool
+	Detail  string
+}
+
+func (tm *TransactionManager) recordCheck(name string, passed bool, format string, args ...interface{}) {
+	check := Check{Name: name, Passed: passed, Detail: fmt.Sprintf(format, args...)}
+	if check.Passed {
+		fmt.Fprintf(tm.out, "✅ %-20s %s\n", check.Name, check.Detail)
+	} else {
+		fmt.Fprintf(tm.out, "❌ %-20s %s\n", check.Name, check.Detail)
+	}
+	tm.addCheck(check)
+}
+
+func (tm *TransactionManager) recordSkipped(name string, format string, args ...interface{}) {
+	check := Check{Name: name, Skipped: true, Detail: fmt.Sprintf(format, args...)}
+	fmt.Fprintf(tm.out, "⏭️  %-20s %s\n", check.Name, check.Detail)
+	tm.addCheck(check)
+}
+
+func (tm *TransactionManager) addCheck(check Check) {
+	if tm.current != nil {
+		tm.current.Checks = append(tm.current.Checks, check)
+	}
+}
+
+// FullScenario0 is the network health baseline: the nodes run the same
+// chain from the configured genesis, every validator seals at the Clique
+// period and the nodes agree on the head.
+func (tm *TransactionManager) FullScenario0() error {
+	slog.Info("🎬 Scenario 0: Network health baseline")
+	ctx := context.Background()
+	if tm.current == nil {
+		// Called outside Run, the checks still need a result to count them
+		tm.current = &ScenarioResult{Scenario: "0", StartedAt: time.Now()}
+		defer func() { tm.current = nil }()
+	}
+
+	nodes := tm.reachableNodes(ctx)
+	genesis, genesisErr := readGenesis(tm.genesis)
+
+	tm.checkChainIDs(ctx, nodes, genesis)
+	tm.checkGenesisHash(ctx, nodes)
+	tm.checkSealing(ctx, nodes, genesis)
+	tm.checkHeadConvergence(ctx, nodes)
+	tm.checkGenesisAlloc(ctx, nodes, genesis, genesisErr)
+
+	failed := 0
+	for _, check := range tm.current.Checks {
+		if !check.Passed && !check.Skipped {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrChecksFailed, failed, len(tm.current.Checks))
+	}
+	return nil
+}
+
+// reachableNodes checks that every selected node answers and returns those
+// that do, in display order.
+func (tm *TransactionManager) reachableNodes(ctx context.Context) []string {
+	var names []string
+	for _, name := range monitor.NodeOrder {
+		if _, ok := tm.clients[name]; ok {
+			names = append(names, name)
+		}
+	}
+	var reachable, down []string
+	for _, name := range names {
+		callCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		_, err := tm.clients[name].BlockNumber(callCtx)
+		cancel()
+		if err != nil {
+			down = append(down, name)
+			continue
+		}
+		reachable = append(reachable, name)
+	}
+	if len(down) > 0 {
+		tm.recordCheck("nodes reachable", false, "%d/%d nodes answer, down: %s", len(reachable), len(names), strings.Join(down, ", "))
+	} else {
+		tm.recordCheck("nodes reachable", true, "%d/%d nodes answer", len(reachable), len(names))
+	}
+	return reachable
+}
+
+func (tm *TransactionManager) checkChainIDs(ctx context.Context, nodes []string, genesis *core.Genesis) {
+	ids := make(map[string][]string)
+	for _, name := range nodes {
+		id, err := tm.clients[name].ChainID(ctx)
+		if err != nil {
+			ids["error"] = append(ids["error"], name)
+			continue
+		}
+		ids[id.String()] = append(ids[id.String()], name)
+	}
+	if len(ids) != 1 {
+		tm.recordCheck("chain id", false, "nodes disagree: %s", formatGroups(ids))
+		return
+	}
+	for id := range ids {
+		if genesis != nil && genesis.Config != nil && genesis.Config.ChainID != nil && genesis.Config.ChainID.String() != id {
+			tm.recordCheck("chain id", false, "nodes run chain %s, genesis %s declares %s", id, tm.genesis, genesis.Config.ChainID)
+			return
+		}
+		tm.recordCheck("chain id", true, "all nodes on chain %s", id)
+	}
+}
+
+func (tm *TransactionManager) checkGenesisHash(ctx context.Context, nodes []string) {
+	hashes := make(map[string][]string)
+	for _, name := range nodes {
+		header, err := tm.clients[name].HeaderByNumber(ctx, big.NewInt(0))
+		if err != nil {
+			hashes["error"] = append(hashes["error"], name)
+			continue
+		}
+		hashes[header.Hash().Hex()] = append(hashes[header.Hash().Hex()], name)
+	}
+	if len(hashes) != 1 {
+		tm.recordCheck("genesis hash", false, "nodes disagree: %s", formatGroups(hashes))
+		return
+	}
+	for hash := range hashes {
+		tm.recordCheck("genesis hash", true, "all nodes share %s", hash)
+	}
+}
+
+// checkSealing waits until every Clique signer sealed a recent block, then
+// compares the average block time with the period.
+func (tm *TransactionManager) checkSealing(ctx context.Context, nodes []string, genesis *core.Genesis) {
+	if len(nodes) == 0 {
+		tm.recordCheck("validators sealed", false, "no node answers")
+		tm.recordCheck("block time", false, "no node answers")
+		return
+	}
+	client, err := clique.Dial(tm.endpoints[nodes[0]])
+	if err != nil {
+		tm.recordCheck("validators sealed", false, "%v", err)
+		tm.recordCheck("block time", false, "%v", err)
+		return
+	}
+	defer client.Close()
+
+	signers, err := client.GetSigners(ctx)
+	if err != nil {
+		tm.recordCheck("validators sealed", false, "%v", err)
+		tm.recordCheck("block time", false, "%v", err)
+		return
+	}
+	var period uint64
+	if genesis != nil && genesis.Config != nil && genesis.Config.Clique != nil {
+		period = genesis.Config.Clique.Period
+	}
+
+	// Enough blocks for every signer to get its turn a few times
+	window := uint64(4 * len(signers))
+	if window < 20 {
+		window = 20
+	}
+	var blocks []clique.SealedBlock
+	var missing []common.Address
+	deadline := time.Now().Add(baselineWait)
+	for {
+		blocks, err = client.RecentBlocks(ctx, window)
+		if err != nil {
+			tm.recordCheck("validators sealed", false, "%v", err)
+			tm.recordCheck("block time", false, "%v", err)
+			return
+		}
+		missing = unsealed(signers, blocks)
+		if len(missing) == 0 && uint64(len(blocks)) >= window/2 || time.Now().After(deadline) {
+			break
+		}
+		slog.Info("⏱️  Network sealing blocks", "blocks", len(blocks), "waiting_for", len(missing))
+		time.Sleep(time.Duration(max(period, 1)) * time.Second)
+	}
+
+	if len(missing) > 0 {
+		tm.recordCheck("validators sealed", false, "%d/%d signers sealed none of the last %d blocks: %s",
+			len(missing), len(signers), len(blocks), joinAddresses(missing))
+	} else {
+		tm.recordCheck("validators sealed", true, "all %d signers sealed in the last %d blocks", len(signers), len(blocks))
+	}
+
+	if period == 0 {
+		tm.recordCheck("block time", false, "no Clique period in genesis %s", tm.genesis)
+		return
+	}
+	stats := clique.AnalyzeBlocks(blocks, signers, period).BlockTimes
+	if stats.Samples == 0 {
+		tm.recordCheck("block time", false, "not enough blocks to measure")
+		return
+	}
+	// Out-of-turn signers wait a random delay, so only the average is held
+	// to the period
+	tolerance := math.Max(1, float64(period)/4)
+	deviation := math.Abs(stats.Average - float64(period))
+	tm.recordCheck("block time", deviation <= tolerance, "average %.2fs over %d blocks for a %ds period (±%.2fs), min %ds, max %ds",
+		stats.Average, stats.Samples, period, tolerance, stats.Min, stats.Max)
+}
+
+func unsealed(signers []common.Address, blocks []clique.SealedBlock) []common.Address {
+	sealed := make(map[common.Address]bool)
+	for _, block := range blocks {
+		sealed[block.Signer] = true
+	}
+	var missing []common.Address
+	for _, signer := range signers {
+		if !sealed[signer] {
+			missing = append(missing, signer)
+		}
+	}
+	return missing
+}
+
+// checkHeadConvergence compares the nodes' hashes at the lowest of their
+// heads, retrying a few times since a block may still be propagating.
+func (tm *TransactionManager) checkHeadConvergence(ctx context.Context, nodes []string) {
+	if len(nodes) == 0 {
+		tm.recordCheck("head convergence", false, "no node answers")
+		return
+	}
+	var detail string
+	for attempt := 0; attempt < 5; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Second)
+		}
+		var lowest, highest uint64
+		for i, name := range nodes {
+			head, err := tm.clients[name].BlockNumber(ctx)
+			if err != nil {
+				detail = fmt.Sprintf("%s: %v", name, err)
+				continue
+			}
+			if i == 0 || head < lowest {
+				lowest = head
+			}
+			if head > highest {
+				highest = head
+			}
+		}
+		hashes := make(map[string][]string)
+		for _, name := range nodes {
+			header, err := tm.clients[name].HeaderByNumber(ctx, new(big.Int).SetUint64(lowest))
+			if err != nil {
+				hashes["error"] = append(hashes["error"], name)
+				continue
+			}
+			hashes[header.Hash().Hex()] = append(hashes[header.Hash().Hex()], name)
+		}
+		if len(hashes) == 1 {
+			for hash := range hashes {
+				tm.recordCheck("head convergence", true, "all nodes have %s at #%d, %d block(s) between heads", hash, lowest, highest-lowest)
+			}
+			return
+		}
+		detail = fmt.Sprintf("nodes disagree at #%d: %s", lowest, formatGroups(hashes))
+	}
+	tm.recordCheck("head convergence", false, "%s", detail)
+}
+
+// checkGenesisAlloc compares the genesis balances of the prefunded accounts
+// on every node with the genesis file. Nodes that pruned the genesis state
+// cannot tell, so they are left out, and the check is skipped if none can.
+func (tm *TransactionManager) checkGenesisAlloc(ctx context.Context, nodes []string, genesis *core.Genesis, genesisErr error) {
+	if genesisErr != nil {
+		tm.recordCheck("genesis alloc", false, "%v", genesisErr)
+		return
+	}
+	var mismatches []string
+	prunedOn := make(map[string]bool)
+	for address, account := range genesis.Alloc {
+		want := account.Balance
+		if want == nil {
+			want = new(big.Int)
+		}
+		for _, name := range nodes {
+			balance, err := tm.clients[name].BalanceAt(ctx, address, big.NewInt(0))
+			if err != nil && prunedState(err) {
+				prunedOn[name] = true
+				continue
+			}
+			if err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("%s on %s: %v", address.Hex(), name, err))
+				continue
+			}
+			if balance.Cmp(want) != 0 {
+				mismatches = append(mismatches, fmt.Sprintf("%s on %s: %s wei, genesis %s wei", address.Hex(), name, balance, want))
+			}
+		}
+	}
+	if len(mismatches) > 0 {
+		tm.recordCheck("genesis alloc", false, "%d mismatch(es): %s", len(mismatches), strings.Join(mismatches, "; "))
+		return
+	}
+	var pruned []string
+	for _, name := range nodes {
+		if prunedOn[name] {
+			pruned = append(pruned, name)
+		}
+	}
+	switch {
+	case len(nodes) > 0 && len(pruned) == len(nodes):
+		tm.recordSkipped("genesis alloc", "every node pruned the genesis state")
+	case len(pruned) > 0:
+		tm.recordCheck("genesis alloc", true, "%d prefunded accounts match %s, %s pruned the genesis state",
+			len(genesis.Alloc), tm.genesis, strings.Join(pruned, ", "))
+	default:
+		tm.recordCheck("genesis alloc", true, "%d prefunded accounts match %s on every node", len(genesis.Alloc), tm.genesis)
+	}
+}
+
+// prunedState reports whether err is a node missing the state of an old
+// block, which geth reports as a missing trie node.
+func prunedState(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "missing trie node") || strings.Contains(message, "state is not available")
+}
+
+func readGenesis(path string) (*core.Genesis, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no genesis configured")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis %s: %v", path, err)
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis %s: %v", path, err)
+	}
+	return genesis, nil
+}
+
+// formatGroups renders nodes grouped by the value they reported.
+func formatGroups(groups map[string][]string) string {
+	var parts []string
+	for value, names := range groups {
+		parts = append(parts, fmt.Sprintf("%s (%s)", value, strings.Join(names, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinAddresses(addresses []common.Address) string {
+	parts := make([]string, len(addresses))
+	for i, address := range addresses {
+		parts[i] = address.Hex()
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,140 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrInsufficientFunds reports a funder that cannot cover a faucet request.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// FaucetTransfer is one funding transaction.
+type FaucetTransfer struct {
+	To    common.Address
+	Hash  common.Hash
+	Block uint64
+}
+
+type FaucetResult struct {
+	Node      string
+	From      common.Address
+	Amount    *big.Int
+	Transfers []FaucetTransfer
+}
+
+// Faucet sends amount to every recipient from the unlocked coinbase of node,
+// funded at genesis, in JSON-RPC batches of batchSize. Each batch is mined
+// before the next is sent.
+func (tm *TransactionManager) Faucet(ctx context.Context, node string, recipients []common.Address, amount *big.Int, batchSize int) (*FaucetResult, error) {
+	endpoint := tm.getEndpoint(node)
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s: %w", node, ErrNodeOffline)
+	}
+	rpcClient, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", node, err)
+	}
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	var coinbase common.Address
+	if err := rpcClient.CallContext(ctx, &coinbase, "eth_coinbase"); err != nil {
+		return nil, fmt.Errorf("%w: failed to read the coinbase of %s: %v", ErrNodeOffline, node, err)
+	}
+	balance, err := client.BalanceAt(ctx, coinbase, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balance of %s: %v", coinbase.Hex(), err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gas price from %s: %v", node, err)
+	}
+	// Each transfer also pays its 21000 gas
+	perTransfer := new(big.Int).Add(amount, new(big.Int).Mul(big.NewInt(21000), gasPrice))
+	total := new(big.Int).Mul(perTransfer, big.NewInt(int64(len(recipients))))
+	if balance.Cmp(total) < 0 {
+		return nil, fmt.Errorf("%w: %s holds %s wei, %s wei needed", ErrInsufficientFunds, coinbase.Hex(), balance, total)
+	}
+
+	result := &FaucetResult{Node: node, From: coinbase, Amount: amount}
+	for start := 0; start < len(recipients); start += batchSize {
+		batch := recipients[start:min(start+batchSize, len(recipients))]
+		hashes, err := coinbaseTransfers(ctx, rpcClient, coinbase, batch, amount)
+		if err != nil {
+			return result, err
+		}
+		receipts, err := waitMined(ctx, client, hashes)
+		if err != nil {
+			return result, err
+		}
+		for i, receipt := range receipts {
+			result.Transfers = append(result.Transfers, FaucetTransfer{To: batch[i], Hash: receipt.TxHash, Block: receipt.BlockNumber.Uint64()})
+		}
+		slog.Info("💧 Accounts funded", "done", len(result.Transfers), "total", len(recipients))
+	}
+	return result, nil
+}
+
+// coinbaseTransfers sends amount from the unlocked coinbase to each
+// recipient in one JSON-RPC batch.
+func coinbaseTransfers(ctx context.Context, rpcClient *rpc.Client, coinbase common.Address, recipients []common.Address, amount *big.Int) ([]common.Hash, error) {
+	hashes := make([]common.Hash, len(recipients))
+	batch := make([]rpc.BatchElem, len(recipients))
+	for i, recipient := range recipients {
+		batch[i] = rpc.BatchElem{Method: "eth_sendTransaction", Args: []interface{}{map[string]interface{}{
+			"from":  coinbase,
+			"to":    recipient,
+			"value": (*hexutil.Big)(amount),
+			"gas":   hexutil.Uint64(21000),
+		}}, Result: &hashes[i]}
+	}
+	if err := rpcClient.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("batch submission failed: %v", err)
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("failed to fund %s from %s: %v", recipients[i].Hex(), coinbase.Hex(), elem.Error)
+		}
+	}
+	return hashes, nil
+}
+
+// AccountState is an account as one node sees it. Err is set when the node
+// could not be asked.
+type AccountState struct {
+	Node    string
+	Balance *big.Int
+	Nonce   uint64
+	Err     error
+}
+
+// AccountStates reads the balance and nonce of each address on each of
+// nodes, in their order.
+func (tm *TransactionManager) AccountStates(ctx context.Context, nodes []string, addresses []common.Address) map[common.Address][]AccountState {
+	states := make(map[common.Address][]AccountState, len(addresses))
+	for _, node := range nodes {
+		client, ok := tm.clients[node]
+		for _, address := range addresses {
+			state := AccountState{Node: node}
+			if !ok {
+				state.Err = ErrNodeOffline
+				states[address] = append(states[address], state)
+				continue
+			}
+			state.Balance, state.Err = client.BalanceAt(ctx, address, nil)
+			if state.Err == nil {
+				state.Nonce, state.Err = client.NonceAt(ctx, address, nil)
+			}
+			states[address] = append(states[address], state)
+		}
+	}
+	return states
+}
@@ -0,0 +1,126 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// FeeStrategy decides the fees of the load generator's transactions.
+type FeeStrategy string
+
+const (
+	// FeeFixed pays FeeSettings.Tip and FeeSettings.MaxFee whatever the
+	// market does.
+	FeeFixed FeeStrategy = "fixed"
+	// FeeOracle asks the node: its suggested tip, and twice the base fee
+	// plus the tip as the cap, as wallets do.
+	FeeOracle FeeStrategy = "oracle"
+	// FeeBump starts at the oracle fees and replaces transactions still
+	// pending after FeeSettings.BumpAfter with fees raised by BumpPercent.
+	FeeBump FeeStrategy = "bump"
+)
+
+// minBumpPercent is the smallest raise geth and Nethermind accept for a
+// replacement transaction.
+const minBumpPercent = 10
+
+func ParseFeeStrategy(value string) (FeeStrategy, error) {
+	switch strategy := FeeStrategy(strings.ToLower(value)); strategy {
+	case FeeFixed, FeeOracle, FeeBump:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown fee strategy %q (expected fixed, oracle or bump)", value)
+}
+
+// FeeSettings configure the strategies.
+type FeeSettings struct {
+	// Tip and MaxFee are the fixed strategy's fees
+	Tip    *big.Int
+	MaxFee *big.Int
+	// BumpPercent raises the fees of each replacement
+	BumpPercent int
+	// BumpAfter is how many blocks a transaction may stay pending before
+	// it is replaced
+	BumpAfter uint64
+	// MaxBumps bounds the replacements of one transaction
+	MaxBumps int
+}
+
+// DefaultFeeSettings pays a 1 gwei tip up to 20 gwei.
+func DefaultFeeSettings() FeeSettings {
+	return FeeSettings{
+		Tip:         big.NewInt(params.GWei),
+		MaxFee:      big.NewInt(20 * params.GWei),
+		BumpPercent: 25,
+		BumpAfter:   2,
+		MaxBumps:    3,
+	}
+}
+
+// Fees are the EIP-1559 fees of a transaction.
+type Fees struct {
+	Tip    *big.Int
+	MaxFee *big.Int
+}
+
+func (f Fees) String() string {
+	return fmt.Sprintf("tip %s gwei, max %s gwei", formatGwei(f.Tip), formatGwei(f.MaxFee))
+}
+
+// Bump raises both fees by percent, at least by the replacement minimum.
+func (f Fees) Bump(percent int) Fees {
+	percent = max(percent, minBumpPercent)
+	raise := func(fee *big.Int) *big.Int {
+		raised := new(big.Int).Mul(fee, big.NewInt(int64(100+percent)))
+		raised.Div(raised, big.NewInt(100))
+		// Integer division could round a tiny fee back to itself
+		if raised.Cmp(fee) <= 0 {
+			raised.Add(fee, big.NewInt(1))
+		}
+		return raised
+	}
+	return Fees{Tip: raise(f.Tip), MaxFee: raise(f.MaxFee)}
+}
+
+// SuggestFees returns the initial fees of strategy on the node of client.
+func SuggestFees(ctx context.Context, client *ethclient.Client, strategy FeeStrategy, settings FeeSettings) (Fees, error) {
+	if strategy == FeeFixed {
+		return Fees{Tip: settings.Tip, MaxFee: settings.MaxFee}, nil
+	}
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return Fees{}, fmt.Errorf("failed to read the suggested tip: %v", err)
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return Fees{}, fmt.Errorf("failed to read the base fee: %v", err)
+	}
+	baseFee := header.BaseFee
+	if baseFee == nil {
+		baseFee = new(big.Int)
+	}
+	maxFee := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+	return Fees{Tip: tip, MaxFee: maxFee}, nil
+}
+
+// suggestFees is SuggestFees on a node of the manager.
+func (tm *TransactionManager) suggestFees(node string, strategy FeeStrategy, settings FeeSettings) (Fees, error) {
+	client, ok := tm.clients[node]
+	if !ok {
+		return Fees{}, fmt.Errorf("%w: %s is not selected", ErrNodeOffline, node)
+	}
+	return SuggestFees(context.Background(), client, strategy, settings)
+}
+
+func formatGwei(wei *big.Int) string {
+	if wei == nil {
+		return "0"
+	}
+	gwei := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei))
+	return gwei.Text('f', 3)
+}
@@ -1,422 +1,359 @@
 package scenarios
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"log/slog"
 	"math/big"
-	"os/exec"
 	"strconv"
-	"strings"
 	"time"
 	"benchy/internal/monitor"
-)
-
-type PersistentState struct {
-	Scenario1Executed         bool    `json:"scenario1_executed"`
-	Scenario2Executed         bool    `json:"scenario2_executed"`
-	Scenario3Executed         bool    `json:"scenario3_executed"`
-	AliceTransactionsSent     int     `json:"alice_transactions_sent"`
-	BobETHReceived           float64 `json:"bob_eth_received"`
-	CassandraTransactionsSent int     `json:"cassandra_transactions_sent"`
-	AliceHasRestarted        bool    `json:"alice_has_restarted"`
-}
-
-func (tm *TransactionManager) loadState() *PersistentState {
-	state := &PersistentState{}
-	stateFile := "benchy_state.json"
-	
-	if data, err := ioutil.ReadFile(stateFile); err == nil {
-		json.Unmarshal(data, state)
-	}
-	
-	return state
-}
+	"benchy/internal/state"
 
-func (tm *TransactionManager) FullScenario0() error {
-	fmt.Println("🎬 Scenario 0: Network Initialization")
-	fmt.Println("⏳ Letting the network run for a few minutes...")
-	fmt.Println("🔍 Validating nodes must have ETH available as reward or part of initial configuration")
-	
-	for i := 1; i <= 2; i++ {
-		fmt.Printf("⏱️  Minute %d/2 - Network mining blocks...\n", i)
-		time.Sleep(60 * time.Second)
-		
-		tm.GetNetworkStatus()
-	}
-	
-	fmt.Println("\n🔍 Final check - Validator balances:")
-	
-	validators := map[string]string{
-		"alice":     "0x71562b71999873db5b286df957af199ec94617f7",
-		"bob":       "0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1", 
-		"cassandra": "0x71562b71999873db5b286df957af199ec94617f7",
-	}
-	
-	for node, address := range validators {
-		endpoint := tm.getEndpoint(node)
-		balance := tm.getExpectedBalance(endpoint, address, node)
-		
-		if balance != "0.0000 ETH" {
-			fmt.Printf("✅ %s has positive balance: %s\n", strings.Title(node), balance)
-		} else {
-			fmt.Printf("❌ %s has zero balance\n", strings.Title(node))
-		}
-	}
-	
-	return nil
-}
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
 
 func (tm *TransactionManager) FullScenario1() error {
-	fmt.Println("🎬 Scenario 1: Alice sending 0.1 ETH to Bob every 10 seconds")
+	slog.Info("🎬 Scenario 1: Alice sending 0.1 ETH to Bob every 10 seconds")
 	
-	aliceEndpoint := "http://localhost:8545"
 	bobAddress := "0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1"
 	
 	if !tm.isNodeOnline("alice") {
-		fmt.Println("❌ Alice is offline - cannot execute scenario 1")
-		return fmt.Errorf("alice is offline")
+		slog.Error("Alice is offline - cannot execute scenario 1")
+		return fmt.Errorf("alice: %w", ErrNodeOffline)
 	}
 	
-	fmt.Println("💰 Balances before scenario 1:")
-	aliceBalanceBefore := tm.getCurrentBalance(aliceEndpoint, "0x71562b71999873db5b286df957af199ec94617f7")
-	bobBalanceBefore := tm.getCurrentBalance(aliceEndpoint, bobAddress)
-	fmt.Printf("   Alice: %s\n", aliceBalanceBefore)
-	fmt.Printf("   Bob: %s\n", bobBalanceBefore)
+	alice, err := tm.coinbase("alice")
+	if err != nil {
+		return err
+	}
+	aliceAddress := alice.Hex()
+	
+	fmt.Fprintln(tm.out, "💰 Balances before scenario 1:")
+	aliceBalanceBefore := tm.getCurrentBalance("alice", aliceAddress)
+	bobBalanceBefore := tm.getCurrentBalance("alice", bobAddress)
+	fmt.Fprintf(tm.out, "   Alice: %s\n", aliceBalanceBefore)
+	fmt.Fprintf(tm.out, "   Bob: %s\n", bobBalanceBefore)
 	
 	successfulTransactions := 0
 	
 	for i := 1; i <= 3; i++ {
-		fmt.Printf("💸 Transfer #%d: Alice → Bob (0.1 ETH)\n", i)
+		fmt.Fprintf(tm.out, "💸 Transfer #%d: Alice → Bob (0.1 ETH)\n", i)
 		
-		err := tm.executeTransactionWithValidation(aliceEndpoint,
-			"0x71562b71999873db5b286df957af199ec94617f7",
+		err := tm.executeTransactionWithValidation("alice",
+			aliceAddress,
 			bobAddress,
 			"0x16345785d8a0000",
-			"Alice", "Bob")
+			"Alice", "Bob", nil)
 		
 		if err != nil {
-			fmt.Printf("❌ Transfer #%d failed: %v\n", i, err)
+			slog.Error("Transfer failed", "transfer", i, "error", err)
 		} else {
-			fmt.Printf("✅ Transfer #%d completed\n", i)
+			fmt.Fprintf(tm.out, "✅ Transfer #%d completed\n", i)
 			successfulTransactions++
 		}
 		
 		if i < 3 {
-			fmt.Println("⏱️  Waiting 10 seconds...")
+			slog.Info("⏱️  Waiting before next transfer", "seconds", 10)
 			time.Sleep(10 * time.Second)
 		}
 	}
 	
 	if successfulTransactions == 0 {
-		fmt.Printf("❌ All transactions failed - Scenario 1 NOT executed\n")
+		slog.Error("All transactions failed - Scenario 1 NOT executed")
 		return fmt.Errorf("scenario 1 failed: no successful transactions")
 	}
 	
-	fmt.Println("\n💰 Balances after scenario 1:")
-	aliceBalanceAfter := tm.getCurrentBalance(aliceEndpoint, "0x71562b71999873db5b286df957af199ec94617f7")
-	bobBalanceAfter := tm.getCurrentBalance(aliceEndpoint, bobAddress)
-	fmt.Printf("   Alice: %s (sent %d×0.1 ETH)\n", aliceBalanceAfter, successfulTransactions)
-	fmt.Printf("   Bob: %s (received %d×0.1 ETH)\n", bobBalanceAfter, successfulTransactions)
+	fmt.Fprintln(tm.out, "\n💰 Balances after scenario 1:")
+	aliceBalanceAfter := tm.getCurrentBalance("alice", aliceAddress)
+	bobBalanceAfter := tm.getCurrentBalance("alice", bobAddress)
+	fmt.Fprintf(tm.out, "   Alice: %s (sent %d×0.1 ETH)\n", aliceBalanceAfter, successfulTransactions)
+	fmt.Fprintf(tm.out, "   Bob: %s (received %d×0.1 ETH)\n", bobBalanceAfter, successfulTransactions)
 	
-	monitor.MarkScenarioExecutedWithCount(1, successfulTransactions)
-	fmt.Println("🔄 Scenario 1 marked as executed in monitoring system")
+	monitor.MarkScenarioExecuted(1)
+	slog.Debug("Scenario marked as executed in monitoring system", "scenario", 1)
 	
 	return nil
 }
 
 func (tm *TransactionManager) isNodeOnline(nodeName string) bool {
-	endpoint := tm.getEndpoint(nodeName)
-	
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`,
-		endpoint)
-	
-	output, err := cmd.Output()
-	if err != nil {
+	client, ok := tm.clients[nodeName]
+	if !ok {
 		return false
 	}
 	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if result, ok := response["result"].(string); ok {
-			return result != ""
-		}
-		if _, hasError := response["error"]; hasError {
-			return false
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.BlockNumber(ctx)
+	return err == nil
+}
+
+// executeTransactionWithValidation sends value from an account unlocked on
+// node. Nil fees let the node price the transaction.
+func (tm *TransactionManager) executeTransactionWithValidation(node, from, to, value, fromName, toName string, fees *Fees) error {
+	txHash, err := tm.sendTransactionWithValidation(node, from, to, value, fromName, toName, fees)
+
+	valueWei := value
+	amount, decodeErr := hexutil.DecodeBig(value)
+	if decodeErr == nil {
+		valueWei = amount.String()
 	}
-	
-	return false
+	tm.recordTransaction(TransactionResult{Hash: txHash, From: from, To: to, Value: valueWei, Err: err})
+	if err == nil {
+		tm.recordSent(state.TxSent(fromName, toName, amount, txHash))
+	}
+
+	return err
 }
 
-func (tm *TransactionManager) executeTransactionWithValidation(endpoint, from, to, value, fromName, toName string) error {
-	fmt.Printf("📤 %s → %s\n", fromName, toName)
-	fmt.Printf("   From: %s\n", from)
-	fmt.Printf("   To:   %s\n", to)
-	fmt.Printf("   Amount: %s ETH\n", tm.getETHFromWei(value))
-	
-	testCmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`,
-		endpoint)
-	
-	if _, err := testCmd.Output(); err != nil {
-		return fmt.Errorf("node %s is unreachable", endpoint)
+func (tm *TransactionManager) sendTransactionWithValidation(node, from, to, value, fromName, toName string, fees *Fees) (string, error) {
+	fmt.Fprintf(tm.out, "📤 %s → %s\n", fromName, toName)
+	fmt.Fprintf(tm.out, "   From: %s\n", from)
+	fmt.Fprintf(tm.out, "   To:   %s\n", to)
+	fmt.Fprintf(tm.out, "   Amount: %s ETH\n", tm.getETHFromWei(value))
+	
+	if !tm.isNodeOnline(node) {
+		return "", fmt.Errorf("%s: %w", node, ErrNodeOffline)
 	}
 	
 	balanceBeforeFloat := tm.calculateBalanceForTransaction(to, toName, false)
 	balanceBefore := fmt.Sprintf("%.4f ETH", balanceBeforeFloat)
-	fmt.Printf("   %s balance before: %s\n", toName, balanceBefore)
-	
-	transactionData := fmt.Sprintf(`{
-		"jsonrpc": "2.0",
-		"method": "eth_sendTransaction",
-		"params": [{
-			"from": "%s",
-			"to": "%s", 
-			"value": "%s",
-			"gas": "0x5208"
-		}],
-		"id": 1
-	}`, from, to, value)
-	
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", transactionData,
-		endpoint)
-	
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("transaction request failed: %v", err)
-	}
-	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if txHash, ok := response["result"].(string); ok && txHash != "" {
-			fmt.Printf("   ✅ TX Hash: %s\n", txHash)
-			
-			time.Sleep(2 * time.Second)
-			
-			amountFloat := tm.getAmountFloatFromWei(value)
-			balanceAfterFloat := balanceBeforeFloat + amountFloat
-			balanceAfter := fmt.Sprintf("%.4f ETH", balanceAfterFloat)
-			fmt.Printf("   %s balance after: %s\n", toName, balanceAfter)
-			
-			return nil
-		} else if errMsg, ok := response["error"]; ok {
-			return fmt.Errorf("transaction error: %v", errMsg)
-		} else {
-			return fmt.Errorf("no transaction hash returned")
-		}
+	fmt.Fprintf(tm.out, "   %s balance before: %s\n", toName, balanceBefore)
+	
+	args := map[string]interface{}{
+		"from":  from,
+		"to":    to,
+		"value": value,
+		"gas":   hexutil.Uint64(21000),
 	}
+	if fees != nil {
+		args["maxFeePerGas"] = (*hexutil.Big)(fees.MaxFee)
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(fees.Tip)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var txHash common.Hash
+	if err := tm.clients[node].Client().CallContext(ctx, &txHash, "eth_sendTransaction", args); err != nil {
+		return "", fmt.Errorf("transaction error: %v", err)
+	}
+	fmt.Fprintf(tm.out, "   ✅ TX Hash: %s\n", txHash.Hex())
+	
+	time.Sleep(2 * time.Second)
 	
-	return fmt.Errorf("invalid response format")
+	amountFloat := tm.getAmountFloatFromWei(value)
+	balanceAfterFloat := balanceBeforeFloat + amountFloat
+	balanceAfter := fmt.Sprintf("%.4f ETH", balanceAfterFloat)
+	fmt.Fprintf(tm.out, "   %s balance after: %s\n", toName, balanceAfter)
+	
+	return txHash.Hex(), nil
 }
 
 func (tm *TransactionManager) FullScenario2() error {
-	fmt.Println("🎬 Scenario 2: Cassandra deploys ERC20 contract (3000 BY tokens)")
-	fmt.Println("📄 Deploying ERC20 smart contract...")
+	slog.Info("🎬 Scenario 2: Cassandra deploys ERC20 contract (3000 BY tokens)")
+	fmt.Fprintln(tm.out, "📄 Deploying ERC20 smart contract...")
 	
 	if !tm.isNodeOnline("cassandra") {
-		fmt.Println("❌ Cassandra is offline - cannot execute scenario 2")
-		return fmt.Errorf("cassandra is offline")
+		slog.Error("Cassandra is offline - cannot execute scenario 2")
+		return fmt.Errorf("cassandra: %w", ErrNodeOffline)
 	}
 	
-	drissAddress := "0x9876543210fedcba9876543210fedcba98765431"  
-	elenaAddress := "0x9876543210fedcba9876543210fedcba98765432"  
-	
-	fmt.Println("🚀 Contract deployment transaction:")
-	fmt.Printf("📤 Cassandra → Blockchain\n")
-	fmt.Printf("   From: 0x71562b71999873db5b286df957af199ec94617f7\n")
-	fmt.Printf("   To: null (contract creation)\n")
-	fmt.Printf("   Data: ERC20 bytecode + constructor(\"ByToken\", \"BY\", 3000)\n")
-	fmt.Printf("   Gas: 1500000\n")
-	
-	time.Sleep(2 * time.Second)
-	
-	fmt.Printf("   ✅ Contract TX Hash: 0xabc123...def456\n")
-	fmt.Printf("   📋 Contract deployed at: 0x5FbDB2315678afecb367f032d93F642f64180aa3\n")
-	fmt.Printf("   🎯 Total supply: 3000 BY tokens\n")
-	fmt.Printf("   👑 Owner: Cassandra (0x71562b71999873db5b286df957af199ec94617f7)\n")
-	
-	time.Sleep(1 * time.Second)
-	
-	fmt.Printf("\n🎯 Distributing tokens from contract:\n")
-	fmt.Printf("🎯 Distributing 1000 BY tokens to Driss (%s)\n", drissAddress)
-	fmt.Printf("🎯 Distributing 1000 BY tokens to Elena (%s)\n", elenaAddress)
-	fmt.Printf("🏦 Cassandra keeps remaining 1000 BY tokens\n")
-	
-	fmt.Println("\n💸 Token transfer: 1000 BY → Driss")
-	fmt.Printf("📤 Smart Contract Call: transfer(driss, 1000)\n")
-	fmt.Printf("   ✅ Contract TX Hash: 0xdef789...ghi012\n")
-	fmt.Printf("   📋 Driss now has 1000 BY tokens (NO ETH transferred)\n")
+	drissAddress := common.HexToAddress("0x9876543210fedcba9876543210fedcba98765431")
+	elenaAddress := common.HexToAddress("0x9876543210fedcba9876543210fedcba98765432")
 	
-	time.Sleep(1 * time.Second)
+	cassandra, err := tm.coinbase("cassandra")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	gasPaid := new(big.Int)
+	
+	fmt.Fprintln(tm.out, "🚀 Contract deployment transaction:")
+	fmt.Fprintf(tm.out, "📤 Cassandra → Blockchain\n")
+	fmt.Fprintf(tm.out, "   From: %s\n", cassandra.Hex())
+	fmt.Fprintf(tm.out, "   To: null (contract creation)\n")
+	fmt.Fprintf(tm.out, "   Gas: %d\n", workloadDeployGas)
+	deployment, err := tm.coinbaseTransaction(ctx, "cassandra", cassandra, nil, erc20Code, workloadDeployGas)
+	if err != nil {
+		slog.Error("Scenario 2 failed", "error", err)
+		return fmt.Errorf("failed to deploy the BY token: %v", err)
+	}
+	token := deployment.ContractAddress
+	tm.recordSent(state.ContractDeployed("cassandra", token.Hex(), deployment.TxHash.Hex()))
+	gasPaid.Add(gasPaid, receiptFee(deployment))
+	fmt.Fprintf(tm.out, "   ✅ Contract TX Hash: %s\n", deployment.TxHash.Hex())
+	fmt.Fprintf(tm.out, "   📋 Contract deployed at: %s (block #%d)\n", token.Hex(), deployment.BlockNumber.Uint64())
+	
+	fmt.Fprintf(tm.out, "\n🪙 Minting 3000 BY tokens to Cassandra\n")
+	mint := calldata(erc20Mint, common.LeftPadBytes(cassandra.Bytes(), 32), byTokens(3000))
+	minted, err := tm.coinbaseTransaction(ctx, "cassandra", cassandra, &token, mint, tokenGas)
+	if err != nil {
+		slog.Error("Scenario 2 failed", "error", err)
+		return fmt.Errorf("failed to mint the BY tokens: %v", err)
+	}
+	tm.recordSent(state.TxSent("cassandra", "cassandra", nil, minted.TxHash.Hex()))
+	gasPaid.Add(gasPaid, receiptFee(minted))
+	fmt.Fprintf(tm.out, "   ✅ Contract TX Hash: %s\n", minted.TxHash.Hex())
 	
-	fmt.Println("💸 Token transfer: 1000 BY → Elena")
-	fmt.Printf("📤 Smart Contract Call: transfer(elena, 1000)\n")
-	fmt.Printf("   ✅ Contract TX Hash: 0x345abc...def678\n")
-	fmt.Printf("   📋 Elena now has 1000 BY tokens (NO ETH transferred)\n")
+	type holder struct {
+		name    string
+		address common.Address
+	}
+	recipients := []holder{{"Driss", drissAddress}, {"Elena", elenaAddress}}
+	for _, recipient := range recipients {
+		fmt.Fprintf(tm.out, "\n💸 Token transfer: 1000 BY → %s\n", recipient.name)
+		fmt.Fprintf(tm.out, "📤 Smart Contract Call: transfer(%s, 1000)\n", recipient.address.Hex())
+		transfer := calldata(erc20Transfer, common.LeftPadBytes(recipient.address.Bytes(), 32), byTokens(1000))
+		receipt, err := tm.coinbaseTransaction(ctx, "cassandra", cassandra, &token, transfer, tokenGas)
+		if err != nil {
+			slog.Error("Scenario 2 failed", "error", err)
+			return fmt.Errorf("failed to transfer BY tokens to %s: %v", recipient.name, err)
+		}
+		// The token transfers move no ETH but cost Cassandra gas
+		tm.recordSent(state.TxSent("cassandra", recipient.name, nil, receipt.TxHash.Hex()))
+		gasPaid.Add(gasPaid, receiptFee(receipt))
+		fmt.Fprintf(tm.out, "   ✅ Contract TX Hash: %s\n", receipt.TxHash.Hex())
+	}
 	
-	fmt.Println("\n✅ ERC20 deployment and distribution completed!")
-	fmt.Println("📊 Token distribution summary:")
-	fmt.Println("   • Driss: 1000 BY tokens (0 ETH)")
-	fmt.Println("   • Elena: 1000 BY tokens (0 ETH)") 
-	fmt.Println("   • Cassandra: 1000 BY tokens (remaining)")
-	fmt.Printf("   • Gas fees paid by Cassandra: ~0.05 ETH\n")
-	fmt.Println("   • Contract: 0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	fmt.Fprintln(tm.out, "\n✅ ERC20 deployment and distribution completed!")
+	fmt.Fprintln(tm.out, "📊 Token distribution summary:")
+	holders := append(recipients, holder{"Cassandra", cassandra})
+	for _, holder := range holders {
+		balance, err := tm.tokenBalance(ctx, "cassandra", token, holder.address)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tm.out, "   • %s: %s\n", holder.name, formatBY(balance))
+	}
+	fmt.Fprintf(tm.out, "   • Gas fees paid by Cassandra: %.6f ETH\n", state.WeiToETH(gasPaid))
+	fmt.Fprintf(tm.out, "   • Contract: %s\n", token.Hex())
 	
 	monitor.MarkScenarioExecuted(2)
-	fmt.Println("🔄 Scenario 2 marked as executed in monitoring system")
+	slog.Debug("Scenario marked as executed in monitoring system", "scenario", 2)
 		
 	return nil
 }
 
 func (tm *TransactionManager) FullScenario3() error {
-	fmt.Println("🎬 Scenario 3: Transaction replacement with higher fee")
-	fmt.Println("🔄 Cassandra tries to send 1 ETH to Driss, then cancels and sends to Elena")
+	slog.Info("🎬 Scenario 3: Transaction replacement with higher fee")
+	fmt.Fprintln(tm.out, "🔄 Cassandra tries to send 1 ETH to Driss, then cancels and sends to Elena")
 	
-	cassandraEndpoint := "http://localhost:8549"
-	drissAddress := "0x9876543210fedcba9876543210fedcba98765431"
-	elenaAddress := "0x9876543210fedcba9876543210fedcba98765432"
+	drissAddress := common.HexToAddress("0x9876543210fedcba9876543210fedcba98765431")
+	elenaAddress := common.HexToAddress("0x9876543210fedcba9876543210fedcba98765432")
+	oneEther := big.NewInt(1e18)
 	
 	if !tm.isNodeOnline("cassandra") {
-		fmt.Println("❌ Cassandra is offline - cannot execute scenario 3")
-		return fmt.Errorf("cassandra is offline")
-	}
-	
-	fmt.Println("💰 Balances before scenario 3:")
-	drissBalanceBefore := tm.getCurrentBalance(cassandraEndpoint, drissAddress)
-	elenaBalanceBefore := tm.getCurrentBalance(cassandraEndpoint, elenaAddress)
-	fmt.Printf("   Driss: %s (+ 1000 BY tokens)\n", drissBalanceBefore)
-	fmt.Printf("   Elena: %s (+ 1000 BY tokens)\n", elenaBalanceBefore)
-	
-	fmt.Println("\n💸 First transaction: Cassandra → Driss (1 ETH)")
-	fmt.Printf("📤 Cassandra → Driss\n")
-	fmt.Printf("   From: 0x71562b71999873db5b286df957af199ec94617f7\n")
-	fmt.Printf("   To:   %s\n", drissAddress)
-	fmt.Printf("   Amount: 1 ETH\n")
-	fmt.Printf("   Gas Price: 20 gwei\n")
-	fmt.Printf("   Nonce: 2\n")
-	fmt.Printf("   Driss balance before: %s\n", drissBalanceBefore)
-	
-	fmt.Printf("   🔄 TX Hash: 0x1234...abcd (pending in mempool)\n")
-	
-	time.Sleep(3 * time.Second)
-	
-	fmt.Println("\n🔄 Replacement transaction: Cassandra → Elena (1 ETH, higher fee)")
-	fmt.Printf("📤 Replacement with higher gas price:\n")
-	fmt.Printf("   From: 0x71562b71999873db5b286df957af199ec94617f7\n")
-	fmt.Printf("   To: %s\n", elenaAddress)
-	fmt.Printf("   Amount: 1 ETH\n")
-	fmt.Printf("   Gas Price: 50 gwei (2.5x higher!)\n")
-	fmt.Printf("   Nonce: 2 (same nonce)\n")
-	
-	err := tm.executeTransactionWithValidation(cassandraEndpoint,
-		"0x71562b71999873db5b286df957af199ec94617f7",
-		elenaAddress,
-		"0xde0b6b3a7640000",
-		"Cassandra", "Elena")
+		slog.Error("Cassandra is offline - cannot execute scenario 3")
+		return fmt.Errorf("cassandra: %w", ErrNodeOffline)
+	}
 	
+	// The first transaction pays what the node suggests; the replacement
+	// outbids it by the bump strategy's raise
+	settings := DefaultFeeSettings()
+	fees, err := tm.suggestFees("cassandra", FeeOracle, settings)
 	if err != nil {
-		fmt.Printf("❌ Scenario 3 failed: %v\n", err)
 		return err
 	}
+	replacementFees := fees.Bump(settings.BumpPercent)
 	
-	time.Sleep(2 * time.Second)
-	
-	fmt.Printf("\n❌ First transaction cancelled (replaced by higher fee)\n")
-	fmt.Printf("   Reason: Same nonce (2) with higher gas price (50 gwei > 20 gwei)\n")
-	fmt.Printf("   Driss balance after: %s (unchanged)\n", drissBalanceBefore)
-	
-	elenaBalanceAfter := tm.getCurrentBalance(cassandraEndpoint, elenaAddress)
-	fmt.Printf("✅ Replacement successful: Elena received 1 ETH\n")
-	fmt.Printf("   Elena balance after: %s\n", elenaBalanceAfter)
-	fmt.Printf("⛽ Gas fee difference: +30 gwei for priority\n")
+	// Replacing needs both transactions signed at the same nonce, so
+	// Cassandra sends from a key we hold, funded by her node's coinbase
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	pool, err := tm.SenderPool(ctx, "cassandra", nil, 1, new(big.Int).Mul(oneEther, big.NewInt(2)))
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	cassandra := pool.senders[0]
 	
-	monitor.MarkScenarioExecuted(3)
-	fmt.Println("🔄 Scenario 3 marked as executed in monitoring system")
+	fmt.Fprintln(tm.out, "💰 Balances before scenario 3:")
+	drissBalanceBefore := tm.getCurrentBalance("cassandra", drissAddress.Hex())
+	elenaBalanceBefore := tm.getCurrentBalance("cassandra", elenaAddress.Hex())
+	fmt.Fprintf(tm.out, "   Driss: %s (+ 1000 BY tokens)\n", drissBalanceBefore)
+	fmt.Fprintf(tm.out, "   Elena: %s (+ 1000 BY tokens)\n", elenaBalanceBefore)
 	
-	return nil
-}
-
-func (tm *TransactionManager) getCurrentBalance(endpoint, address string) string {
-	balance := getBalance(endpoint, address)
-	if balance == "Error" || balance == "0x0" {
-		return "0.0000 ETH"
+	original, _, err := pool.Sign(TxRequest{To: &drissAddress, Value: oneEther, Gas: 21000, GasTipCap: fees.Tip, GasFeeCap: fees.MaxFee})
+	if err != nil {
+		return err
 	}
-	
-	if len(balance) > 2 {
-		if balanceInt, err := strconv.ParseInt(balance[2:], 16, 64); err == nil {
-			ethBalance := float64(balanceInt) / 1e18
-			return fmt.Sprintf("%.4f ETH", ethBalance)
-		}
+	fmt.Fprintln(tm.out, "\n💸 First transaction: Cassandra → Driss (1 ETH)")
+	fmt.Fprintf(tm.out, "📤 Cassandra → Driss\n")
+	fmt.Fprintf(tm.out, "   From: %s\n", cassandra.Address.Hex())
+	fmt.Fprintf(tm.out, "   To:   %s\n", drissAddress.Hex())
+	fmt.Fprintf(tm.out, "   Amount: 1 ETH\n")
+	fmt.Fprintf(tm.out, "   Fees: %s\n", fees)
+	fmt.Fprintf(tm.out, "   Nonce: %d\n", original.Nonce())
+	fmt.Fprintf(tm.out, "   Driss balance before: %s\n", drissBalanceBefore)
+	if err := pool.client.SendTransaction(ctx, original); err != nil {
+		slog.Error("Scenario 3 failed", "error", err)
+		return fmt.Errorf("failed to send the first transaction: %v", err)
 	}
+	fmt.Fprintf(tm.out, "   🔄 TX Hash: %s (pending in mempool)\n", original.Hash().Hex())
 	
-	return balance
-}
-
-func (tm *TransactionManager) getExpectedBalance(endpoint, address, nodeName string) string {
-	realBalance := getBalance(endpoint, address)
-	
-	if realBalance == "Error" || realBalance == "0x0" {
-		if nodeName == "alice" || nodeName == "bob" || nodeName == "cassandra" {
-			return "100.0000 ETH"
-		}
-		return "0.0000 ETH"
+	replacement, err := pool.SignWithNonce(cassandra, original.Nonce(), TxRequest{To: &elenaAddress, Value: oneEther, Gas: 21000, GasTipCap: replacementFees.Tip, GasFeeCap: replacementFees.MaxFee})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(tm.out, "\n🔄 Replacement transaction: Cassandra → Elena (1 ETH, higher fee)")
+	fmt.Fprintf(tm.out, "📤 Replacement with higher gas price:\n")
+	fmt.Fprintf(tm.out, "   From: %s\n", cassandra.Address.Hex())
+	fmt.Fprintf(tm.out, "   To: %s\n", elenaAddress.Hex())
+	fmt.Fprintf(tm.out, "   Amount: 1 ETH\n")
+	fmt.Fprintf(tm.out, "   Fees: %s (+%d%%)\n", replacementFees, settings.BumpPercent)
+	fmt.Fprintf(tm.out, "   Nonce: %d (same nonce)\n", replacement.Nonce())
+	
+	// The first transaction may already be sealed, leaving nothing to
+	// replace
+	err = pool.client.SendTransaction(ctx, replacement)
+	tm.recordTransaction(TransactionResult{Hash: replacement.Hash().Hex(), From: cassandra.Address.Hex(), To: elenaAddress.Hex(), Value: oneEther.String(), Err: err})
+	if err != nil {
+		slog.Error("Scenario 3 failed", "error", err)
+		return fmt.Errorf("failed to replace %s: %v", original.Hash().Hex(), err)
 	}
+	fmt.Fprintf(tm.out, "   ✅ TX Hash: %s\n", replacement.Hash().Hex())
+	tm.recordSent(state.TxSent("Cassandra", "Elena", oneEther, replacement.Hash().Hex()))
 	
-	balanceInt, success := new(big.Int).SetString(realBalance[2:], 16)
-	if !success {
-		return "0.0000 ETH"
+	if _, err := pool.WaitMined(ctx, []common.Hash{replacement.Hash()}); err != nil {
+		slog.Error("Scenario 3 failed", "error", err)
+		return err
+	}
+	if _, _, err := pool.client.TransactionByHash(ctx, original.Hash()); !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("transaction %s was not replaced", original.Hash().Hex())
 	}
 	
-	balanceFloat := new(big.Float).SetInt(balanceInt)
-	balanceFloat = balanceFloat.Quo(balanceFloat, big.NewFloat(1e18))
+	fmt.Fprintf(tm.out, "\n❌ First transaction cancelled (replaced by higher fee)\n")
+	fmt.Fprintf(tm.out, "   Reason: Same nonce (%d) with higher fees (max %s > %s gwei)\n", original.Nonce(), formatGwei(replacementFees.MaxFee), formatGwei(fees.MaxFee))
+	fmt.Fprintf(tm.out, "   Driss balance after: %s\n", tm.getCurrentBalance("cassandra", drissAddress.Hex()))
 	
-	if nodeName == "alice" && balanceFloat.Cmp(big.NewFloat(1000000000000)) > 0 {
-		txCount := tm.getTransactionCount(endpoint, address)
-		expectedBalance := 100.0 - (float64(txCount) * 0.1)
-		if expectedBalance < 0 {
-			expectedBalance = 0
-		}
-		return fmt.Sprintf("%.4f ETH", expectedBalance)
-	} else if nodeName == "bob" {
-		realBalance, _ := balanceFloat.Float64()
-		expectedBalance := realBalance + 100.0
-		return fmt.Sprintf("%.4f ETH", expectedBalance)
-	} else if balanceFloat.Cmp(big.NewFloat(1000000000000)) > 0 {
-		return "100.0000 ETH"
-	} else {
-		return balanceFloat.Text('f', 4) + " ETH"
-	}
-}
-
-func (tm *TransactionManager) getTransactionCount(endpoint, address string) uint64 {
-	data := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getTransactionCount","params":["%s","latest"],"id":1}`, address)
+	elenaBalanceAfter := tm.getCurrentBalance("cassandra", elenaAddress.Hex())
+	fmt.Fprintf(tm.out, "✅ Replacement successful: Elena received 1 ETH\n")
+	fmt.Fprintf(tm.out, "   Elena balance after: %s\n", elenaBalanceAfter)
+	fmt.Fprintf(tm.out, "⛽ Gas fee difference: +%s gwei tip for priority\n", formatGwei(new(big.Int).Sub(replacementFees.Tip, fees.Tip)))
 	
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", data,
-		endpoint)
+	monitor.MarkScenarioExecuted(3)
+	slog.Debug("Scenario marked as executed in monitoring system", "scenario", 3)
 	
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
+	return nil
+}
+
+// getCurrentBalance formats the balance of address as node sees it.
+func (tm *TransactionManager) getCurrentBalance(node, address string) string {
+	client, ok := tm.clients[node]
+	if !ok {
+		return "0.0000 ETH"
 	}
 	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if result, ok := response["result"].(string); ok {
-			if txCount, err := strconv.ParseUint(result[2:], 16, 64); err == nil {
-				return txCount
-			}
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return "0.0000 ETH"
 	}
-	
-	return 0
+	return fmt.Sprintf("%.4f ETH", state.WeiToETH(balance))
 }
 
 func (tm *TransactionManager) getETHFromWei(weiHex string) string {
@@ -455,54 +392,29 @@ func (tm *TransactionManager) getAmountFloatFromWei(weiHex string) float64 {
 }
 
 func (tm *TransactionManager) calculateBalanceForTransaction(address, nodeName string, afterTransaction bool) float64 {
-	state := tm.loadState()
+	current, err := state.Default().Load()
+	if err != nil {
+		slog.Warn("Failed to load persistent state", "error", err)
+		current = &state.State{}
+	}
 	
 	switch nodeName {
 	case "Alice":
-		return 100.0 - (float64(state.AliceTransactionsSent) * 0.1)
+		return 100.0 - (float64(current.TxSent("alice")) * 0.1)
 		
 	case "Bob":
-		return 100.0 + state.BobETHReceived
+		return 100.0 + state.WeiToETH(current.Received("bob"))
 		
 	case "Cassandra":
-		if state.CassandraTransactionsSent > 0 {
-			return 100.0 - (float64(state.CassandraTransactionsSent) * 0.05)
+		if sent := current.TxSent("cassandra"); sent > 0 {
+			return 100.0 - (float64(sent) * 0.05)
 		}
 		return 100.0
 		
 	case "Driss", "Elena":
-		if state.Scenario2Executed {
-			if state.Scenario3Executed && nodeName == "Elena" {
-				return 1.0
-			}
-			return 0.0
-		}
-		return 0.0
+		return state.WeiToETH(current.Received(nodeName))
 		
 	default:
 		return 0.0
 	}
 }
-
-func getBalance(endpoint, address string) string {
-	data := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["%s","latest"],"id":1}`, address)
-	
-	cmd := exec.Command("curl", "-s", "-X", "POST",
-		"-H", "Content-Type: application/json",
-		"--data", data,
-		endpoint)
-	
-	output, err := cmd.Output()
-	if err != nil {
-		return "Error"
-	}
-	
-	var response map[string]interface{}
-	if json.Unmarshal(output, &response) == nil {
-		if balance, ok := response["result"].(string); ok {
-			return balance
-		}
-	}
-	
-	return "0x0"
-}
\ No newline at end of file
@@ -0,0 +1,296 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchy/internal/monitor"
+	"benchy/internal/state"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrMissingPrerequisite = errors.New("missing prerequisite")
+	ErrPreconditionFailed  = errors.New("precondition not met")
+)
+
+// transferGas covers the gas of a plain transfer at the prices the
+// scenarios pay.
+var transferGas = new(big.Int).Mul(big.NewInt(21000), big.NewInt(50e9))
+
+// Scenario is a node of the scenario graph.
+type Scenario struct {
+	ID          string
+	Description string
+	// Requires lists the scenarios whose effects this one builds on.
+	Requires []string
+	// Nodes are the nodes the scenario sends through or checks.
+	Nodes []string
+
+	run func(tm *TransactionManager) error
+	// precondition checks that the chain can carry the scenario.
+	precondition func(tm *TransactionManager) error
+	// done reports whether the effects of the scenario already hold, so
+	// running it again would only repeat them. Scenarios without lasting
+	// effects leave it nil.
+	done func(tm *TransactionManager) (bool, error)
+}
+
+// graph lists the scenarios in an order where prerequisites come first.
+var graph = []*Scenario{
+	{
+		ID:          "0",
+		Description: "Network health baseline",
+		run:         (*TransactionManager).FullScenario0,
+	},
+	{
+		ID:          "1",
+		Description: "Alice sends 3×0.1 ETH to Bob",
+		Nodes:       []string{"alice"},
+		run:         (*TransactionManager).FullScenario1,
+		precondition: func(tm *TransactionManager) error {
+			need := new(big.Int).Mul(big.NewInt(3), new(big.Int).Add(big.NewInt(1e17), transferGas))
+			return tm.requireCoinbaseBalance("alice", need)
+		},
+		done: func(tm *TransactionManager) (bool, error) { return tm.scenarioDone(1) },
+	},
+	{
+		ID:          "2",
+		Description: "Cassandra deploys the BY token and distributes it",
+		Nodes:       []string{"cassandra"},
+		run:         (*TransactionManager).FullScenario2,
+		precondition: func(tm *TransactionManager) error {
+			return tm.requireCoinbaseBalance("cassandra", big.NewInt(5e16))
+		},
+		done: func(tm *TransactionManager) (bool, error) { return tm.scenarioDone(2) },
+	},
+	{
+		ID:          "3",
+		Description: "Cassandra replaces a transfer with a higher fee",
+		Requires:    []string{"2"},
+		Nodes:       []string{"cassandra"},
+		run:         (*TransactionManager).FullScenario3,
+		precondition: func(tm *TransactionManager) error {
+			// Driss and Elena hold the BY tokens scenario 2 sent them
+			if err := tm.requireToken("cassandra"); err != nil {
+				return err
+			}
+			// The coinbase funds the key that sends and replaces the transfer
+			return tm.requireCoinbaseBalance("cassandra", new(big.Int).Add(big.NewInt(2e18), transferGas))
+		},
+		done: func(tm *TransactionManager) (bool, error) { return tm.scenarioDone(3) },
+	},
+}
+
+// Scenarios returns the scenario graph, prerequisites first.
+func Scenarios() []*Scenario {
+	return graph
+}
+
+func lookupScenario(id string) (*Scenario, bool) {
+	for _, scenario := range graph {
+		if scenario.ID == id {
+			return scenario, true
+		}
+	}
+	return nil, false
+}
+
+// RunOptions control how Run walks the scenario graph.
+type RunOptions struct {
+	// WithDeps runs missing prerequisites first instead of failing.
+	WithDeps bool
+	// Force runs scenarios whose effects already hold again.
+	Force bool
+}
+
+// Run runs a scenario once its prerequisites are done and its preconditions
+// hold. A scenario that is already done is skipped unless opts.Force. The
+// results cover the prerequisites run first and end with the scenario.
+func (tm *TransactionManager) Run(id string, opts RunOptions) ([]*ScenarioResult, error) {
+	scenario, known := lookupScenario(id)
+	if !known {
+		result := &ScenarioResult{Scenario: id, StartedAt: time.Now(), FinishedAt: time.Now()}
+		result.Err = fmt.Errorf("%w: %s", ErrUnknownScenario, id)
+		return []*ScenarioResult{result}, result.Err
+	}
+
+	var results []*ScenarioResult
+	for _, required := range scenario.Requires {
+		prerequisite, _ := lookupScenario(required)
+		done, err := prerequisite.done(tm)
+		if err != nil {
+			return failed(results, id, err)
+		}
+		if done {
+			continue
+		}
+		if !opts.WithDeps {
+			return failed(results, id, fmt.Errorf("scenario %s: %w: scenario %s has not run (run it first or pass --with-deps)",
+				id, ErrMissingPrerequisite, required))
+		}
+		slog.Info("🔗 Running prerequisite first", "scenario", id, "prerequisite", required)
+		ran, err := tm.Run(required, RunOptions{WithDeps: true})
+		results = append(results, ran...)
+		if err != nil {
+			return failed(results, id, fmt.Errorf("scenario %s: prerequisite %s failed: %w", id, required, err))
+		}
+	}
+
+	result := tm.runNode(scenario, opts.Force)
+	return append(results, result), result.Err
+}
+
+// RunAll runs the whole graph in order, stopping at the first failure.
+func (tm *TransactionManager) RunAll(opts RunOptions) ([]*ScenarioResult, error) {
+	var results []*ScenarioResult
+	for _, scenario := range graph {
+		ran, err := tm.Run(scenario.ID, RunOptions{Force: opts.Force})
+		results = append(results, ran...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// failed appends the result of a scenario that could not start.
+func failed(results []*ScenarioResult, id string, err error) ([]*ScenarioResult, error) {
+	now := time.Now()
+	return append(results, &ScenarioResult{Scenario: id, StartedAt: now, FinishedAt: now, Err: err}), err
+}
+
+func (tm *TransactionManager) runNode(scenario *Scenario, force bool) *ScenarioResult {
+	result := &ScenarioResult{Scenario: scenario.ID, StartedAt: time.Now()}
+	tm.current = result
+	defer func() {
+		tm.current = nil
+		result.FinishedAt = time.Now()
+	}()
+
+	if unreachable := tm.unreachable(scenario.Nodes); len(unreachable) > 0 {
+		result.Err = fmt.Errorf("scenario %s: %w: %s", scenario.ID, ErrNodeOffline, strings.Join(unreachable, ", "))
+		return result
+	}
+
+	if scenario.done != nil && !force {
+		done, err := scenario.done(tm)
+		if err != nil {
+			result.Err = fmt.Errorf("scenario %s: %v", scenario.ID, err)
+			return result
+		}
+		if done {
+			slog.Info("⏭️  Scenario already done, skipping (use --force to run it again)", "scenario", scenario.ID)
+			result.Skipped = true
+			return result
+		}
+	}
+
+	if scenario.precondition != nil {
+		if err := scenario.precondition(tm); err != nil {
+			result.Err = fmt.Errorf("scenario %s: %w", scenario.ID, err)
+			return result
+		}
+	}
+
+	result.Err = scenario.run(tm)
+	return result
+}
+
+// scenarioDone reports whether the journal records the scenario and one of
+// the transactions it sent is still on chain. A relaunched chain drops them
+// even if the state file survived, and a completion recorded without
+// transactions, such as one migrated from the counters of version 1, is no
+// evidence at all.
+func (tm *TransactionManager) scenarioDone(scenario int) (bool, error) {
+	journal, err := state.Default().Load()
+	if err != nil {
+		return false, err
+	}
+	if !journal.ScenarioCompleted(scenario) {
+		return false, nil
+	}
+
+	sent := journal.Transactions(scenario)
+	if len(sent) == 0 {
+		return false, nil
+	}
+	for _, event := range sent {
+		client, ok := tm.clients[event.Node]
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(event.Hash))
+		cancel()
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return false, fmt.Errorf("failed to check transaction %s on %s: %v", event.Hash, event.Node, err)
+		}
+		if err == nil && receipt.Status == types.ReceiptStatusSuccessful {
+			return true, nil
+		}
+	}
+	slog.Warn("Recorded transactions are no longer on chain", "scenario", scenario, "transactions", len(sent))
+	return false, nil
+}
+
+// requireBalance checks that address holds at least need on node.
+func (tm *TransactionManager) requireBalance(node, address string, need *big.Int) error {
+	client, ok := tm.clients[node]
+	if !ok {
+		return fmt.Errorf("%w: %s is not selected", ErrNodeOffline, node)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the balance of %s on %s: %v", address, node, err)
+	}
+	if balance.Cmp(need) < 0 {
+		return fmt.Errorf("%w: %s holds %.4f ETH on %s, needs %.4f ETH",
+			ErrPreconditionFailed, address, state.WeiToETH(balance), node, state.WeiToETH(need))
+	}
+	return nil
+}
+
+// requireCoinbaseBalance checks that the coinbase of node holds at least
+// need.
+func (tm *TransactionManager) requireCoinbaseBalance(node string, need *big.Int) error {
+	coinbase, err := tm.coinbase(node)
+	if err != nil {
+		return err
+	}
+	return tm.requireBalance(node, coinbase.Hex(), need)
+}
+
+// coinbase returns the unlocked account node sends from.
+func (tm *TransactionManager) coinbase(node string) (common.Address, error) {
+	client, ok := tm.clients[node]
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: %s is not selected", ErrNodeOffline, node)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var coinbase common.Address
+	if err := client.Client().CallContext(ctx, &coinbase, "eth_coinbase"); err != nil {
+		return common.Address{}, fmt.Errorf("failed to read the coinbase of %s: %v", node, err)
+	}
+	return coinbase, nil
+}
+
+// recordSent journals a transaction sent by the running scenario.
+func (tm *TransactionManager) recordSent(event state.Event) {
+	if tm.current != nil {
+		event.Scenario, _ = strconv.Atoi(tm.current.Scenario)
+	}
+	monitor.RecordEvents(event)
+}
@@ -0,0 +1,192 @@
+package scenarios
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"benchy/internal/clique"
+	"benchy/internal/config"
+	"benchy/internal/fake"
+	"benchy/internal/state"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// newGraphManager fakes the default nodes, with the journal in a temporary
+// working directory.
+func newGraphManager(t *testing.T) (*TransactionManager, *fake.Network) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	network, err := fake.NewNetwork(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(network.Close)
+	tm, err := NewTransactionManager(network.Config.Endpoints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm, network
+}
+
+func journal(t *testing.T, events ...state.Event) {
+	t.Helper()
+	if err := state.Default().Append(events...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScenarioDoneNeedsTransactionsOnChain(t *testing.T) {
+	tm, network := newGraphManager(t)
+	bob := common.HexToAddress("0x742d35Cc6558FfC7876CFBbA534d3a05E5d8b4F1")
+
+	inferred := state.ScenarioCompleted(1)
+	inferred.Inferred = true
+	journal(t, inferred)
+	if done, err := tm.scenarioDone(1); err != nil || done {
+		t.Errorf("an inferred completion: got done %v (%v), want the scenario to run", done, err)
+	}
+
+	// A completion without transactions, as migrated from version 1
+	journal(t, state.ScenarioCompleted(1))
+	if done, err := tm.scenarioDone(1); err != nil || done {
+		t.Errorf("a completion without transactions: got done %v (%v), want the scenario to run", done, err)
+	}
+
+	// A transaction another chain mined
+	unknown := state.TxSent("alice", "bob", big.NewInt(1e17), common.Hash{1}.Hex())
+	unknown.Scenario = 1
+	journal(t, unknown)
+	if done, err := tm.scenarioDone(1); err != nil || done {
+		t.Errorf("a transaction missing from the chain: got done %v (%v), want the scenario to run", done, err)
+	}
+
+	hash, err := network.Chain.SendFromCoinbase(&bob, big.NewInt(1e17), 21000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined := state.TxSent("alice", "bob", big.NewInt(1e17), hash.Hex())
+	mined.Scenario = 1
+	journal(t, mined)
+	if done, err := tm.scenarioDone(1); err != nil || !done {
+		t.Errorf("a mined transaction: got done %v (%v), want the scenario skipped", done, err)
+	}
+}
+
+func TestInferredCompletionDoesNotSkip(t *testing.T) {
+	tm, network := newGraphManager(t)
+	inferred := state.ScenarioCompleted(2)
+	inferred.Inferred = true
+	journal(t, inferred)
+
+	head := network.Chain.BlockNumber()
+	results, err := tm.Run("2", RunOptions{})
+	if err != nil || len(results) != 1 || results[0].Skipped {
+		t.Fatalf("got results %+v (%v), want scenario 2 run", results, err)
+	}
+	if network.Chain.BlockNumber() == head {
+		t.Error("scenario 2 sent no transaction")
+	}
+}
+
+func TestScenario2DeploysToken(t *testing.T) {
+	tm, _ := newGraphManager(t)
+	if _, err := tm.Run("2", RunOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := state.Default().Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := common.HexToAddress(journal.Contract(2))
+	if err := tm.requireToken("cassandra"); err != nil {
+		t.Fatalf("the token %s: %v", token.Hex(), err)
+	}
+	cassandra, err := tm.coinbase("cassandra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	holders := map[string]common.Address{
+		"cassandra": cassandra,
+		"driss":     common.HexToAddress("0x9876543210fedcba9876543210fedcba98765431"),
+		"elena":     common.HexToAddress("0x9876543210fedcba9876543210fedcba98765432"),
+	}
+	for name, holder := range holders {
+		balance, err := tm.tokenBalance(t.Context(), "cassandra", token, holder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Cmp(byTokens(1000)) != 0 {
+			t.Errorf("%s holds %s, want 1000 BY", name, formatBY(balance))
+		}
+	}
+	if done, err := tm.scenarioDone(2); err != nil || !done {
+		t.Errorf("got done %v (%v), want scenario 2 done", done, err)
+	}
+}
+
+func TestScenario3RequiresToken(t *testing.T) {
+	tm, _ := newGraphManager(t)
+
+	results, err := tm.Run("3", RunOptions{})
+	if !errors.Is(err, ErrMissingPrerequisite) || len(results) != 1 {
+		t.Fatalf("got %v and results %+v, want ErrMissingPrerequisite", err, results)
+	}
+
+	// A token recorded on another chain fails the precondition
+	deployed := state.ContractDeployed("cassandra", common.Address{1}.Hex(), common.Hash{1}.Hex())
+	deployed.Scenario = 2
+	journal(t, deployed)
+	if err := tm.requireToken("cassandra"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("got %v for a token without code, want ErrPreconditionFailed", err)
+	}
+
+	// The replacement of scenario 3 needs a mempool the fake chain lacks,
+	// so only its prerequisite is checked
+	results, _ = tm.Run("3", RunOptions{WithDeps: true})
+	if len(results) != 2 || results[0].Scenario != "2" || results[0].Err != nil {
+		t.Fatalf("got results %+v, want scenario 2 run first", results)
+	}
+	if err := tm.requireToken("cassandra"); err != nil {
+		t.Errorf("the prerequisite left no token: %v", err)
+	}
+}
+
+func TestBaselineNotClique(t *testing.T) {
+	tm, network := newGraphManager(t)
+	network.Chain.SetClique(false)
+	tm.current = &ScenarioResult{Scenario: "0"}
+
+	tm.checkSealing(t.Context(), []string{"alice"}, nil)
+	checks := tm.current.Checks
+	if len(checks) == 0 || checks[0].Name != "validators sealed" || checks[0].Passed ||
+		!strings.Contains(checks[0].Detail, clique.ErrNotClique.Error()) {
+		t.Errorf("got checks %+v, want validators sealed failed as not Clique", checks)
+	}
+}
+
+func TestGenesisAllocOnPrunedNodes(t *testing.T) {
+	tm, network := newGraphManager(t)
+	alice := network.Config.Nodes[0].Address
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{common.HexToAddress(alice): {Balance: fake.AccountBalance}}}
+	nodes := []string{"alice", "bob"}
+	network.Chain.Mine(1)
+
+	tm.current = &ScenarioResult{Scenario: "0"}
+	tm.checkGenesisAlloc(t.Context(), nodes, genesis, nil)
+	if checks := tm.current.Checks; len(checks) != 1 || !checks[0].Passed {
+		t.Fatalf("got checks %+v, want the genesis alloc to match", checks)
+	}
+
+	// A pruned node cannot read the genesis state, which is not a mismatch
+	network.Chain.SetPruned(true)
+	tm.current = &ScenarioResult{Scenario: "0"}
+	tm.checkGenesisAlloc(t.Context(), nodes, genesis, nil)
+	if checks := tm.current.Checks; len(checks) != 1 || checks[0].Passed || !checks[0].Skipped {
+		t.Errorf("got checks %+v, want the genesis alloc skipped", checks)
+	}
+}
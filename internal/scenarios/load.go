@@ -0,0 +1,532 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"benchy/internal/monitor"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// loadSenderBalance is what each load sender is topped up to: 100 ETH.
+var loadSenderBalance = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+
+// loadHeadPoll is how often the load generator checks for new blocks.
+const loadHeadPoll = 100 * time.Millisecond
+
+// LoadPlan describes a load run: transactions of the workloads of Mix sent
+// through Node at Rate per second for Duration, priced by Strategies in
+// turn.
+type LoadPlan struct {
+	Node     string
+	Rate     float64
+	Duration time.Duration
+	Mix      WorkloadMix
+	// Workloads size the storage and keccak workloads
+	Workloads WorkloadSettings
+	// Senders is the number of accounts per strategy: each strategy sends
+	// from its own, so a stuck transaction only delays its own strategy
+	Senders    int
+	Strategies []FeeStrategy
+	Fees       FeeSettings
+	// Timeout bounds the wait for the last transactions after Duration.
+	Timeout time.Duration
+}
+
+// LoadTransaction is one transaction of a load run, followed through its
+// replacements.
+type LoadTransaction struct {
+	Workload Workload
+	Strategy FeeStrategy
+	// Hash and Fees are those of the last submission, or of the one
+	// included.
+	Hash     common.Hash
+	Fees     Fees
+	Bumps    int
+	SentAt   time.Time
+	Included bool
+	// Latency runs from the first submission to the block including it.
+	Latency           time.Duration
+	Block             uint64
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	// Status is the receipt status of the included transaction.
+	Status uint64
+}
+
+// Reverted reports whether the transaction was included but failed.
+func (tx *LoadTransaction) Reverted() bool {
+	return tx.Included && tx.Status != types.ReceiptStatusSuccessful
+}
+
+type LoadResult struct {
+	Plan       LoadPlan
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Transactions are those the node accepted.
+	Transactions []*LoadTransaction
+	Rejected     []error
+	// Contracts are the workload contracts deployed for the run.
+	Contracts map[Workload]common.Address
+	// Blocks are the fees of the blocks mined during the run.
+	Blocks []monitor.BlockFees
+}
+
+// LoadGroup summarizes the transactions sharing a fee strategy or a
+// workload.
+type LoadGroup struct {
+	Name string
+	Sent int
+	// Included counts the successful transactions; Reverted those included
+	// that failed, which the latency and gas figures leave out.
+	Included int
+	Reverted int
+	Bumps    int
+	Latency  monitor.LatencySummary
+	// AvgGasUsed and AvgGasPrice are over the included transactions; the
+	// gas price is the effective one, in gwei.
+	AvgGasUsed  float64
+	AvgGasPrice float64
+}
+
+// pendingLoad is a transaction not yet seen in a block.
+type pendingLoad struct {
+	tx      *LoadTransaction
+	pool    *SenderPool
+	sender  *Sender
+	nonce   uint64
+	request TxRequest
+	// submittedAt is the head when it was last (re)submitted
+	submittedAt uint64
+	hashes      []common.Hash
+}
+
+// loadRun is the state shared by the sender and the block follower.
+type loadRun struct {
+	tm     *TransactionManager
+	plan   LoadPlan
+	client *ethclient.Client
+	pools  map[FeeStrategy]*SenderPool
+	// contracts are the deployed workload contracts
+	contracts map[Workload]common.Address
+	mu        sync.Mutex
+	pending   map[common.Hash]*pendingLoad
+	head      uint64
+	oracle    Fees
+	// done is closed when the block follower returns
+	done chan struct{}
+}
+
+// RunLoad runs plan, then reads the fee market of the blocks it spanned.
+// It returns what was measured so far when ctx is cancelled.
+func (tm *TransactionManager) RunLoad(ctx context.Context, plan LoadPlan) (*LoadResult, error) {
+	if plan.Rate <= 0 || len(plan.Strategies) == 0 || len(plan.Mix) == 0 {
+		return nil, fmt.Errorf("a load needs a positive rate, a fee strategy and a workload")
+	}
+	if plan.Fees.Tip.Cmp(plan.Fees.MaxFee) > 0 {
+		return nil, fmt.Errorf("the fixed tip (%s gwei) exceeds the max fee (%s gwei)", formatGwei(plan.Fees.Tip), formatGwei(plan.Fees.MaxFee))
+	}
+	if unreachable := tm.unreachable([]string{plan.Node}); len(unreachable) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNodeOffline, strings.Join(unreachable, ", "))
+	}
+
+	run := &loadRun{
+		tm:      tm,
+		plan:    plan,
+		client:  tm.clients[plan.Node],
+		pools:   make(map[FeeStrategy]*SenderPool),
+		pending: make(map[common.Hash]*pendingLoad),
+		done:    make(chan struct{}),
+	}
+	for _, strategy := range plan.Strategies {
+		if _, ok := run.pools[strategy]; ok {
+			continue
+		}
+		pool, err := tm.senderPool(ctx, plan.Node, strategy, nil, plan.Senders, loadSenderBalance)
+		if err != nil {
+			return nil, err
+		}
+		defer pool.Close()
+		run.pools[strategy] = pool
+	}
+
+	// Every sender may send ERC20 transfers
+	var holders []common.Address
+	for _, strategy := range plan.Strategies {
+		for _, sender := range run.pools[strategy].senders {
+			holders = append(holders, sender.Address)
+		}
+	}
+	var err error
+	if run.contracts, err = DeployWorkloads(ctx, run.pools[plan.Strategies[0]], plan.Mix, holders); err != nil {
+		return nil, err
+	}
+
+	if run.head, err = run.client.BlockNumber(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read the head of %s: %v", plan.Node, err)
+	}
+	if run.oracle, err = SuggestFees(ctx, run.client, FeeOracle, plan.Fees); err != nil {
+		return nil, err
+	}
+	firstBlock := run.head + 1
+
+	result := &LoadResult{Plan: plan, StartedAt: time.Now(), Contracts: run.contracts}
+	followCtx, stopFollowing := context.WithCancel(context.Background())
+	defer stopFollowing()
+	go run.follow(followCtx)
+
+	run.send(ctx, result)
+	run.wait(ctx, plan.Timeout)
+	stopFollowing()
+	<-run.done
+	result.FinishedAt = time.Now()
+
+	run.mu.Lock()
+	lastBlock := run.head
+	run.mu.Unlock()
+	if lastBlock >= firstBlock {
+		feeCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if result.Blocks, err = monitor.AnalyzeFees(feeCtx, run.client, firstBlock, lastBlock); err != nil {
+			slog.Warn("Cannot read the fee market of the run", "error", err)
+		}
+	}
+	return result, nil
+}
+
+// send submits transactions at the plan's rate until its duration elapses.
+func (run *loadRun) send(ctx context.Context, result *LoadResult) {
+	interval := time.Duration(float64(time.Second) / run.plan.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.After(run.plan.Duration)
+	picker := newWorkloadPicker(run.plan.Mix)
+	for i := 0; ; i++ {
+		strategy := run.plan.Strategies[i%len(run.plan.Strategies)]
+		workload := picker.next()
+		pool := run.pools[strategy]
+		recipient := pool.senders[i%len(pool.senders)].Address
+		fees := Fees{Tip: run.plan.Fees.Tip, MaxFee: run.plan.Fees.MaxFee}
+		// follow moves the head and the oracle while we send
+		run.mu.Lock()
+		head := run.head
+		if strategy != FeeFixed {
+			fees = run.oracle
+		}
+		run.mu.Unlock()
+		request := workloadRequest(workload, i, run.contracts, recipient, run.plan.Workloads)
+		request.GasTipCap, request.GasFeeCap = fees.Tip, fees.MaxFee
+		tx, sender, err := pool.Sign(request)
+		if err != nil {
+			result.Rejected = append(result.Rejected, err)
+			return
+		}
+
+		// The transaction is pending before it is sent, so a block
+		// including it at once is not missed by the follower
+		loadTx := &LoadTransaction{Workload: workload, Strategy: strategy, Hash: tx.Hash(), Fees: fees, SentAt: time.Now()}
+		run.mu.Lock()
+		run.pending[tx.Hash()] = &pendingLoad{
+			tx: loadTx, pool: pool, sender: sender, nonce: tx.Nonce(), request: request,
+			submittedAt: head, hashes: []common.Hash{tx.Hash()},
+		}
+		run.mu.Unlock()
+		if err := run.client.SendTransaction(ctx, tx); err != nil {
+			run.mu.Lock()
+			delete(run.pending, tx.Hash())
+			run.mu.Unlock()
+			pool.resync(ctx, []*Sender{sender})
+			slog.Debug("Transaction rejected", "node", run.plan.Node, "error", err)
+			result.Rejected = append(result.Rejected, err)
+		} else {
+			result.Transactions = append(result.Transactions, loadTx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// wait lets the pending transactions land for up to timeout.
+func (run *loadRun) wait(ctx context.Context, timeout time.Duration) {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(loadHeadPoll)
+	defer ticker.Stop()
+	for {
+		run.mu.Lock()
+		left := len(run.pending)
+		run.mu.Unlock()
+		if left == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			slog.Warn("Transactions still pending at the end of the load", "count", run.distinctPending())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (run *loadRun) distinctPending() int {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	distinct := make(map[*pendingLoad]bool)
+	for _, entry := range run.pending {
+		distinct[entry] = true
+	}
+	return len(distinct)
+}
+
+// follow processes each new block until ctx is cancelled: it marks the
+// transactions it includes, refreshes the oracle fees and bumps the
+// transactions left behind.
+func (run *loadRun) follow(ctx context.Context) {
+	defer close(run.done)
+	client := run.client
+	ticker := time.NewTicker(loadHeadPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			continue
+		}
+		run.mu.Lock()
+		next := run.head + 1
+		run.mu.Unlock()
+		for number := next; number <= head; number++ {
+			if err := run.processBlock(ctx, number); err != nil {
+				slog.Debug("Cannot read block", "block", number, "error", err)
+				break
+			}
+		}
+	}
+}
+
+func (run *loadRun) processBlock(ctx context.Context, number uint64) error {
+	client := run.client
+	block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return err
+	}
+	seenAt := time.Now()
+
+	// The receipts are read before the transactions leave the pending set,
+	// so the run cannot end while the last block's are still missing
+	run.mu.Lock()
+	var included []*types.Transaction
+	for _, tx := range block.Transactions() {
+		if _, ok := run.pending[tx.Hash()]; ok {
+			included = append(included, tx)
+		}
+	}
+	run.mu.Unlock()
+	receipts := make(map[common.Hash]*types.Receipt)
+	if len(included) > 0 {
+		blockReceipts, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+		if err != nil {
+			return err
+		}
+		for _, receipt := range blockReceipts {
+			receipts[receipt.TxHash] = receipt
+		}
+	}
+
+	run.mu.Lock()
+	for _, tx := range included {
+		entry, ok := run.pending[tx.Hash()]
+		if !ok {
+			continue
+		}
+		for _, hash := range entry.hashes {
+			delete(run.pending, hash)
+		}
+		entry.tx.Included = true
+		entry.tx.Hash = tx.Hash()
+		entry.tx.Block = number
+		entry.tx.Latency = seenAt.Sub(entry.tx.SentAt)
+		if receipt, ok := receipts[tx.Hash()]; ok {
+			entry.tx.GasUsed = receipt.GasUsed
+			entry.tx.EffectiveGasPrice = receipt.EffectiveGasPrice
+			entry.tx.Status = receipt.Status
+		}
+	}
+	run.head = number
+	run.mu.Unlock()
+
+	if fees, err := SuggestFees(ctx, client, FeeOracle, run.plan.Fees); err == nil {
+		run.mu.Lock()
+		run.oracle = fees
+		run.mu.Unlock()
+	}
+	run.bump(ctx, number)
+	return nil
+}
+
+// bump replaces the bump strategy's transactions pending for too long.
+func (run *loadRun) bump(ctx context.Context, head uint64) {
+	settings := run.plan.Fees
+	run.mu.Lock()
+	var stale []*pendingLoad
+	for hash, entry := range run.pending {
+		if entry.tx.Strategy == FeeBump && hash == entry.tx.Hash &&
+			head-entry.submittedAt >= settings.BumpAfter && entry.tx.Bumps < settings.MaxBumps {
+			stale = append(stale, entry)
+		}
+	}
+	run.mu.Unlock()
+
+	for _, entry := range stale {
+		fees := entry.tx.Fees.Bump(settings.BumpPercent)
+		request := entry.request
+		request.GasTipCap, request.GasFeeCap = fees.Tip, fees.MaxFee
+		tx, err := entry.pool.SignWithNonce(entry.sender, entry.nonce, request)
+		if err != nil {
+			continue
+		}
+		run.mu.Lock()
+		if entry.tx.Included {
+			run.mu.Unlock()
+			continue
+		}
+		entry.hashes = append(entry.hashes, tx.Hash())
+		run.pending[tx.Hash()] = entry
+		run.mu.Unlock()
+		err = run.client.SendTransaction(ctx, tx)
+
+		run.mu.Lock()
+		if err != nil {
+			// The original may have been included meanwhile
+			slog.Debug("Replacement rejected", "nonce", entry.nonce, "error", err)
+			delete(run.pending, tx.Hash())
+			entry.hashes = entry.hashes[:len(entry.hashes)-1]
+		} else if !entry.tx.Included || entry.tx.Hash == tx.Hash() {
+			entry.tx.Hash = tx.Hash()
+			entry.tx.Fees = fees
+			entry.tx.Bumps++
+			entry.submittedAt = head
+			entry.request = request
+		}
+		run.mu.Unlock()
+	}
+}
+
+// ByStrategy summarizes the transactions per fee strategy, in plan order.
+func (r *LoadResult) ByStrategy() []LoadGroup {
+	var groups []LoadGroup
+	for _, strategy := range r.Plan.Strategies {
+		if containsGroup(groups, string(strategy)) {
+			continue
+		}
+		groups = append(groups, summarizeLoad(string(strategy), r.Transactions, func(tx *LoadTransaction) bool {
+			return tx.Strategy == strategy
+		}))
+	}
+	return groups
+}
+
+// ByWorkload summarizes the transactions per workload, in mix order.
+func (r *LoadResult) ByWorkload() []LoadGroup {
+	var groups []LoadGroup
+	for _, share := range r.Plan.Mix {
+		workload := share.Workload
+		if containsGroup(groups, string(workload)) {
+			continue
+		}
+		groups = append(groups, summarizeLoad(string(workload), r.Transactions, func(tx *LoadTransaction) bool {
+			return tx.Workload == workload
+		}))
+	}
+	return groups
+}
+
+func containsGroup(groups []LoadGroup, name string) bool {
+	for _, group := range groups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func summarizeLoad(name string, transactions []*LoadTransaction, match func(*LoadTransaction) bool) LoadGroup {
+	group := LoadGroup{Name: name}
+	var latencies []time.Duration
+	gasPrice := new(big.Int)
+	for _, tx := range transactions {
+		if !match(tx) {
+			continue
+		}
+		group.Sent++
+		group.Bumps += tx.Bumps
+		if tx.Reverted() {
+			group.Reverted++
+			continue
+		}
+		if !tx.Included {
+			continue
+		}
+		group.Included++
+		latencies = append(latencies, tx.Latency)
+		group.AvgGasUsed += float64(tx.GasUsed)
+		if tx.EffectiveGasPrice != nil {
+			gasPrice.Add(gasPrice, tx.EffectiveGasPrice)
+		}
+	}
+	group.Latency = monitor.SummarizeLatencies(latencies, group.Sent-group.Included-group.Reverted)
+	if group.Included > 0 {
+		group.AvgGasUsed /= float64(group.Included)
+		group.AvgGasPrice = monitor.Gwei(gasPrice) / float64(group.Included)
+	}
+	return group
+}
+
+// DisplayLoad prints the inclusion latency and gas per fee strategy and per
+// workload, then the fee market of the run.
+func DisplayLoad(result *LoadResult) {
+	fmt.Printf("\n🚚 Load through %s: %d sent, %d rejected in %s\n", result.Plan.Node,
+		len(result.Transactions), len(result.Rejected), result.FinishedAt.Sub(result.StartedAt).Round(time.Second))
+	displayLoadGroups("Strategy", result.ByStrategy())
+	if len(result.Plan.Mix) > 1 || result.Plan.Mix[0].Workload != WorkloadTransfer {
+		fmt.Println()
+		displayLoadGroups("Workload", result.ByWorkload())
+	}
+	monitor.DisplayFees(result.Blocks)
+}
+
+func displayLoadGroups(title string, groups []LoadGroup) {
+	fmt.Printf("%-10s %-6s %-9s %-9s %-6s %-44s %-10s %-12s\n", title, "Sent", "Included", "Reverted", "Bumps",
+		"Inclusion ms (min / p50 / avg / p95 / max)", "Avg gas", "Gas price")
+	fmt.Println("-" + strings.Repeat("-", 114))
+	for _, group := range groups {
+		latency := "n/a"
+		if group.Latency.Samples > 0 {
+			latency = fmt.Sprintf("%.0f / %.0f / %.0f / %.0f / %.0f", group.Latency.Min, group.Latency.P50,
+				group.Latency.Avg, group.Latency.P95, group.Latency.Max)
+		}
+		fmt.Printf("%-10s %-6d %-9d %-9d %-6d %-44s %-10.0f %.3f gwei\n", group.Name, group.Sent, group.Included,
+			group.Reverted, group.Bumps, latency, group.AvgGasUsed, group.AvgGasPrice)
+	}
+}
@@ -0,0 +1,289 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"benchy/internal/monitor"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// propagationSenders are the keys the measured transactions rotate over.
+const propagationSenders = 4
+
+// propagationSenderBalance covers the gas of thousands of transfers: 10 ETH.
+var propagationSenderBalance = new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+
+// PropagationPlan describes a propagation measurement: Count transfers
+// submitted every Interval, each to the next of Origins, while every other
+// node is polled until it holds the transaction in its pool and in a block.
+type PropagationPlan struct {
+	// Funder is the node whose unlocked coinbase funds the senders.
+	Funder   string
+	Origins  []string
+	Count    int
+	Interval time.Duration
+	Poll     time.Duration
+	// Timeout bounds how long a node is polled for one transaction.
+	Timeout time.Duration
+	// Clients maps node names to their client kind, for the per-client view.
+	Clients map[string]string
+}
+
+// PropagationSample is one transaction observed by one node. Pool and Block
+// are measured from the moment the origin accepted the transaction.
+type PropagationSample struct {
+	Hash     common.Hash
+	From, To string
+	// InPool is set when the node returned the transaction before it was
+	// mined. A node that first saw it in a block has no pool latency.
+	InPool   bool
+	Pool     time.Duration
+	Included bool
+	Block    time.Duration
+}
+
+// PropagationPair summarizes the transactions sent through From as seen by
+// To. To equals From for the origin's own inclusion latency.
+type PropagationPair struct {
+	From, To             string
+	FromClient, ToClient string
+	Pool                 monitor.LatencySummary
+	Block                monitor.LatencySummary
+}
+
+type PropagationResult struct {
+	Plan       PropagationPlan
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Sent counts the transactions the origins accepted.
+	Sent    int
+	Samples []PropagationSample
+	// Errors are the submissions the origins rejected.
+	Errors []error
+}
+
+// MeasurePropagation runs plan. It returns what was measured so far when
+// ctx is cancelled.
+func (tm *TransactionManager) MeasurePropagation(ctx context.Context, plan PropagationPlan) (*PropagationResult, error) {
+	for _, origin := range plan.Origins {
+		if _, ok := tm.clients[origin]; !ok {
+			return nil, fmt.Errorf("%w: %s is not selected", ErrNodeOffline, origin)
+		}
+	}
+	if len(plan.Origins) == 0 || len(tm.clients) < 2 {
+		return nil, fmt.Errorf("propagation needs an origin and at least two nodes")
+	}
+	if unreachable := tm.unreachable(tm.nodeNames()); len(unreachable) > 0 {
+		return nil, fmt.Errorf("%w: %s (exclude them with --nodes)", ErrNodeOffline, strings.Join(unreachable, ", "))
+	}
+
+	pool, err := tm.SenderPool(ctx, plan.Funder, nil, propagationSenders, propagationSenderBalance)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	result := &PropagationResult{Plan: plan, StartedAt: time.Now()}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < plan.Count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(plan.Interval):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		origin := plan.Origins[i%len(plan.Origins)]
+		tx, sender, err := pool.Sign(TxRequest{To: &pool.senders[0].Address, Gas: 21000})
+		if err != nil {
+			// The observers of earlier transactions still use the pool
+			wg.Wait()
+			return nil, err
+		}
+		if err := tm.clients[origin].SendTransaction(ctx, tx); err != nil {
+			pool.resync(ctx, []*Sender{sender})
+			slog.Warn("Transaction rejected", "node", origin, "error", err)
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %v", origin, err))
+			continue
+		}
+		sentAt := time.Now()
+		result.Sent++
+		slog.Debug("Transaction sent", "node", origin, "hash", tx.Hash().Hex())
+
+		for name := range tm.clients {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				sample := tm.observe(ctx, name, tx.Hash(), sentAt, plan)
+				sample.From = origin
+				mu.Lock()
+				result.Samples = append(result.Samples, sample)
+				mu.Unlock()
+			}(name)
+		}
+	}
+
+	wg.Wait()
+	result.FinishedAt = time.Now()
+	return result, nil
+}
+
+// observe polls node until it holds hash in a block or the plan times out.
+func (tm *TransactionManager) observe(ctx context.Context, node string, hash common.Hash, sentAt time.Time, plan PropagationPlan) PropagationSample {
+	sample := PropagationSample{Hash: hash, To: node}
+	ctx, cancel := context.WithTimeout(ctx, plan.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(plan.Poll)
+	defer ticker.Stop()
+	seen := false
+	for {
+		_, pending, err := tm.clients[node].TransactionByHash(ctx, hash)
+		now := time.Now()
+		switch {
+		case err == nil && pending && !seen:
+			seen = true
+			sample.InPool = true
+			sample.Pool = now.Sub(sentAt)
+		case err == nil && !pending:
+			sample.Included = true
+			sample.Block = now.Sub(sentAt)
+			return sample
+		case err != nil && !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil:
+			slog.Debug("Polling transaction failed", "node", node, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return sample
+		case <-ticker.C:
+		}
+	}
+}
+
+// Pairs summarizes the samples per origin and observing node, in node order.
+func (r *PropagationResult) Pairs() []PropagationPair {
+	var pairs []PropagationPair
+	for _, from := range monitor.NodeOrder {
+		for _, to := range monitor.NodeOrder {
+			pool, block, found := r.latencies(func(s PropagationSample) bool { return s.From == from && s.To == to })
+			if !found {
+				continue
+			}
+			pairs = append(pairs, PropagationPair{
+				From: from, To: to,
+				FromClient: r.Plan.Clients[from], ToClient: r.Plan.Clients[to],
+				Pool: pool, Block: block,
+			})
+		}
+	}
+	return pairs
+}
+
+// ClientPairs summarizes the samples between distinct nodes per client kind
+// of the origin and observing node, such as Geth → Nethermind.
+func (r *PropagationResult) ClientPairs() []PropagationPair {
+	var kinds []string
+	known := make(map[string]bool)
+	for _, name := range monitor.NodeOrder {
+		if kind, ok := r.Plan.Clients[name]; ok && !known[kind] {
+			known[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+
+	var pairs []PropagationPair
+	for _, from := range kinds {
+		for _, to := range kinds {
+			pool, block, found := r.latencies(func(s PropagationSample) bool {
+				return s.From != s.To && r.Plan.Clients[s.From] == from && r.Plan.Clients[s.To] == to
+			})
+			if found {
+				pairs = append(pairs, PropagationPair{FromClient: from, ToClient: to, Pool: pool, Block: block})
+			}
+		}
+	}
+	return pairs
+}
+
+// latencies summarizes the pool and block latencies of the matching samples.
+func (r *PropagationResult) latencies(match func(PropagationSample) bool) (pool, block monitor.LatencySummary, found bool) {
+	var poolTimes, blockTimes []time.Duration
+	poolMissed, blockMissed := 0, 0
+	for _, sample := range r.Samples {
+		if !match(sample) {
+			continue
+		}
+		found = true
+		if sample.InPool {
+			poolTimes = append(poolTimes, sample.Pool)
+		} else if !sample.Included {
+			poolMissed++
+		}
+		if sample.Included {
+			blockTimes = append(blockTimes, sample.Block)
+		} else {
+			blockMissed++
+		}
+	}
+	return monitor.SummarizeLatencies(poolTimes, poolMissed), monitor.SummarizeLatencies(blockTimes, blockMissed), found
+}
+
+// nodeNames lists the selected nodes.
+func (tm *TransactionManager) nodeNames() []string {
+	names := make([]string, 0, len(tm.clients))
+	for name := range tm.clients {
+		names = append(names, name)
+	}
+	return names
+}
+
+// DisplayPropagation prints the latency distributions of result.
+func DisplayPropagation(result *PropagationResult) {
+	fmt.Printf("\n📡 Transaction propagation: %d sent, %d rejected, in %s\n",
+		result.Sent, len(result.Errors), result.FinishedAt.Sub(result.StartedAt).Round(time.Second))
+	fmt.Println("   Latency from acceptance by the origin, ms (min / p50 / avg / p95 / max)")
+
+	fmt.Println("\n   Per node pair:")
+	for _, pair := range result.Pairs() {
+		label := fmt.Sprintf("%s (%s) → %s (%s)", pair.From, pair.FromClient, pair.To, pair.ToClient)
+		if pair.From == pair.To {
+			label = fmt.Sprintf("%s (%s), own block", pair.From, pair.FromClient)
+			fmt.Printf("   %-40s %s\n", label, formatLatency("block", pair.Block))
+			continue
+		}
+		fmt.Printf("   %-40s %s   %s\n", label, formatLatency("pool", pair.Pool), formatLatency("block", pair.Block))
+	}
+
+	fmt.Println("\n   Per client pair:")
+	for _, pair := range result.ClientPairs() {
+		label := fmt.Sprintf("%s → %s", pair.FromClient, pair.ToClient)
+		fmt.Printf("   %-40s %s   %s\n", label, formatLatency("pool", pair.Pool), formatLatency("block", pair.Block))
+	}
+}
+
+func formatLatency(label string, summary monitor.LatencySummary) string {
+	text := fmt.Sprintf("%s n/a", label)
+	if summary.Samples > 0 {
+		text = fmt.Sprintf("%s %.0f / %.0f / %.0f / %.0f / %.0f", label,
+			summary.Min, summary.P50, summary.Avg, summary.P95, summary.Max)
+	}
+	if summary.Missed > 0 {
+		text += fmt.Sprintf(" (%d missed)", summary.Missed)
+	}
+	return text
+}
@@ -0,0 +1,70 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnknownScenario = errors.New("unknown scenario")
+	ErrNodeOffline     = errors.New("node is offline")
+)
+
+type TransactionResult struct {
+	Hash  string
+	From  string
+	To    string
+	Value string
+	Err   error
+}
+
+// ScenarioResult records what a scenario run actually did on chain.
+type ScenarioResult struct {
+	Scenario     string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Transactions []TransactionResult
+	// Checks are the verifications the scenario made, if it makes any.
+	Checks []Check
+	// Skipped is set when the effects of the scenario already held.
+	Skipped bool
+	Err     error
+}
+
+func (r *ScenarioResult) Success() bool {
+	return r.Err == nil
+}
+
+// RunScenario runs a scenario by number and returns its result, failing if
+// its prerequisites have not run. The error is also stored in the result so
+// callers can report it either way.
+func (tm *TransactionManager) RunScenario(scenario string) (*ScenarioResult, error) {
+	results, err := tm.Run(scenario, RunOptions{})
+	return results[len(results)-1], err
+}
+
+// unreachable returns the nodes that are not configured or do not answer.
+func (tm *TransactionManager) unreachable(nodes []string) []string {
+	var missing []string
+	for _, name := range nodes {
+		client, ok := tm.clients[name]
+		if !ok {
+			missing = append(missing, name+" (not selected)")
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		_, err := client.BlockNumber(ctx)
+		cancel()
+		if err != nil {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func (tm *TransactionManager) recordTransaction(tx TransactionResult) {
+	if tm.current != nil {
+		tm.current.Transactions = append(tm.current.Transactions, tx)
+	}
+}
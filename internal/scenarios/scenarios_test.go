@@ -0,0 +1,238 @@
+package scenarios_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"benchy/internal/config"
+	"benchy/internal/fake"
+	"benchy/internal/scenarios"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// newTestManager fakes the default nodes and sends through them. The
+// persistent state is written to a temporary working directory.
+func newTestManager(t *testing.T) (*scenarios.TransactionManager, *fake.Network) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	network, err := fake.NewNetwork(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(network.Close)
+	tm, err := scenarios.NewTransactionManager(network.Config.Endpoints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm, network
+}
+
+func newAddresses(t *testing.T, count int) []common.Address {
+	t.Helper()
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return addresses
+}
+
+func balanceOf(t *testing.T, node *fake.Node, address common.Address) *big.Int {
+	t.Helper()
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	balance, err := client.BalanceAt(context.Background(), address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance
+}
+
+func TestFaucet(t *testing.T) {
+	tm, network := newTestManager(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	recipients := newAddresses(t, 3)
+	amount := big.NewInt(1e17)
+	result, err := tm.Faucet(ctx, "bob", recipients, amount, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.From != network.Chain.Coinbase() || len(result.Transfers) != len(recipients) {
+		t.Fatalf("got %d transfers from %s", len(result.Transfers), result.From.Hex())
+	}
+	for i, transfer := range result.Transfers {
+		if transfer.To != recipients[i] || transfer.Block == 0 {
+			t.Errorf("transfer %d: got %s in block #%d", i, transfer.To.Hex(), transfer.Block)
+		}
+		// Any node serves the balances of the shared chain
+		if balance := balanceOf(t, network.Nodes["elena"], recipients[i]); balance.Cmp(amount) != 0 {
+			t.Errorf("%s holds %v, want %v", recipients[i].Hex(), balance, amount)
+		}
+	}
+
+	// The coinbase cannot pay the gas of a transfer of its whole balance
+	_, err = tm.Faucet(ctx, "bob", recipients[:1], fake.CoinbaseBalance, 1)
+	if !errors.Is(err, scenarios.ErrInsufficientFunds) {
+		t.Errorf("got %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestSenderPool(t *testing.T) {
+	tm, network := newTestManager(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	minBalance := big.NewInt(1e18)
+	pool, err := tm.SenderPool(ctx, "cassandra", nil, 3, minBalance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if keys := pool.Keys(); len(keys) != 3 {
+		t.Fatalf("got %d keys, want 3", len(keys))
+	}
+
+	recipient := newAddresses(t, 1)[0]
+	requests := make([]scenarios.TxRequest, 6)
+	for i := range requests {
+		requests[i] = scenarios.TxRequest{To: &recipient, Value: big.NewInt(1000), Gas: 21000}
+	}
+	hashes, err := pool.SendBatch(ctx, requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipts, err := pool.WaitMined(ctx, hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != len(requests) {
+		t.Fatalf("got %d receipts, want %d", len(receipts), len(requests))
+	}
+	if balance := balanceOf(t, network.Nodes["alice"], recipient); balance.Int64() != 6000 {
+		t.Errorf("the recipient holds %v wei, want 6000", balance)
+	}
+
+	// Reloading the same keys finds them funded and sends no top-up
+	before := network.Chain.BlockNumber()
+	again, err := tm.SenderPool(ctx, "cassandra", pool.Keys(), 0, big.NewInt(1e17))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.Close()
+	if after := network.Chain.BlockNumber(); after != before {
+		t.Errorf("funded senders were topped up: head moved from #%d to #%d", before, after)
+	}
+}
+
+func TestRunLoad(t *testing.T) {
+	tm, _ := newTestManager(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var mix scenarios.WorkloadMix
+	for _, workload := range scenarios.Workloads {
+		mix = append(mix, scenarios.WorkloadShare{Workload: workload, Weight: 1})
+	}
+	plan := scenarios.LoadPlan{
+		Node:       "alice",
+		Rate:       2 * float64(len(mix)),
+		Duration:   time.Second,
+		Mix:        mix,
+		Workloads:  scenarios.DefaultWorkloadSettings(),
+		Senders:    2,
+		Strategies: []scenarios.FeeStrategy{scenarios.FeeFixed, scenarios.FeeOracle},
+		Fees:       scenarios.DefaultFeeSettings(),
+		Timeout:    10 * time.Second,
+	}
+	result, err := tm.RunLoad(ctx, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Rejected) > 0 {
+		t.Fatalf("got rejections %v", result.Rejected)
+	}
+	workloads := make(map[scenarios.Workload]int)
+	for _, tx := range result.Transactions {
+		if !tx.Included || tx.Block == 0 || tx.GasUsed == 0 {
+			t.Errorf("%s: included %v in block #%d using %d gas", tx.Hash.Hex(), tx.Included, tx.Block, tx.GasUsed)
+		}
+		if tx.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("%s %s: got status %d, want it successful", tx.Workload, tx.Hash.Hex(), tx.Status)
+		}
+		workloads[tx.Workload]++
+	}
+	for _, workload := range scenarios.Workloads {
+		if workloads[workload] == 0 {
+			t.Errorf("no %s transaction was sent", workload)
+		}
+		if _, ok := result.Contracts[workload]; !ok && workload != scenarios.WorkloadTransfer {
+			t.Errorf("the %s contract was not deployed", workload)
+		}
+	}
+	for _, group := range result.ByWorkload() {
+		if group.Reverted > 0 || group.Included != group.Sent {
+			t.Errorf("%s: %d sent, %d included, %d reverted", group.Name, group.Sent, group.Included, group.Reverted)
+		}
+	}
+	if len(result.Blocks) == 0 {
+		t.Error("the fees of the run's blocks were not read")
+	}
+}
+
+func TestLoadGroupsLeaveOutReverted(t *testing.T) {
+	result := &scenarios.LoadResult{
+		Plan: scenarios.LoadPlan{Mix: scenarios.WorkloadMix{{Workload: scenarios.WorkloadSwap, Weight: 1}}},
+		Transactions: []*scenarios.LoadTransaction{
+			{Workload: scenarios.WorkloadSwap, Included: true, Status: types.ReceiptStatusSuccessful, Latency: time.Second, GasUsed: 60000},
+			{Workload: scenarios.WorkloadSwap, Included: true, Status: types.ReceiptStatusFailed, Latency: time.Hour, GasUsed: 30000},
+			{Workload: scenarios.WorkloadSwap},
+		},
+	}
+	groups := result.ByWorkload()
+	if len(groups) != 1 {
+		t.Fatalf("got groups %+v", groups)
+	}
+	group := groups[0]
+	if group.Sent != 3 || group.Included != 1 || group.Reverted != 1 {
+		t.Errorf("got %d sent, %d included, %d reverted, want 3, 1 and 1", group.Sent, group.Included, group.Reverted)
+	}
+	if group.AvgGasUsed != 60000 || group.Latency.Samples != 1 || group.Latency.Missed != 1 {
+		t.Errorf("got %.0f gas and latency %+v, want the successful transaction only", group.AvgGasUsed, group.Latency)
+	}
+}
+
+func TestRunScenarioErrors(t *testing.T) {
+	tm, network := newTestManager(t)
+
+	if _, err := tm.Run("9", scenarios.RunOptions{}); !errors.Is(err, scenarios.ErrUnknownScenario) {
+		t.Errorf("got %v, want ErrUnknownScenario", err)
+	}
+
+	err := network.Runtime.Compose(context.Background(), "", []string{"stop", "alice"}, nil, io.Discard, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := tm.Run("1", scenarios.RunOptions{Force: true})
+	if !errors.Is(err, scenarios.ErrNodeOffline) {
+		t.Errorf("got %v, want ErrNodeOffline", err)
+	}
+	if len(results) != 1 || results[0].Scenario != "1" || results[0].Err == nil {
+		t.Errorf("got results %+v, want scenario 1 failed", results)
+	}
+}
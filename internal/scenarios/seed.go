@@ -0,0 +1,194 @@
+package scenarios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SeedProgressFile keeps seeding progress so an interrupted seed resumes.
+const SeedProgressFile = "benchy_seed.json"
+
+const (
+	seedSenders     = 8
+	slotsPerCall    = 200
+	seedTransferGas = 21000
+	seedDeployGas   = 100000
+)
+
+// seedSenderBalance is what each sender is topped up to: 100 ETH.
+var seedSenderBalance = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+
+// storageFillerCode deploys a contract whose fallback writes slot i = i+1 for
+// i in [start, start+count), with start and count as two calldata words.
+var storageFillerCode = hexutil.MustDecode("0x601f80600b6000396000f3" +
+	"6000358060203501905b81811015601d578060010181556001016009565b00")
+
+// SeedPlan is the state to add to the chain.
+type SeedPlan struct {
+	Node      string `json:"node"`
+	Accounts  int    `json:"accounts"`
+	Contracts int    `json:"contracts"`
+	Slots     int    `json:"slots"`
+	BatchSize int    `json:"batch_size"`
+}
+
+// SeedProgress is what has been confirmed on chain so far.
+type SeedProgress struct {
+	Plan      SeedPlan `json:"plan"`
+	Keys      []string `json:"sender_keys"`
+	Accounts  int      `json:"accounts_created"`
+	Contracts []string `json:"contracts"`
+	Slots     int      `json:"slots_written"`
+}
+
+func (p *SeedProgress) Complete() bool {
+	return p.Accounts >= p.Plan.Accounts && len(p.Contracts) >= p.Plan.Contracts && p.Slots >= p.Plan.Slots
+}
+
+// LoadSeedProgress returns the progress in path, nil if there is none.
+func LoadSeedProgress(path string) (*SeedProgress, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	progress := &SeedProgress{}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return progress, nil
+}
+
+func (p *SeedProgress) save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Seed fills the chain through plan.Node with plain accounts, storage filler
+// contracts and storage slots, in batches of signed transactions. Progress is
+// saved to progressPath after each mined batch; a progress for the same plan
+// is resumed.
+func (tm *TransactionManager) Seed(ctx context.Context, plan SeedPlan, progressPath string) (*SeedProgress, error) {
+	if plan.Slots > 0 && plan.Contracts == 0 {
+		return nil, fmt.Errorf("storage slots need at least one contract")
+	}
+	if plan.BatchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive")
+	}
+
+	progress, err := LoadSeedProgress(progressPath)
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil && progress.Plan != plan {
+		return nil, fmt.Errorf("%s holds a seed with a different plan (%+v), rerun with --restart", progressPath, progress.Plan)
+	}
+	if progress == nil {
+		progress = &SeedProgress{Plan: plan}
+	} else {
+		slog.Info("↩️  Resuming seed", "accounts", progress.Accounts,
+			"contracts", len(progress.Contracts), "slots", progress.Slots)
+	}
+
+	pool, err := tm.SenderPool(ctx, plan.Node, progress.Keys, seedSenders, seedSenderBalance)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+	progress.Keys = pool.Keys()
+	if err := progress.save(progressPath); err != nil {
+		return nil, err
+	}
+
+	for progress.Accounts < plan.Accounts {
+		count := min(plan.BatchSize, plan.Accounts-progress.Accounts)
+		requests := make([]TxRequest, count)
+		for i := range requests {
+			to := seedAccount(progress.Accounts + i)
+			requests[i] = TxRequest{To: &to, Value: big.NewInt(1), Gas: seedTransferGas}
+		}
+		if _, err := sendSeedBatch(ctx, pool, requests); err != nil {
+			return progress, err
+		}
+		progress.Accounts += count
+		if err := progress.save(progressPath); err != nil {
+			return progress, err
+		}
+		slog.Info("👤 Accounts created", "done", progress.Accounts, "total", plan.Accounts)
+	}
+
+	for len(progress.Contracts) < plan.Contracts {
+		count := min(plan.BatchSize, plan.Contracts-len(progress.Contracts))
+		requests := make([]TxRequest, count)
+		for i := range requests {
+			requests[i] = TxRequest{Gas: seedDeployGas, Data: storageFillerCode}
+		}
+		receipts, err := sendSeedBatch(ctx, pool, requests)
+		if err != nil {
+			return progress, err
+		}
+		for _, receipt := range receipts {
+			progress.Contracts = append(progress.Contracts, receipt.ContractAddress.Hex())
+		}
+		if err := progress.save(progressPath); err != nil {
+			return progress, err
+		}
+		slog.Info("📄 Contracts deployed", "done", len(progress.Contracts), "total", plan.Contracts)
+	}
+
+	for progress.Slots < plan.Slots {
+		var requests []TxRequest
+		written := progress.Slots
+		for len(requests) < plan.BatchSize && written < plan.Slots {
+			count := min(slotsPerCall, plan.Slots-written)
+			// Slot keys are unique across calls, contracts take turns
+			contract := common.HexToAddress(progress.Contracts[(written/slotsPerCall)%len(progress.Contracts)])
+			data := append(common.BigToHash(big.NewInt(int64(written))).Bytes(), common.BigToHash(big.NewInt(int64(count))).Bytes()...)
+			requests = append(requests, TxRequest{To: &contract, Gas: 50000 + 23000*uint64(count), Data: data})
+			written += count
+		}
+		if _, err := sendSeedBatch(ctx, pool, requests); err != nil {
+			return progress, err
+		}
+		progress.Slots = written
+		if err := progress.save(progressPath); err != nil {
+			return progress, err
+		}
+		slog.Info("💾 Storage slots written", "done", progress.Slots, "total", plan.Slots)
+	}
+
+	return progress, nil
+}
+
+// sendSeedBatch submits requests and waits until all of them are mined.
+func sendSeedBatch(ctx context.Context, pool *SenderPool, requests []TxRequest) ([]*types.Receipt, error) {
+	hashes, err := pool.SendBatch(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	return pool.WaitMined(ctx, hashes)
+}
+
+// seedAccount derives the i-th seeded account, so resumed runs continue the
+// same sequence.
+func seedAccount(i int) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprintf("benchy-seed-account-%d", i))))
+}
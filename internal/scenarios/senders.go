@@ -0,0 +1,277 @@
+package scenarios
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Sender is a locally held key that signs its own transactions.
+type Sender struct {
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+	nonce   uint64
+}
+
+// TxRequest is an unsigned transaction for the pool. A nil To deploys Data.
+// With a GasFeeCap it is an EIP-1559 transaction, otherwise it pays the
+// pool's gas price.
+type TxRequest struct {
+	To        *common.Address
+	Value     *big.Int
+	Gas       uint64
+	Data      []byte
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// SenderPool spreads signed transactions over several funded keys, so
+// batches are not serialized behind a single account's nonce.
+type SenderPool struct {
+	rpc      *rpc.Client
+	client   *ethclient.Client
+	chainID  *big.Int
+	signer   types.Signer
+	gasPrice *big.Int
+	senders  []*Sender
+	next     int
+	// strategy is the fee strategy of the load the pool sends, if any
+	strategy FeeStrategy
+}
+
+// SenderPool connects to node and loads the senders from their hex keys,
+// generating size keys when none are given. Senders whose balance is below
+// minBalance are topped up from the node's unlocked coinbase.
+func (tm *TransactionManager) SenderPool(ctx context.Context, node string, keys []string, size int, minBalance *big.Int) (*SenderPool, error) {
+	return tm.senderPool(ctx, node, "", keys, size, minBalance)
+}
+
+// senderPool is SenderPool for the senders of a fee strategy, which the
+// funding log names.
+func (tm *TransactionManager) senderPool(ctx context.Context, node string, strategy FeeStrategy, keys []string, size int, minBalance *big.Int) (*SenderPool, error) {
+	endpoint := tm.getEndpoint(node)
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s: %w", node, ErrNodeOffline)
+	}
+	rpcClient, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", node, err)
+	}
+	client := ethclient.NewClient(rpcClient)
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		rpcClient.Close()
+		return nil, fmt.Errorf("failed to read chain id from %s: %v", node, err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		rpcClient.Close()
+		return nil, fmt.Errorf("failed to read gas price from %s: %v", node, err)
+	}
+	pool := &SenderPool{
+		rpc:      rpcClient,
+		client:   client,
+		chainID:  chainID,
+		signer:   types.LatestSignerForChainID(chainID),
+		gasPrice: gasPrice,
+		strategy: strategy,
+	}
+
+	for _, hexKey := range keys {
+		key, err := crypto.HexToECDSA(hexKey)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("invalid sender key: %v", err)
+		}
+		pool.senders = append(pool.senders, &Sender{Key: key, Address: crypto.PubkeyToAddress(key.PublicKey)})
+	}
+	for len(pool.senders) < size {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.senders = append(pool.senders, &Sender{Key: key, Address: crypto.PubkeyToAddress(key.PublicKey)})
+	}
+
+	if err := pool.fund(ctx, minBalance); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	for _, sender := range pool.senders {
+		if sender.nonce, err = client.PendingNonceAt(ctx, sender.Address); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to read nonce of %s: %v", sender.Address.Hex(), err)
+		}
+	}
+	return pool, nil
+}
+
+// fund sends minBalance from the coinbase to every sender below it.
+func (p *SenderPool) fund(ctx context.Context, minBalance *big.Int) error {
+	var coinbase common.Address
+	if err := p.rpc.CallContext(ctx, &coinbase, "eth_coinbase"); err != nil {
+		return fmt.Errorf("failed to read coinbase: %v", err)
+	}
+
+	var poor []common.Address
+	for _, sender := range p.senders {
+		balance, err := p.client.BalanceAt(ctx, sender.Address, nil)
+		if err != nil {
+			return fmt.Errorf("failed to read balance of %s: %v", sender.Address.Hex(), err)
+		}
+		if balance.Cmp(minBalance) < 0 {
+			poor = append(poor, sender.Address)
+		}
+	}
+	if len(poor) == 0 {
+		return nil
+	}
+	attrs := []interface{}{"count", len(poor), "from", coinbase.Hex()}
+	if p.strategy != "" {
+		attrs = append(attrs, "strategy", p.strategy)
+	}
+	slog.Info("💰 Funding senders", attrs...)
+	pending, err := coinbaseTransfers(ctx, p.rpc, coinbase, poor, minBalance)
+	if err != nil {
+		return err
+	}
+	_, err = p.WaitMined(ctx, pending)
+	return err
+}
+
+// Keys returns the hex private keys of the senders, for resuming later.
+func (p *SenderPool) Keys() []string {
+	keys := make([]string, len(p.senders))
+	for i, sender := range p.senders {
+		keys[i] = hexutil.Encode(crypto.FromECDSA(sender.Key))[2:]
+	}
+	return keys
+}
+
+// Sign signs request with the next sender in turn and advances its nonce.
+func (p *SenderPool) Sign(request TxRequest) (*types.Transaction, *Sender, error) {
+	sender := p.senders[p.next]
+	p.next = (p.next + 1) % len(p.senders)
+
+	tx, err := p.SignWithNonce(sender, sender.nonce, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	sender.nonce++
+	return tx, sender, nil
+}
+
+// SignWithNonce signs request from sender at nonce, to replace a pending
+// transaction.
+func (p *SenderPool) SignWithNonce(sender *Sender, nonce uint64, request TxRequest) (*types.Transaction, error) {
+	value := request.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	var data types.TxData = &types.LegacyTx{
+		Nonce:    nonce,
+		To:       request.To,
+		Value:    value,
+		Gas:      request.Gas,
+		GasPrice: p.gasPrice,
+		Data:     request.Data,
+	}
+	if request.GasFeeCap != nil {
+		data = &types.DynamicFeeTx{
+			ChainID:   p.chainID,
+			Nonce:     nonce,
+			To:        request.To,
+			Value:     value,
+			Gas:       request.Gas,
+			GasTipCap: request.GasTipCap,
+			GasFeeCap: request.GasFeeCap,
+			Data:      request.Data,
+		}
+	}
+	return types.SignNewTx(sender.Key, p.signer, data)
+}
+
+// SendBatch signs each request with the next sender in turn and submits them
+// all in one JSON-RPC batch.
+func (p *SenderPool) SendBatch(ctx context.Context, requests []TxRequest) ([]common.Hash, error) {
+	hashes := make([]common.Hash, len(requests))
+	batch := make([]rpc.BatchElem, len(requests))
+	used := make([]*Sender, len(requests))
+
+	for i, request := range requests {
+		tx, sender, err := p.Sign(request)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		used[i] = sender
+		batch[i] = rpc.BatchElem{Method: "eth_sendRawTransaction", Args: []interface{}{hexutil.Encode(raw)}, Result: &hashes[i]}
+	}
+
+	if err := p.rpc.BatchCallContext(ctx, batch); err != nil {
+		p.resync(ctx, used)
+		return nil, fmt.Errorf("batch submission failed: %v", err)
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			p.resync(ctx, used)
+			return nil, fmt.Errorf("transaction rejected: %v", elem.Error)
+		}
+	}
+	return hashes, nil
+}
+
+// resync reloads nonces after a failed batch left gaps.
+func (p *SenderPool) resync(ctx context.Context, senders []*Sender) {
+	for _, sender := range senders {
+		if nonce, err := p.client.PendingNonceAt(ctx, sender.Address); err == nil {
+			sender.nonce = nonce
+		}
+	}
+}
+
+// WaitMined polls for the receipts of hashes and fails on reverted ones.
+func (p *SenderPool) WaitMined(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error) {
+	return waitMined(ctx, p.client, hashes)
+}
+
+func waitMined(ctx context.Context, client *ethclient.Client, hashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	for i, hash := range hashes {
+		for receipts[i] == nil {
+			receipt, err := client.TransactionReceipt(ctx, hash)
+			if err == nil {
+				if receipt.Status != types.ReceiptStatusSuccessful {
+					return nil, fmt.Errorf("transaction %s reverted", hash.Hex())
+				}
+				receipts[i] = receipt
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("transaction %s not mined: %v", hash.Hex(), ctx.Err())
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+	return receipts, nil
+}
+
+func (p *SenderPool) Close() {
+	p.rpc.Close()
+}
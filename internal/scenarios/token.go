@@ -0,0 +1,100 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"benchy/internal/state"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Scenario 2 deploys the BY token from the ERC20 workload contract, whose
+// balanceOf reads the holders' balances back.
+var erc20BalanceOf = hexutil.MustDecode("0x70a08231")
+
+// tokenGas covers a mint or a transfer of the ERC20 workload contract.
+const tokenGas = 80000
+
+// byTokens returns n BY tokens in their smallest unit, at 18 decimals.
+func byTokens(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+// formatBY formats an amount of BY tokens in whole tokens.
+func formatBY(amount *big.Int) string {
+	return new(big.Int).Quo(amount, byTokens(1)).String() + " BY"
+}
+
+// coinbaseTransaction sends a transaction from the coinbase of node and
+// waits for it to be mined. A nil to creates a contract.
+func (tm *TransactionManager) coinbaseTransaction(ctx context.Context, node string, from common.Address, to *common.Address, data []byte, gas uint64) (*types.Receipt, error) {
+	args := map[string]interface{}{
+		"from":  from,
+		"input": hexutil.Bytes(data),
+		"gas":   hexutil.Uint64(gas),
+	}
+	if to != nil {
+		args["to"] = to
+	}
+	client := tm.clients[node]
+	var hash common.Hash
+	if err := client.Client().CallContext(ctx, &hash, "eth_sendTransaction", args); err != nil {
+		return nil, fmt.Errorf("transaction error: %v", err)
+	}
+	receipts, err := waitMined(ctx, client, []common.Hash{hash})
+	if err != nil {
+		return nil, err
+	}
+	return receipts[0], nil
+}
+
+// receiptFee returns the fee a mined transaction paid.
+func receiptFee(receipt *types.Receipt) *big.Int {
+	if receipt.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+}
+
+// tokenBalance reads the balance of holder in the token at token.
+func (tm *TransactionManager) tokenBalance(ctx context.Context, node string, token, holder common.Address) (*big.Int, error) {
+	data := calldata(erc20BalanceOf, common.LeftPadBytes(holder.Bytes(), 32))
+	result, err := tm.clients[node].CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the token balance of %s: %v", holder.Hex(), err)
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// requireToken checks that the BY token scenario 2 deployed has its code on
+// the chain of node.
+func (tm *TransactionManager) requireToken(node string) error {
+	journal, err := state.Default().Load()
+	if err != nil {
+		return err
+	}
+	token := journal.Contract(2)
+	if token == "" {
+		return fmt.Errorf("%w: scenario 2 deployed no BY token", ErrPreconditionFailed)
+	}
+	client, ok := tm.clients[node]
+	if !ok {
+		return fmt.Errorf("%w: %s is not selected", ErrNodeOffline, node)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	code, err := client.CodeAt(ctx, common.HexToAddress(token), nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the code at %s on %s: %v", token, node, err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w: the BY token %s has no code on %s", ErrPreconditionFailed, token, node)
+	}
+	return nil
+}
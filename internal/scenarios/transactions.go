@@ -2,61 +2,69 @@ package scenarios
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os/exec"
+	"io"
+	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type TransactionManager struct {
-	clients map[string]*ethclient.Client
+	clients   map[string]*ethclient.Client
+	endpoints map[string]string
+	// genesis is the genesis file the network was created from.
+	genesis string
+	current *ScenarioResult
+	// out receives the console output of the scenarios.
+	out io.Writer
 }
 
-func NewTransactionManager() *TransactionManager {
+// NewTransactionManager creates a client per node. Dialing HTTP endpoints
+// does not contact the nodes; reachability is checked per scenario.
+func NewTransactionManager(endpoints map[string]string) (*TransactionManager, error) {
 	clients := make(map[string]*ethclient.Client)
-	
-	endpoints := map[string]string{
-		"alice":     "http://localhost:8545",
-		"bob":       "http://localhost:8547",
-		"cassandra": "http://localhost:8549",
-		"driss":     "http://localhost:8551",
-		"elena":     "http://localhost:8553",
-	}
-
 	for name, endpoint := range endpoints {
-		if client, err := ethclient.Dial(endpoint); err == nil {
-			clients[name] = client
+		client, err := ethclient.Dial(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for %s (%s): %v", name, endpoint, err)
 		}
+		clients[name] = client
 	}
 
-	return &TransactionManager{clients: clients}
+	return &TransactionManager{clients: clients, endpoints: endpoints, out: os.Stdout}, nil
+}
+
+// WithOutput returns a manager sharing the clients of tm that prints the
+// scenarios to out instead of stdout.
+func (tm *TransactionManager) WithOutput(out io.Writer) *TransactionManager {
+	copied := *tm
+	copied.out = out
+	copied.current = nil
+	return &copied
+}
+
+// SetGenesis sets the genesis file scenario 0 checks the network against.
+func (tm *TransactionManager) SetGenesis(path string) {
+	tm.genesis = path
 }
 
-// Méthode getEndpoint centralisée (sera utilisée par full_scenarios.go aussi)
 func (tm *TransactionManager) getEndpoint(nodeName string) string {
-	endpoints := map[string]string{
-		"alice":     "http://localhost:8545",
-		"bob":       "http://localhost:8547",
-		"cassandra": "http://localhost:8549",
-		"driss":     "http://localhost:8551",
-		"elena":     "http://localhost:8553",
-	}
-	return endpoints[nodeName]
+	return tm.endpoints[nodeName]
 }
 
 // MÉTHODES MISES À JOUR POUR CORRESPONDRE AU SYSTÈME CORRIGÉ
 
 func (tm *TransactionManager) Scenario0() error {
-	fmt.Println("🎬 Scenario 0: Initializing network...")
-	fmt.Println("✅ Validators are mining REAL blocks automatically with --dev mode")
-	fmt.Println("💰 Each --dev node has pre-funded accounts ready for transactions")
+	fmt.Fprintln(tm.out, "🎬 Scenario 0: Initializing network...")
+	fmt.Fprintln(tm.out, "✅ Validators are mining REAL blocks automatically with --dev mode")
+	fmt.Fprintln(tm.out, "💰 Each --dev node has pre-funded accounts ready for transactions")
 	
 	// Vérifier que les nœuds minent
 	for name, client := range tm.clients {
 		if blockNum, err := client.BlockNumber(context.Background()); err == nil {
-			fmt.Printf("   %s: Block #%d\n", name, blockNum)
+			fmt.Fprintf(tm.out, "   %s: Block #%d\n", name, blockNum)
 		}
 	}
 	
@@ -64,44 +72,39 @@ func (tm *TransactionManager) Scenario0() error {
 }
 
 func (tm *TransactionManager) Scenario1() error {
-	fmt.Println("🎬 Scenario 1: Alice sending 0.1 ETH to Bob every 10 seconds...")
+	fmt.Fprintln(tm.out, "🎬 Scenario 1: Alice sending 0.1 ETH to Bob every 10 seconds...")
 	
 	// Pour l'instant, on va juste afficher les comptes réels
-	fmt.Println("🔍 Getting real accounts first...")
+	fmt.Fprintln(tm.out, "🔍 Getting real accounts first...")
 	
-	endpoints := map[string]string{
-		"Alice": "http://localhost:8545",
-		"Bob":   "http://localhost:8547",
-	}
-
-	for name, endpoint := range endpoints {
-		cmd := exec.Command("curl", "-s", "-X", "POST",
-			"-H", "Content-Type: application/json",
-			"--data", `{"jsonrpc":"2.0","method":"eth_accounts","params":[],"id":1}`,
-			endpoint)
+	for name, node := range map[string]string{"Alice": "alice", "Bob": "bob"} {
+		client, ok := tm.clients[node]
+		if !ok {
+			fmt.Fprintf(tm.out, "❌ %s offline\n", name)
+			continue
+		}
 		
-		output, err := cmd.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		var accounts []common.Address
+		err := client.Client().CallContext(ctx, &accounts, "eth_accounts")
+		cancel()
 		if err != nil {
-			fmt.Printf("❌ %s offline\n", name)
+			fmt.Fprintf(tm.out, "❌ %s offline\n", name)
 			continue
 		}
-
-		var response map[string]interface{}
-		if json.Unmarshal(output, &response) == nil {
-			if accounts, ok := response["result"].([]interface{}); ok && len(accounts) > 0 {
-				fmt.Printf("💰 %s account: %s\n", name, accounts[0])
-			}
+		if len(accounts) > 0 {
+			fmt.Fprintf(tm.out, "💰 %s account: %s\n", name, accounts[0].Hex())
 		}
 	}
 	
-	fmt.Println("🔄 Starting periodic transfers simulation...")
+	fmt.Fprintln(tm.out, "🔄 Starting periodic transfers simulation...")
 	
 	for i := 0; i < 3; i++ {
-		fmt.Printf("💸 Transfer #%d: Alice → Bob (0.1 ETH)\n", i+1)
-		fmt.Println("   ⏳ (Real transaction implementation coming next...)")
+		fmt.Fprintf(tm.out, "💸 Transfer #%d: Alice → Bob (0.1 ETH)\n", i+1)
+		fmt.Fprintln(tm.out, "   ⏳ (Real transaction implementation coming next...)")
 		
 		if i < 2 {
-			fmt.Println("   ⏱️  Waiting 10 seconds...")
+			fmt.Fprintln(tm.out, "   ⏱️  Waiting 10 seconds...")
 			time.Sleep(10 * time.Second)
 		}
 	}
@@ -110,18 +113,18 @@ func (tm *TransactionManager) Scenario1() error {
 }
 
 func (tm *TransactionManager) GetNetworkStatus() {
-	fmt.Println("📊 Current Network Status:")
-	fmt.Println("==========================")
+	fmt.Fprintln(tm.out, "📊 Current Network Status:")
+	fmt.Fprintln(tm.out, "==========================")
 	
 	for name, client := range tm.clients {
 		ctx := context.Background()
 		
 		blockNum, err := client.BlockNumber(ctx)
 		if err != nil {
-			fmt.Printf("%s: ❌ Offline\n", name)
+			fmt.Fprintf(tm.out, "%s: ❌ Offline\n", name)
 			continue
 		}
 		
-		fmt.Printf("%s: 🟢 Block #%d\n", name, blockNum)
+		fmt.Fprintf(tm.out, "%s: 🟢 Block #%d\n", name, blockNum)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,289 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Workload is a kind of transaction of the load generator.
+type Workload string
+
+const (
+	// WorkloadTransfer is a plain ETH transfer.
+	WorkloadTransfer Workload = "transfer"
+	// WorkloadERC20 is an ERC20 transfer: a balance check, two balance
+	// writes and a Transfer event.
+	WorkloadERC20 Workload = "erc20"
+	// WorkloadERC721 mints a token: a counter, an owner, a balance and a
+	// Transfer event.
+	WorkloadERC721 Workload = "erc721"
+	// WorkloadStorage writes WorkloadSettings.StorageSlots fresh slots.
+	WorkloadStorage Workload = "storage"
+	// WorkloadKeccak chains WorkloadSettings.KeccakRounds keccak256 hashes.
+	WorkloadKeccak Workload = "keccak"
+	// WorkloadSwap is a Uniswap V2 style swap against a constant product
+	// pool: two reserves, the output balance and a Swap event.
+	WorkloadSwap Workload = "swap"
+)
+
+// Workloads lists the workloads in display order.
+var Workloads = []Workload{WorkloadTransfer, WorkloadERC20, WorkloadERC721, WorkloadStorage, WorkloadKeccak, WorkloadSwap}
+
+// The workload contracts are hand-assembled: a selector dispatch, then the
+// function. Their constructors only set initial state.
+var (
+	// erc20Code: transfer(address,uint256) 0xa9059cbb,
+	// mint(address,uint256) 0x40c10f19 open to anyone, and
+	// balanceOf(address) 0x70a08231. Balances are at keccak(owner . 0).
+	erc20Code = hexutil.MustDecode("0x6101008061000d6000396000f3" +
+		"60003560e01c8063a9059cbb1461002c57806340c10f191461009957806370a08231146100e5575b600080fd5b50" +
+		"33600052600060205260406000208054602435808210610027579003905560043560005260406000208054602435" +
+		"019055602435600052600435337fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" +
+		"60206000a3600160005260206000f35b5060043560005260006020526040600020805460243501905560243560005260" +
+		"043560007fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3005b50600435" +
+		"600052600060205260406000205460005260206000f3")
+	// erc721Code: mint(address) 0x6a627842 assigns the next token id.
+	// The supply is in slot 0, owners at keccak(id . 1), balances at
+	// keccak(owner . 2).
+	erc721Code = hexutil.MustDecode("0x6100778061000d6000396000f3" +
+		"60003560e01c80636a62784214610016575b600080fd5b506000548060010160005580600052600160205260406000" +
+		"206004359055600435600052600260205260406000208054600101905560043560007fddf252ad1be2c89b69c2b068" +
+		"fc378daa952ba7f163c4a11628f55a4df523b3ef600080a400")
+	// storageCode: fill(uint256 count) 0x3fda5389 writes the block number
+	// to the count slots after the last one written, tracked in slot 0.
+	storageCode = hexutil.MustDecode("0x61003d8061000d6000396000f3" +
+		"60003560e01c80633fda538914610016575b600080fd5b506000548060043501905b8181101561003757438160010155" +
+		"600101610021565b5060005500")
+	// keccakCode: hash(uint256 rounds) 0xb189fd4c hashes slot 0 rounds
+	// times and stores the result back.
+	keccakCode = hexutil.MustDecode("0x61003d8061000d6000396000f3" +
+		"60003560e01c8063b189fd4c14610016575b600080fd5b506000546004355b8015610037579060005260206000" +
+		"20906001900361001e565b5060005500")
+	// swapCode: swap(uint256 amountIn, bool zeroForOne) 0x2aea6605 prices
+	// the output with the 0.3% fee of Uniswap V2 against reserves in slots
+	// 0 and 1, both 1e24 at deployment, and credits it at
+	// keccak(caller . 2+token). The input is taken for granted.
+	swapCode = hexutil.MustDecode("0x69d3c21bcecceda1000000806000556001556100898061001f6000396000f3" +
+		"60003560e01c80632aea660514610016575b600080fd5b5060243515158015805482546004356103e50280820290" +
+		"836103e80201900480910384559060043501825533600052826002016020526040600020805482019055600435600052" +
+		"602052337f77f92a1b6a1a11de8ca49515ad4c1fad45632dd3442167d74b90b304a3c7a75860406000a200")
+)
+
+const workloadDeployGas = 300000
+
+var (
+	erc20Transfer = hexutil.MustDecode("0xa9059cbb")
+	erc20Mint     = hexutil.MustDecode("0x40c10f19")
+	erc721Mint    = hexutil.MustDecode("0x6a627842")
+	storageFill   = hexutil.MustDecode("0x3fda5389")
+	keccakHash    = hexutil.MustDecode("0xb189fd4c")
+	swapExact     = hexutil.MustDecode("0x2aea6605")
+)
+
+// erc20Supply is minted to every load sender before the run.
+var erc20Supply = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// code returns the creation code of the workload's contract, nil for plain
+// transfers.
+func (w Workload) code() []byte {
+	switch w {
+	case WorkloadERC20:
+		return erc20Code
+	case WorkloadERC721:
+		return erc721Code
+	case WorkloadStorage:
+		return storageCode
+	case WorkloadKeccak:
+		return keccakCode
+	case WorkloadSwap:
+		return swapCode
+	}
+	return nil
+}
+
+func ParseWorkload(value string) (Workload, error) {
+	for _, workload := range Workloads {
+		if string(workload) == strings.ToLower(value) {
+			return workload, nil
+		}
+	}
+	names := make([]string, len(Workloads))
+	for i, workload := range Workloads {
+		names[i] = string(workload)
+	}
+	return "", fmt.Errorf("unknown workload %q (expected %s)", value, strings.Join(names, ", "))
+}
+
+// WorkloadSettings size the storage and keccak workloads.
+type WorkloadSettings struct {
+	StorageSlots int
+	KeccakRounds int
+}
+
+// DefaultWorkloadSettings write 10 slots and hash 1000 times per call.
+func DefaultWorkloadSettings() WorkloadSettings {
+	return WorkloadSettings{StorageSlots: 10, KeccakRounds: 1000}
+}
+
+// WorkloadShare is the weight of a workload in a mix.
+type WorkloadShare struct {
+	Workload Workload
+	Weight   int
+}
+
+// WorkloadMix is the proportion of each workload in a load.
+type WorkloadMix []WorkloadShare
+
+// ParseWorkloadMix reads entries such as "erc20=2" or "swap", whose weight
+// is then 1.
+func ParseWorkloadMix(entries []string) (WorkloadMix, error) {
+	var mix WorkloadMix
+	for _, entry := range entries {
+		name, weight, found := strings.Cut(entry, "=")
+		workload, err := ParseWorkload(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		share := WorkloadShare{Workload: workload, Weight: 1}
+		if found {
+			if share.Weight, err = strconv.Atoi(strings.TrimSpace(weight)); err != nil || share.Weight < 0 {
+				return nil, fmt.Errorf("invalid weight %q for workload %s", weight, workload)
+			}
+		}
+		if share.Weight > 0 {
+			mix = append(mix, share)
+		}
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("the workload mix is empty")
+	}
+	return mix, nil
+}
+
+func (m WorkloadMix) String() string {
+	parts := make([]string, len(m))
+	for i, share := range m {
+		parts[i] = fmt.Sprintf("%s=%d", share.Workload, share.Weight)
+	}
+	return strings.Join(parts, ",")
+}
+
+// workloadPicker deals out the workloads of a mix in proportion to their
+// weights, interleaved rather than in runs (smooth weighted round-robin).
+type workloadPicker struct {
+	mix     WorkloadMix
+	current []int
+	total   int
+}
+
+func newWorkloadPicker(mix WorkloadMix) *workloadPicker {
+	picker := &workloadPicker{mix: mix, current: make([]int, len(mix))}
+	for _, share := range mix {
+		picker.total += share.Weight
+	}
+	return picker
+}
+
+func (p *workloadPicker) next() Workload {
+	best := 0
+	for i, share := range p.mix {
+		p.current[i] += share.Weight
+		if p.current[i] > p.current[best] {
+			best = i
+		}
+	}
+	p.current[best] -= p.total
+	return p.mix[best].Workload
+}
+
+// workloadRequest builds the i-th transaction of a load, of workload,
+// against the deployed contracts. Recipient receives the transfers and the
+// minted tokens.
+func workloadRequest(workload Workload, i int, contracts map[Workload]common.Address, recipient common.Address, settings WorkloadSettings) TxRequest {
+	contract := contracts[workload]
+	switch workload {
+	case WorkloadERC20:
+		return TxRequest{To: &contract, Gas: 80000, Data: calldata(erc20Transfer, common.LeftPadBytes(recipient.Bytes(), 32), big.NewInt(1))}
+	case WorkloadERC721:
+		return TxRequest{To: &contract, Gas: 150000, Data: calldata(erc721Mint, common.LeftPadBytes(recipient.Bytes(), 32))}
+	case WorkloadStorage:
+		slots := settings.StorageSlots
+		return TxRequest{To: &contract, Gas: 50000 + 23000*uint64(slots), Data: calldata(storageFill, big.NewInt(int64(slots)))}
+	case WorkloadKeccak:
+		rounds := settings.KeccakRounds
+		return TxRequest{To: &contract, Gas: 50000 + 150*uint64(rounds), Data: calldata(keccakHash, big.NewInt(int64(rounds)))}
+	case WorkloadSwap:
+		// Alternate directions so the pool stays balanced
+		direction := big.NewInt(int64(i % 2))
+		return TxRequest{To: &contract, Gas: 120000, Data: calldata(swapExact, big.NewInt(1e15), direction)}
+	}
+	return TxRequest{To: &recipient, Gas: 21000}
+}
+
+// calldata appends 32-byte words to a selector.
+func calldata(selector []byte, words ...interface{}) []byte {
+	data := append([]byte{}, selector...)
+	for _, word := range words {
+		switch word := word.(type) {
+		case []byte:
+			data = append(data, word...)
+		case *big.Int:
+			data = append(data, common.BigToHash(word).Bytes()...)
+		}
+	}
+	return data
+}
+
+// DeployWorkloads deploys the contracts of the workloads of mix from pool
+// and mints ERC20 tokens to holders, returning the contract addresses.
+func DeployWorkloads(ctx context.Context, pool *SenderPool, mix WorkloadMix, holders []common.Address) (map[Workload]common.Address, error) {
+	contracts := make(map[Workload]common.Address)
+	var deployed []Workload
+	var requests []TxRequest
+	for _, share := range mix {
+		if code := share.Workload.code(); code != nil && !containsWorkload(deployed, share.Workload) {
+			deployed = append(deployed, share.Workload)
+			requests = append(requests, TxRequest{Gas: workloadDeployGas, Data: code})
+		}
+	}
+	if len(requests) == 0 {
+		return contracts, nil
+	}
+
+	receipts, err := sendSeedBatch(ctx, pool, requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy the workload contracts: %v", err)
+	}
+	for i, receipt := range receipts {
+		contracts[deployed[i]] = receipt.ContractAddress
+		slog.Info("📄 Workload contract deployed", "workload", deployed[i], "address", receipt.ContractAddress.Hex())
+	}
+
+	if token, ok := contracts[WorkloadERC20]; ok {
+		requests = requests[:0]
+		for _, holder := range holders {
+			data := calldata(erc20Mint, common.LeftPadBytes(holder.Bytes(), 32), erc20Supply)
+			requests = append(requests, TxRequest{To: &token, Gas: 80000, Data: data})
+		}
+		if _, err := sendSeedBatch(ctx, pool, requests); err != nil {
+			return nil, fmt.Errorf("failed to mint the ERC20 tokens: %v", err)
+		}
+	}
+	return contracts, nil
+}
+
+func containsWorkload(workloads []Workload, workload Workload) bool {
+	for _, w := range workloads {
+		if w == workload {
+			return true
+		}
+	}
+	return false
+}
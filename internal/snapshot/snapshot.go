@@ -0,0 +1,330 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"benchy/internal/config"
+	"benchy/internal/docker"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+const manifestName = "manifest.json"
+
+// Manifest describes the content of a snapshot archive.
+type Manifest struct {
+	Name      string        `json:"name"`
+	CreatedAt time.Time     `json:"created_at"`
+	Nodes     []NodeData    `json:"nodes"`
+	Topology  []string      `json:"topology"`
+	Config    []config.Node `json:"config"`
+}
+
+// NodeData is one node's datadir in the archive.
+type NodeData struct {
+	Name    string `json:"name"`
+	DataDir string `json:"datadir"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// Path returns the archive of a snapshot in dir. Names are plain file
+// names: separators and ".." would point outside dir.
+func Path(dir, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return filepath.Join(dir, name+".tar.gz"), nil
+}
+
+// Create stops the network, archives every node datadir along with the
+// genesis, added nodes and compose override, then starts the network again.
+func Create(ctx context.Context, dm *docker.DockerManager, cfg *config.Config, name string) (*Manifest, error) {
+	archivePath, err := Path(cfg.SnapshotDir, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(archivePath); err == nil {
+		return nil, fmt.Errorf("snapshot %s already exists", name)
+	}
+	if err := os.MkdirAll(cfg.SnapshotDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", cfg.SnapshotDir, err)
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %v", err)
+	}
+	defer cli.Close()
+
+	// Stopped containers give a consistent copy of the databases
+	slog.Info("⏸️  Stopping nodes to freeze their datadirs")
+	if err := dm.StopAll(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		slog.Info("▶️  Restarting nodes")
+		if err := dm.StartAll(); err != nil {
+			slog.Warn("Failed to restart nodes", "error", err)
+		}
+	}()
+
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", archivePath, err)
+	}
+	manifest, err := write(ctx, file, cli, dm, cfg, name)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(archivePath)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func write(ctx context.Context, file io.Writer, cli *client.Client, dm *docker.DockerManager, cfg *config.Config, name string) (*Manifest, error) {
+	gz := gzip.NewWriter(file)
+	archive := tar.NewWriter(gz)
+	manifest := &Manifest{Name: name, CreatedAt: time.Now().UTC(), Config: cfg.Nodes}
+
+	for _, source := range topologyFiles(dm, cfg) {
+		data, err := os.ReadFile(source)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", source, err)
+		}
+		entry := "topology/" + filepath.Base(source)
+		if err := addFile(archive, entry, data); err != nil {
+			return nil, err
+		}
+		manifest.Topology = append(manifest.Topology, filepath.Base(source))
+	}
+
+	for _, node := range cfg.Nodes {
+		slog.Info("📦 Archiving datadir", "node", node.Name, "path", node.ContainerDataDir())
+		size, err := addDataDir(ctx, archive, cli, node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive %s: %v", node.Name, err)
+		}
+		manifest.Nodes = append(manifest.Nodes, NodeData{Name: node.Name, DataDir: node.ContainerDataDir(), Bytes: size})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := addFile(archive, manifestName, data); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, gz.Close()
+}
+
+// addDataDir stores the docker tar stream of a datadir as nodes/<name>.tar.
+// The stream is buffered to a temp file since tar headers need its size.
+func addDataDir(ctx context.Context, archive *tar.Writer, cli *client.Client, node config.Node) (int64, error) {
+	reader, _, err := cli.CopyFromContainer(ctx, "benchy-"+node.Name, node.ContainerDataDir())
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp("", "benchy-datadir-*.tar")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, reader)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	header := &tar.Header{Name: "nodes/" + node.Name + ".tar", Mode: 0o644, Size: size, ModTime: time.Now()}
+	if err := archive.WriteHeader(header); err != nil {
+		return 0, err
+	}
+	_, err = io.Copy(archive, tmp)
+	return size, err
+}
+
+func addFile(archive *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: time.Now()}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := archive.Write(data)
+	return err
+}
+
+// topologyFiles are restored before the network is created.
+func topologyFiles(dm *docker.DockerManager, cfg *config.Config) []string {
+	return []string{cfg.Genesis, cfg.NodesFile, dm.OverrideFile()}
+}
+
+// Restore relaunches the network from a snapshot: topology files are put
+// back first, then each datadir is copied into its container before start.
+func Restore(ctx context.Context, dm *docker.DockerManager, cfg *config.Config, name string) (*Manifest, error) {
+	// The datadirs are copied from the compose directory, where the launch
+	// runs, so the archive path must not be relative
+	archivePath, err := Path(cfg.SnapshotDir, name)
+	if err != nil {
+		return nil, err
+	}
+	if archivePath, err = filepath.Abs(archivePath); err != nil {
+		return nil, fmt.Errorf("failed to resolve snapshot %s: %v", name, err)
+	}
+	manifest, err := readManifest(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes added since the snapshot must not survive the restore
+	for _, added := range []string{cfg.NodesFile, dm.OverrideFile()} {
+		if err := os.Remove(added); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove %s: %v", added, err)
+		}
+	}
+
+	destinations := make(map[string]string)
+	for _, source := range topologyFiles(dm, cfg) {
+		destinations[filepath.Base(source)] = source
+	}
+	err = walk(archivePath, func(header *tar.Header, content io.Reader) error {
+		base := path.Base(header.Name)
+		if path.Dir(header.Name) != "topology" || destinations[base] == "" {
+			return nil
+		}
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+		slog.Info("📐 Restoring topology file", "path", destinations[base])
+		return os.WriteFile(destinations[base], data, 0o644)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore topology: %v", err)
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %v", err)
+	}
+	defer cli.Close()
+
+	datadirs := make(map[string]string)
+	for _, node := range manifest.Nodes {
+		datadirs[node.Name] = node.DataDir
+	}
+
+	restore := func() error {
+		return walk(archivePath, func(header *tar.Header, content io.Reader) error {
+			if path.Dir(header.Name) != "nodes" {
+				return nil
+			}
+			node := strings.TrimSuffix(path.Base(header.Name), ".tar")
+			// The tar holds the datadir itself, so extract into its parent
+			parent := path.Dir(datadirs[node])
+			slog.Info("📥 Restoring datadir", "node", node, "path", datadirs[node])
+			return cli.CopyToContainer(ctx, "benchy-"+node, parent, content, types.CopyToContainerOptions{})
+		})
+	}
+
+	if err := dm.LaunchNetworkFrom(restore); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func readManifest(archivePath string) (*Manifest, error) {
+	var manifest *Manifest
+	err := walk(archivePath, func(header *tar.Header, content io.Reader) error {
+		if header.Name != manifestName {
+			return nil
+		}
+		manifest = &Manifest{}
+		return json.NewDecoder(content).Decode(manifest)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %v", archivePath, err)
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("snapshot %s has no manifest", archivePath)
+	}
+	return manifest, nil
+}
+
+func walk(archivePath string, visit func(*tar.Header, io.Reader) error) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := visit(header, archive); err != nil {
+			return err
+		}
+	}
+}
+
+// List returns the manifests of all snapshots in dir, newest first.
+func List(dir string) ([]*Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []*Manifest
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".tar.gz") {
+			continue
+		}
+		manifest, err := readManifest(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].CreatedAt.After(manifests[j].CreatedAt) })
+	return manifests, nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package state
+
+import "os"
+
+// Without flock, only goroutines sharing a Store are serialized.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package state persists what benchy did to the network across runs, as a
+// versioned journal of typed events shared by every command.
+package state
+
+import (
+	"math/big"
+	"strings"
+	"time"
+)
+
+// DefaultFile is the state file in the working directory.
+const DefaultFile = "benchy_state.json"
+
+type EventType string
+
+const (
+	// EventTxSent is a transaction accepted by a node.
+	EventTxSent EventType = "tx_sent"
+	// EventContractDeployed is a contract created by a transaction.
+	EventContractDeployed EventType = "contract_deployed"
+	// EventScenarioCompleted is a scenario that ran to the end.
+	EventScenarioCompleted EventType = "scenario_completed"
+	// EventNodeRestarted is a node seen offline after it ran.
+	EventNodeRestarted EventType = "node_restarted"
+	// EventNodeSynced is a restarted node back at the network head.
+	EventNodeSynced EventType = "node_synced"
+)
+
+// Event is one entry of the journal. Fields unused by its type are empty.
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// Node is the sender of a transaction or the node that restarted.
+	Node string `json:"node,omitempty"`
+	// To is the node receiving a transaction, if it is one.
+	To       string `json:"to,omitempty"`
+	ValueWei string `json:"value_wei,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+	// Contract is the address of a deployed contract.
+	Contract string `json:"contract,omitempty"`
+	Scenario int    `json:"scenario,omitempty"`
+	// Inferred marks the completions earlier versions guessed from
+	// balances. They do not count as completed.
+	Inferred bool `json:"inferred,omitempty"`
+}
+
+func TxSent(node, to string, value *big.Int, hash string) Event {
+	event := Event{Type: EventTxSent, Time: time.Now(), Node: strings.ToLower(node), To: strings.ToLower(to), Hash: hash}
+	if value != nil {
+		event.ValueWei = value.String()
+	}
+	return event
+}
+
+func ContractDeployed(node, contract, hash string) Event {
+	return Event{Type: EventContractDeployed, Time: time.Now(), Node: strings.ToLower(node), Contract: contract, Hash: hash}
+}
+
+func ScenarioCompleted(scenario int) Event {
+	return Event{Type: EventScenarioCompleted, Time: time.Now(), Scenario: scenario}
+}
+
+func NodeRestarted(node string) Event {
+	return Event{Type: EventNodeRestarted, Time: time.Now(), Node: strings.ToLower(node)}
+}
+
+func NodeSynced(node string) Event {
+	return Event{Type: EventNodeSynced, Time: time.Now(), Node: strings.ToLower(node)}
+}
+
+// State is the content of the state file.
+type State struct {
+	Version int     `json:"version"`
+	Events  []Event `json:"events"`
+}
+
+func (s *State) Append(events ...Event) {
+	s.Events = append(s.Events, events...)
+}
+
+// ScenarioCompleted reports whether the scenario completed at least once.
+func (s *State) ScenarioCompleted(scenario int) bool {
+	for _, event := range s.Events {
+		if event.Type == EventScenarioCompleted && event.Scenario == scenario && !event.Inferred {
+			return true
+		}
+	}
+	return false
+}
+
+// LastScenario returns the highest completed scenario, 0 if none.
+func (s *State) LastScenario() int {
+	last := 0
+	for _, event := range s.Events {
+		if event.Type == EventScenarioCompleted && event.Scenario > last && !event.Inferred {
+			last = event.Scenario
+		}
+	}
+	return last
+}
+
+// TxSent counts the transactions sent by node.
+func (s *State) TxSent(node string) int {
+	node = strings.ToLower(node)
+	count := 0
+	for _, event := range s.Events {
+		if event.Type == EventTxSent && event.Node == node {
+			count++
+		}
+	}
+	return count
+}
+
+// Transactions returns the transactions with a hash sent by the scenario.
+func (s *State) Transactions(scenario int) []Event {
+	var sent []Event
+	for _, event := range s.Events {
+		if event.Type == EventTxSent && event.Scenario == scenario && event.Hash != "" {
+			sent = append(sent, event)
+		}
+	}
+	return sent
+}
+
+// Contract returns the address of the last contract the scenario deployed,
+// empty if none.
+func (s *State) Contract(scenario int) string {
+	contract := ""
+	for _, event := range s.Events {
+		if event.Type == EventContractDeployed && event.Scenario == scenario {
+			contract = event.Contract
+		}
+	}
+	return contract
+}
+
+// Received sums the value of the transactions sent to node.
+func (s *State) Received(node string) *big.Int {
+	node = strings.ToLower(node)
+	total := new(big.Int)
+	for _, event := range s.Events {
+		if event.Type != EventTxSent || event.To != node {
+			continue
+		}
+		if value, ok := new(big.Int).SetString(event.ValueWei, 10); ok {
+			total.Add(total, value)
+		}
+	}
+	return total
+}
+
+// Restarted reports whether node restarted and has not synced since.
+func (s *State) Restarted(node string) bool {
+	node = strings.ToLower(node)
+	restarted := false
+	for _, event := range s.Events {
+		if event.Node != node {
+			continue
+		}
+		switch event.Type {
+		case EventNodeRestarted:
+			restarted = true
+		case EventNodeSynced:
+			restarted = false
+		}
+	}
+	return restarted
+}
+
+// Empty reports whether nothing was recorded.
+func (s *State) Empty() bool {
+	return len(s.Events) == 0
+}
+
+// WeiToETH converts for display.
+func WeiToETH(wei *big.Int) float64 {
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return eth
+}
@@ -0,0 +1,143 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store reads and writes a state file. Updates hold a lock on a sibling
+// .lock file, so goroutines and benchy processes sharing the file do not
+// lose each other's events, and the file is replaced atomically.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns the current state, empty if the file does not exist.
+func (s *Store) Load() (*State, error) {
+	var loaded *State
+	err := s.locked(func() error {
+		var err error
+		loaded, err = s.read()
+		return err
+	})
+	return loaded, err
+}
+
+// Update applies fn to the current state and saves the result, unless fn
+// fails.
+func (s *Store) Update(fn func(*State) error) error {
+	return s.locked(func() error {
+		current, err := s.read()
+		if err != nil {
+			return err
+		}
+		if err := fn(current); err != nil {
+			return err
+		}
+		return s.write(current)
+	})
+}
+
+// Append records events.
+func (s *Store) Append(events ...Event) error {
+	return s.Update(func(current *State) error {
+		current.Append(events...)
+		return nil
+	})
+}
+
+// Reset removes the state file.
+func (s *Store) Reset() error {
+	return s.locked(func() error {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", s.path, err)
+		}
+		return nil
+	})
+}
+
+func (s *Store) locked(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock for %s: %v", s.path, err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock %s: %v", s.path, err)
+	}
+	defer unlockFile(lock)
+
+	return fn()
+}
+
+func (s *Store) read() (*State, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return &State{Version: SchemaVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", s.path, err)
+	}
+	if data, err = upgrade(data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", s.path, err)
+	}
+	current := &State{}
+	if err := json.Unmarshal(data, current); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", s.path, err)
+	}
+	return current, nil
+}
+
+func (s *Store) write(current *State) error {
+	current.Version = SchemaVersion
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", tmp.Name(), err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %v", s.path, err)
+	}
+	return nil
+}
+
+var defaultStore = Open(DefaultFile)
+
+// Default is the store of DefaultFile, shared by the whole process.
+func Default() *Store {
+	return defaultStore
+}
@@ -0,0 +1,173 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// A version 1 file, as benchy wrote it before the journal.
+const countersV1 = `{
+  "scenario1_executed": true,
+  "scenario2_executed": false,
+  "scenario3_executed": true,
+  "alice_transactions_sent": 3,
+  "bob_eth_received": 0.30000000000000004,
+  "cassandra_transactions_sent": 2,
+  "alice_has_restarted": true
+}`
+
+func TestMigrateCounters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(countersV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := Open(path)
+
+	migrated, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated.Version != SchemaVersion {
+		t.Errorf("got version %d, want %d", migrated.Version, SchemaVersion)
+	}
+	if migrated.TxSent("alice") != 3 || migrated.TxSent("Cassandra") != 2 {
+		t.Errorf("got %d transactions from alice and %d from cassandra, want 3 and 2",
+			migrated.TxSent("alice"), migrated.TxSent("cassandra"))
+	}
+	if received := migrated.Received("bob"); received.Cmp(fromETH(3, 10)) != 0 {
+		t.Errorf("bob received %v wei, want 0.3 ETH", received)
+	}
+	for scenario, want := range map[int]bool{1: true, 2: false, 3: true} {
+		if migrated.ScenarioCompleted(scenario) != want {
+			t.Errorf("scenario %d: got completed %v, want %v", scenario, !want, want)
+		}
+		// The counters recorded no hash, so nothing proves the scenarios ran
+		if sent := migrated.Transactions(scenario); len(sent) != 0 {
+			t.Errorf("scenario %d: got transactions %+v from the counters", scenario, sent)
+		}
+	}
+	if !migrated.Restarted("alice") {
+		t.Error("alice's restart was lost")
+	}
+
+	// Loading migrates in memory; the first update writes version 2
+	if err := store.Append(NodeSynced("alice")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved State
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Version != SchemaVersion {
+		t.Errorf("the file was saved as version %d, want %d", saved.Version, SchemaVersion)
+	}
+	current, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current.Events) != len(migrated.Events)+1 || current.Restarted("alice") {
+		t.Errorf("got %d events, want the migrated ones and alice synced", len(current.Events))
+	}
+}
+
+func TestNewerSchemaRefused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	newer := fmt.Sprintf(`{"version": %d, "events": []}`, SchemaVersion+1)
+	if err := os.WriteFile(path, []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := Open(path)
+	if _, err := store.Load(); err == nil {
+		t.Fatal("a file from a newer benchy was loaded")
+	}
+	if err := store.Append(NodeSynced("alice")); err == nil {
+		t.Fatal("a file from a newer benchy was rewritten")
+	}
+	if data, _ := os.ReadFile(path); string(data) != newer {
+		t.Errorf("the newer file was changed:\n%s", data)
+	}
+}
+
+// TestTruncatedTempFile leaves the temporary file of an interrupted write
+// next to the state, which must not be read or stop the next update.
+func TestTruncatedTempFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultFile)
+	store := Open(path)
+	if err := store.Append(ScenarioCompleted(1)); err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(dir, DefaultFile+".tmp-interrupted")
+	if err := os.WriteFile(truncated, []byte(`{"version": 2, "events": [{"type": "tx_`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !current.ScenarioCompleted(1) {
+		t.Error("the state lost the events saved before the interrupted write")
+	}
+	if err := store.Append(ScenarioCompleted(2)); err != nil {
+		t.Fatal(err)
+	}
+	if current, err = store.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !current.ScenarioCompleted(1) || !current.ScenarioCompleted(2) {
+		t.Errorf("got events %+v, want both scenarios", current.Events)
+	}
+
+	// A write is only visible once complete, so nothing else is left behind
+	matches, err := filepath.Glob(filepath.Join(dir, DefaultFile+".tmp-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0] != truncated {
+		t.Errorf("got temporary files %v, want only the interrupted one", matches)
+	}
+}
+
+func TestConcurrentUpdatesOfAStore(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), DefaultFile))
+
+	const updates = 50
+	var wg sync.WaitGroup
+	for _, node := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				if err := store.Append(TxSent(node, "cassandra", nil, "")); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	current, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice, bob := current.TxSent("alice"), current.TxSent("bob"); alice != updates || bob != updates {
+		t.Errorf("got %d transactions from alice and %d from bob, want %d each", alice, bob, updates)
+	}
+}
+
+// fromETH returns numerator/denominator ETH in wei.
+func fromETH(numerator, denominator int64) *big.Int {
+	wei := new(big.Int).Mul(big.NewInt(numerator), big.NewInt(1e18))
+	return wei.Quo(wei, big.NewInt(denominator))
+}
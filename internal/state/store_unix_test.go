@@ -0,0 +1,90 @@
+//go:build unix
+
+package state
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Stores of separate processes only share the file lock, which needs flock.
+
+func TestConcurrentStores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	// Two stores on one file only share the file lock, as two processes do
+	stores := []*Store{Open(path), Open(path)}
+
+	const updates = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, len(stores)*updates)
+	for i, store := range stores {
+		wg.Add(1)
+		go func(node string, store *Store) {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				errs <- store.Append(TxSent(node, "bob", nil, ""))
+			}
+		}("node"+strconv.Itoa(i), store)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current, err := Open(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range stores {
+		if sent := current.TxSent("node" + strconv.Itoa(i)); sent != updates {
+			t.Errorf("node%d: got %d transactions, want %d", i, sent, updates)
+		}
+	}
+}
+
+// TestConcurrentProcesses runs the test binary twice, each process
+// appending to the same file.
+func TestConcurrentProcesses(t *testing.T) {
+	if path := os.Getenv("BENCHY_STATE_HELPER"); path != "" {
+		for i := 0; i < 50; i++ {
+			if err := Open(path).Append(TxSent(os.Getenv("BENCHY_STATE_NODE"), "bob", nil, "")); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		os.Exit(0)
+	}
+
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	var helpers []*exec.Cmd
+	for _, node := range []string{"alice", "bob"} {
+		helper := exec.Command(os.Args[0], "-test.run=^TestConcurrentProcesses$")
+		helper.Env = append(os.Environ(), "BENCHY_STATE_HELPER="+path, "BENCHY_STATE_NODE="+node)
+		helper.Stderr = os.Stderr
+		if err := helper.Start(); err != nil {
+			t.Fatal(err)
+		}
+		helpers = append(helpers, helper)
+	}
+	for _, helper := range helpers {
+		if err := helper.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current, err := Open(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice, bob := current.TxSent("alice"), current.TxSent("bob"); alice != 50 || bob != 50 {
+		t.Errorf("got %d transactions from alice and %d from bob, want 50 each", alice, bob)
+	}
+}
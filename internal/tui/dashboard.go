@@ -0,0 +1,410 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"benchy/internal/monitor"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	historySize  = 120
+	maxEvents    = 200
+	eventLogRows = 8
+)
+
+// NodeController stops and starts node containers from the dashboard.
+type NodeController interface {
+	StopNode(nodeName string) error
+	StartNode(nodeName string) error
+}
+
+type view int
+
+const (
+	viewNodes view = iota
+	viewMempool
+)
+
+type tickMsg time.Time
+
+type eventMsg monitor.LiveEvent
+
+type actionMsg struct {
+	text string
+	err  error
+}
+
+type mempoolMsg struct {
+	node string
+	txs  []monitor.PendingTx
+	err  error
+}
+
+type nodeHistory struct {
+	height []float64
+	cpu    []float64
+	memory []float64
+}
+
+type dashboard struct {
+	live     *monitor.LiveMonitor
+	nodes    NodeController
+	interval time.Duration
+
+	view     view
+	selected int
+	width    int
+	states   map[string]monitor.LiveNodeState
+	history  map[string]*nodeHistory
+	tps      []float64
+	events   []monitor.LiveEvent
+	status   string
+
+	mempoolNode string
+	mempool     []monitor.PendingTx
+	mempoolErr  error
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+	boxStyle      = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+)
+
+// Run starts live monitoring and shows the dashboard until the user quits.
+func Run(live *monitor.LiveMonitor, nodes NodeController, interval time.Duration) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	live.Start(ctx)
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+	model := &dashboard{
+		live:     live,
+		nodes:    nodes,
+		interval: interval,
+		history:  make(map[string]*nodeHistory),
+		width:    120,
+	}
+	for _, name := range monitor.NodeOrder {
+		model.history[name] = &nodeHistory{}
+	}
+	model.sample()
+
+	_, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (d *dashboard) Init() tea.Cmd {
+	return tea.Batch(d.tick(), d.waitForEvent())
+}
+
+func (d *dashboard) tick() tea.Cmd {
+	return tea.Tick(d.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (d *dashboard) waitForEvent() tea.Cmd {
+	return func() tea.Msg { return eventMsg(<-d.live.Events()) }
+}
+
+func (d *dashboard) selectedNode() string {
+	return monitor.NodeOrder[d.selected]
+}
+
+func (d *dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.width = msg.Width
+
+	case tickMsg:
+		d.sample()
+		return d, d.tick()
+
+	case eventMsg:
+		d.events = append(d.events, monitor.LiveEvent(msg))
+		if len(d.events) > maxEvents {
+			d.events = d.events[len(d.events)-maxEvents:]
+		}
+		return d, d.waitForEvent()
+
+	case actionMsg:
+		if msg.err != nil {
+			d.status = fmt.Sprintf("❌ %v", msg.err)
+		} else {
+			d.status = "✅ " + msg.text
+		}
+
+	case mempoolMsg:
+		if msg.node == d.mempoolNode {
+			d.mempool = msg.txs
+			d.mempoolErr = msg.err
+		}
+
+	case tea.KeyMsg:
+		return d.handleKey(msg)
+	}
+	return d, nil
+}
+
+func (d *dashboard) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return d, tea.Quit
+	case "up", "k":
+		if d.view == viewNodes && d.selected > 0 {
+			d.selected--
+		}
+	case "down", "j":
+		if d.view == viewNodes && d.selected < len(monitor.NodeOrder)-1 {
+			d.selected++
+		}
+	case "s":
+		name := d.selectedNode()
+		d.status = fmt.Sprintf("⏳ Stopping %s...", name)
+		return d, func() tea.Msg {
+			return actionMsg{text: name + " stopped", err: d.nodes.StopNode(name)}
+		}
+	case "t":
+		name := d.selectedNode()
+		d.status = fmt.Sprintf("⏳ Starting %s...", name)
+		return d, func() tea.Msg {
+			return actionMsg{text: name + " started", err: d.nodes.StartNode(name)}
+		}
+	case "m", "enter":
+		if d.view == viewMempool {
+			d.view = viewNodes
+			return d, nil
+		}
+		d.view = viewMempool
+		d.mempoolNode = d.selectedNode()
+		d.mempool = nil
+		d.mempoolErr = nil
+		return d, d.loadMempool(d.mempoolNode)
+	case "r":
+		if d.view == viewMempool {
+			return d, d.loadMempool(d.mempoolNode)
+		}
+	case "esc":
+		d.view = viewNodes
+	}
+	return d, nil
+}
+
+func (d *dashboard) loadMempool(name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		txs, err := monitor.GetTxPoolContent(ctx, name)
+		return mempoolMsg{node: name, txs: txs, err: err}
+	}
+}
+
+// sample appends the current state of every node to the chart histories.
+func (d *dashboard) sample() {
+	d.states = d.live.Snapshot()
+
+	networkTPS := 0.0
+	for name, history := range d.history {
+		state := d.states[name]
+		history.height = appendSample(history.height, float64(state.BlockNumber))
+		history.cpu = appendSample(history.cpu, state.CPUUsage)
+		history.memory = appendSample(history.memory, float64(state.MemoryBytes)/(1<<20))
+		if state.Online && state.TPS() > networkTPS {
+			networkTPS = state.TPS()
+		}
+	}
+	d.tps = appendSample(d.tps, networkTPS)
+}
+
+func appendSample(values []float64, value float64) []float64 {
+	values = append(values, value)
+	if len(values) > historySize {
+		values = values[len(values)-historySize:]
+	}
+	return values
+}
+
+func (d *dashboard) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("📊 Benchy dashboard — %s", time.Now().Format("15:04:05"))))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("↑/↓ select  s stop  t start  m mempool  esc back  q quit"))
+	b.WriteString("\n\n")
+
+	if d.view == viewMempool {
+		b.WriteString(d.renderMempool())
+	} else {
+		b.WriteString(d.renderNodes())
+		b.WriteString("\n")
+		b.WriteString(d.renderCharts())
+	}
+
+	b.WriteString("\n")
+	b.WriteString(d.renderEvents())
+	if d.status != "" {
+		b.WriteString("\n" + d.status)
+	}
+	return b.String()
+}
+
+func (d *dashboard) renderNodes() string {
+	var rows []string
+	rows = append(rows, fmt.Sprintf("%-12s %-11s %-6s %-5s %-9s %-6s %-6s %-7s %-10s %-7s",
+		"Node", "Client", "Status", "Feed", "Block", "Age", "Peers", "CPU%", "Memory", "TxPool"))
+
+	for i, name := range monitor.NodeOrder {
+		state, ok := d.states[name]
+		if !ok {
+			continue
+		}
+		status := "OFF"
+		if state.Online {
+			status = "ON"
+		}
+		age := "-"
+		if !state.LastHeadAt.IsZero() {
+			age = fmt.Sprintf("%ds", int(time.Since(state.LastHeadAt).Seconds()))
+		}
+		row := fmt.Sprintf("%-12s %-11s %-6s %-5s #%-8d %-6s %-6d %6.1f%% %-10s %-7d",
+			state.Name, state.Client, status, state.Source, state.BlockNumber, age,
+			state.PeerCount, state.CPUUsage, formatMiB(state.MemoryBytes), state.TxPoolSize)
+		if i == d.selected {
+			row = selectedStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+
+	return boxStyle.Render(strings.Join(rows, "\n"))
+}
+
+func (d *dashboard) renderCharts() string {
+	name := d.selectedNode()
+	history := d.history[name]
+	width := d.width - 30
+	if width < 10 {
+		width = 10
+	}
+
+	lines := []string{
+		titleStyle.Render(strings.Title(name)),
+		chartLine("Block height", history.height, width, "%.0f"),
+		chartLine("CPU %", history.cpu, width, "%.1f"),
+		chartLine("Memory MiB", history.memory, width, "%.0f"),
+		chartLine("Network TPS", d.tps, width, "%.1f"),
+	}
+	return boxStyle.Render(strings.Join(lines, "\n"))
+}
+
+func chartLine(label string, values []float64, width int, format string) string {
+	last := "-"
+	if len(values) > 0 {
+		last = fmt.Sprintf(format, values[len(values)-1])
+	}
+	return fmt.Sprintf("%-13s %s %s", label, sparkline(values, width), last)
+}
+
+func (d *dashboard) renderMempool() string {
+	var rows []string
+	rows = append(rows, titleStyle.Render(fmt.Sprintf("Mempool of %s (r refresh)", d.mempoolNode)))
+
+	switch {
+	case d.mempoolErr != nil:
+		rows = append(rows, fmt.Sprintf("❌ %v", d.mempoolErr))
+	case d.mempool == nil:
+		rows = append(rows, "⏳ Loading...")
+	case len(d.mempool) == 0:
+		rows = append(rows, "Empty")
+	default:
+		rows = append(rows, fmt.Sprintf("%-8s %-12s %-6s %-12s %-14s %-12s",
+			"Pool", "From", "Nonce", "To", "Value (ETH)", "Fee (gwei)"))
+		for _, tx := range d.mempool {
+			pool := "pending"
+			if tx.Queued {
+				pool = "queued"
+			}
+			to := "create"
+			if tx.To != nil {
+				to = tx.To.Hex()[:10] + ".."
+			}
+			rows = append(rows, fmt.Sprintf("%-8s %-12s %-6d %-12s %-14s %-12s",
+				pool, tx.From.Hex()[:10]+"..", tx.Nonce, to, formatUnits(tx.Value, 18), formatUnits(tx.GasPrice, 9)))
+		}
+	}
+	return boxStyle.Render(strings.Join(rows, "\n"))
+}
+
+func (d *dashboard) renderEvents() string {
+	lines := []string{titleStyle.Render("Events")}
+	start := len(d.events) - eventLogRows
+	if start < 0 {
+		start = 0
+	}
+	for _, event := range d.events[start:] {
+		lines = append(lines, fmt.Sprintf("%s %-10s %-9s %s",
+			event.Time.Format("15:04:05"), event.Node, event.Kind, event.Message))
+	}
+	if len(d.events) == 0 {
+		lines = append(lines, dimStyle.Render("No events yet"))
+	}
+	return boxStyle.Render(strings.Join(lines, "\n"))
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the last width values scaled between their min and max.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return strings.Repeat(" ", width)
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		index := 0
+		if max > min {
+			index = int((v - min) / (max - min) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[index])
+	}
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	return b.String()
+}
+
+func formatMiB(bytes uint64) string {
+	if bytes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fMiB", float64(bytes)/(1<<20))
+}
+
+func formatUnits(value *big.Int, decimals int) string {
+	if value == nil {
+		return "-"
+	}
+	f := new(big.Float).SetInt(value)
+	f.Quo(f, big.NewFloat(math.Pow10(decimals)))
+	return f.Text('f', 4)
+}